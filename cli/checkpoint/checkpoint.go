@@ -4,6 +4,8 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
 
 	"github.com/tarantool/tt/cli/cmdcontext"
@@ -18,9 +20,94 @@ type Opts struct {
 	Format     string
 	Replica    []int
 	ShowSystem bool
+	// TimestampFrom and TimestampTo limit row timestamps in seconds since
+	// the epoch: [TimestampFrom, TimestampTo).
+	TimestampFrom float64
+	TimestampTo   float64
+	// Type contains names of the operations to show.
+	Type []string
+	// Key is a primary key value of the changed tuples to show.
+	Key string
+	// Count is true if the number of operations per space and type is
+	// printed instead of the rows.
+	Count bool
 }
 
-// Cat print the contents of .snap/.xlog files.
+// CatNative prints the contents of .snap/.xlog files read without tarantool.
+// The output is the same as the output of Cat.
+func CatNative(w io.Writer, opts Opts, files []string) error {
+	format, ok := formatters[opts.Format]
+	if !ok {
+		return fmt.Errorf("unknown format %q, use yaml, json or lua", opts.Format)
+	}
+	if opts.Count && opts.Format == "lua" {
+		return fmt.Errorf("the lua format is not supported with --count")
+	}
+	filter, err := newRowFilter(opts)
+	if err != nil {
+		return err
+	}
+
+	out := bufio.NewWriter(w)
+	defer out.Flush()
+	if opts.Count {
+		counts := operationCounts{}
+		for _, file := range files {
+			err := readFile(file, func(row *Row) (bool, error) {
+				next, matched := filter.match(row)
+				if matched {
+					counts.add(row)
+				}
+				return next, nil
+			})
+			if err != nil {
+				return fmt.Errorf("failed to read %q: %w", file, err)
+			}
+		}
+		writeCounts(out, opts.Format, counts)
+		return nil
+	}
+
+	for _, file := range files {
+		fmt.Fprintf(out, "• Result of cat: the file \"%s\" is processed below •\n", file)
+		isPrinted := false
+		err := readFile(file, func(row *Row) (bool, error) {
+			next, matched := filter.match(row)
+			if matched {
+				isPrinted = true
+				format(out, row)
+			}
+			return next, nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to read %q: %w", file, err)
+		}
+		if opts.Format == "yaml" && isPrinted {
+			out.WriteString("...\n\n")
+		}
+	}
+	return nil
+}
+
+// CheckFiles reads meta information of the files to check that CatNative
+// supports them. It returns an error wrapping ErrUnsupportedFile if the file
+// format version is not supported.
+func CheckFiles(files []string) error {
+	for _, path := range files {
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %q: %w", path, err)
+		}
+		_, err = NewReader(file)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %q: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Cat print the contents of .snap/.xlog files with tarantool.
 // Returns an error if such occur during reading files.
 func Cat(cmdCtx *cmdcontext.CmdCtx) error {
 	var errbuff bytes.Buffer
@@ -0,0 +1,210 @@
+package checkpoint
+
+import (
+	"bytes"
+	"math"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// defaultOpts returns options of tt cat without flags.
+func defaultOpts(format string) Opts {
+	return Opts{To: math.MaxUint64, TimestampTo: math.Inf(1), Format: format}
+}
+
+func TestCatNative_formats(t *testing.T) {
+	xlog := filepath.Join("testdata", "test.xlog")
+	header := "• Result of cat: the file \"" + xlog + "\" is processed below •\n"
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"yaml", header +
+			"---\n" +
+			"HEADER:\n" +
+			"  lsn: 1\n" +
+			"  replica_id: 1\n" +
+			"  type: UPDATE\n" +
+			"  timestamp: 1650033990.9953\n" +
+			"BODY:\n" +
+			"  space_id: 272\n" +
+			"  index_base: 1\n" +
+			"  key: ['max_id']\n" +
+			"  tuple: [['+', 2, 1]]\n" +
+			"---\n" +
+			"HEADER:\n" +
+			"  lsn: 2\n" +
+			"  replica_id: 1\n" +
+			"  type: INSERT\n" +
+			"  timestamp: 1650033990.997\n" +
+			"BODY:\n" +
+			"  space_id: 280\n" +
+			"  tuple: [512, 1, 'MY_TEST_SPACE', 'memtx', 0, {}, []]\n" +
+			"...\n\n"},
+		{"json", header +
+			`{"HEADER":{"lsn":1,"replica_id":1,"type":"UPDATE","timestamp":1650033990.9953},` +
+			`"BODY":{"space_id":272,"index_base":1,"key":["max_id"],"tuple":[["+",2,1]]}}` +
+			"\n" +
+			`{"HEADER":{"lsn":2,"replica_id":1,"type":"INSERT","timestamp":1650033990.997},` +
+			`"BODY":{"space_id":280,"tuple":[512,1,"MY_TEST_SPACE","memtx",0,{},[]]}}` +
+			"\n"},
+		{"lua", header +
+			`box.space[272]:update({[1] = '\x6d\x61\x78\x5f\x69\x64'}, ` +
+			`{[1] = {[1] = '\x2b', [2] = 2, [3] = 1}})` + "\n" +
+			`box.space[280]:insert({[1] = 512, [2] = 1, ` +
+			`[3] = '\x4d\x59\x5f\x54\x45\x53\x54\x5f\x53\x50\x41\x43\x45', ` +
+			`[4] = '\x6d\x65\x6d\x74\x78', [5] = 0, [6] = {}, [7] = {}})` + "\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.format, func(t *testing.T) {
+			opts := defaultOpts(tc.format)
+			opts.ShowSystem = true
+			var buf bytes.Buffer
+			require.NoError(t, CatNative(&buf, opts, []string{xlog}))
+			assert.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
+
+// lsnRegexp matches LSN of the rows in the YAML output.
+var lsnRegexp = regexp.MustCompile(`lsn: (\d+)`)
+
+func TestCatNative_filters(t *testing.T) {
+	snap := filepath.Join("testdata", "test.snap")
+	xlog := filepath.Join("testdata", "test.xlog")
+	tests := []struct {
+		name     string
+		opts     func(opts *Opts)
+		files    []string
+		expected []string
+	}{
+		{"system spaces are hidden", func(opts *Opts) {}, []string{xlog}, nil},
+		{"lsn", func(opts *Opts) {
+			opts.ShowSystem = true
+			opts.From = 510
+			opts.To = 513
+		}, []string{snap}, []string{"510", "511", "512"}},
+		{"spaces", func(opts *Opts) {
+			opts.Space = []int{320, 296}
+			opts.From = 423
+			opts.To = 513
+		}, []string{snap}, []string{"423", "424", "512"}},
+		{"replica", func(opts *Opts) {
+			opts.ShowSystem = true
+			opts.Replica = []int{1}
+		}, []string{snap, xlog}, []string{"1", "2"}},
+		{"replica to", func(opts *Opts) {
+			opts.ShowSystem = true
+			opts.Replica = []int{1}
+			opts.To = 2
+		}, []string{xlog}, []string{"1"}},
+		{"timestamps", func(opts *Opts) {
+			opts.ShowSystem = true
+			opts.TimestampFrom = 1650033990.996
+			opts.TimestampTo = 1650033991
+		}, []string{xlog}, []string{"2"}},
+		{"type", func(opts *Opts) {
+			opts.ShowSystem = true
+			opts.Type = []string{"UPDATE", "delete"}
+		}, []string{xlog}, []string{"1"}},
+		{"key", func(opts *Opts) {
+			opts.ShowSystem = true
+			opts.Key = "max_id"
+		}, []string{snap, xlog}, []string{"1", "1"}},
+		{"multipart key", func(opts *Opts) {
+			opts.ShowSystem = true
+			opts.Key = "[288, 0]"
+		}, []string{snap}, []string{"323"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := defaultOpts("yaml")
+			tc.opts(&opts)
+			var buf bytes.Buffer
+			require.NoError(t, CatNative(&buf, opts, tc.files))
+			var lsns []string
+			for _, match := range lsnRegexp.FindAllStringSubmatch(buf.String(), -1) {
+				lsns = append(lsns, match[1])
+			}
+			assert.Equal(t, tc.expected, lsns)
+		})
+	}
+}
+
+func TestCatNative_count(t *testing.T) {
+	snap := filepath.Join("testdata", "test.snap")
+	xlog := filepath.Join("testdata", "test.xlog")
+	tests := []struct {
+		name     string
+		opts     func(opts *Opts)
+		expected string
+	}{
+		{"yaml", func(opts *Opts) { opts.Space = []int{272, 280} },
+			"---\n" +
+				"- space_id: 272\n  type: INSERT\n  count: 3\n" +
+				"- space_id: 272\n  type: UPDATE\n  count: 1\n" +
+				"- space_id: 280\n  type: INSERT\n  count: 26\n" +
+				"...\n"},
+		{"json", func(opts *Opts) {
+			opts.Format = "json"
+			opts.Space = []int{320}
+		}, `[{"space_id":320,"type":"INSERT","count":1}]` + "\n"},
+		{"empty yaml", func(opts *Opts) { opts.Type = []string{"upsert"} }, "--- []\n...\n"},
+		{"empty json", func(opts *Opts) {
+			opts.Format = "json"
+			opts.Type = []string{"upsert"}
+		}, "[]\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := defaultOpts("yaml")
+			opts.Count = true
+			tc.opts(&opts)
+			var buf bytes.Buffer
+			require.NoError(t, CatNative(&buf, opts, []string{snap, xlog}))
+			assert.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
+
+func TestCheckFiles(t *testing.T) {
+	snap := filepath.Join("testdata", "test.snap")
+	xlog := filepath.Join("testdata", "test.xlog")
+	require.NoError(t, CheckFiles([]string{snap, xlog}))
+
+	unsupported := writeTestFile(t, "XLOG\n0.14\n\n")
+	err := CheckFiles([]string{snap, unsupported})
+	assert.ErrorIs(t, err, ErrUnsupportedFile)
+	assert.EqualError(t, err, `failed to read "`+unsupported+`": unsupported file format: `+
+		`unknown version "0.14" of XLOG file`)
+
+	err = CheckFiles([]string{"not_exists.xlog"})
+	assert.ErrorContains(t, err, `failed to read "not_exists.xlog": open not_exists.xlog:`)
+}
+
+func TestCatNative_errors(t *testing.T) {
+	var buf bytes.Buffer
+	err := CatNative(&buf, defaultOpts("xml"), []string{"test.xlog"})
+	assert.EqualError(t, err, `unknown format "xml", use yaml, json or lua`)
+
+	opts := defaultOpts("lua")
+	opts.Count = true
+	err = CatNative(&buf, opts, []string{"test.xlog"})
+	assert.EqualError(t, err, "the lua format is not supported with --count")
+
+	opts = defaultOpts("yaml")
+	opts.Type = []string{"select"}
+	err = CatNative(&buf, opts, []string{"test.xlog"})
+	assert.EqualError(t, err,
+		`unknown operation type "select", use insert, replace, update, delete or upsert`)
+
+	err = CatNative(&buf, defaultOpts("yaml"), []string{"not_exists.xlog"})
+	assert.ErrorContains(t, err, `failed to read "not_exists.xlog": open not_exists.xlog:`)
+}
@@ -0,0 +1,310 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apex/log"
+)
+
+// systemSpaceMaxID is a maximum id of the system spaces.
+const systemSpaceMaxID = 511
+
+// keyIndexID is an IPROTO key of the index id in a row body.
+const keyIndexID = 0x11
+
+// indexSpaceID is an id of the _index system space.
+const indexSpaceID = 288
+
+// filterTypes contains types of rows that could be filtered by --type.
+var filterTypes = map[string]uint64{
+	"insert":  typeInsert,
+	"replace": typeReplace,
+	"update":  typeUpdate,
+	"delete":  typeDelete,
+	"upsert":  typeUpsert,
+}
+
+// rowFilter checks that rows match the options.
+type rowFilter struct {
+	opts Opts
+	// types contains the row types to show, all types are shown if it is empty.
+	types map[uint64]bool
+	// key contains the primary key parts to match, it is nil if
+	// the rows are not filtered by the key.
+	key []interface{}
+	// primaryKeys contains numbers of the primary key fields of the spaces
+	// defined by the read _index rows.
+	primaryKeys map[uint64][]int
+	// warned contains spaces with an unknown primary key that are already
+	// reported.
+	warned map[uint64]bool
+}
+
+// newRowFilter creates rowFilter for the options.
+func newRowFilter(opts Opts) (*rowFilter, error) {
+	filter := &rowFilter{
+		opts:        opts,
+		types:       map[uint64]bool{},
+		primaryKeys: map[uint64][]int{},
+		warned:      map[uint64]bool{},
+	}
+	for _, name := range opts.Type {
+		rowType, ok := filterTypes[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown operation type %q, "+
+				"use insert, replace, update, delete or upsert", name)
+		}
+		filter.types[rowType] = true
+	}
+	if opts.Key != "" {
+		filter.key = parseKey(opts.Key)
+	}
+	return filter, nil
+}
+
+// parseKey parses the key parts. The key is a JSON value, an array of
+// values for a multipart key. A value that is not a JSON is a string.
+func parseKey(value string) []interface{} {
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+	var key interface{}
+	if err := decoder.Decode(&key); err != nil {
+		return []interface{}{value}
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return []interface{}{value}
+	}
+	if parts, ok := key.([]interface{}); ok {
+		return parts
+	}
+	return []interface{}{key}
+}
+
+// ParseTimestamp parses a time in RFC 3339 format or a number of seconds
+// since the epoch.
+func ParseTimestamp(value string) (float64, error) {
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return seconds, nil
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timestamp %q, use RFC 3339 format like "+
+			"\"2023-01-31T10:20:30Z\" or seconds since the epoch", value)
+	}
+	return float64(parsed.UnixNano()) / float64(time.Second), nil
+}
+
+// containsID checks that the list contains the id.
+func containsID(list []int, id uint64) bool {
+	for _, item := range list {
+		if item >= 0 && uint64(item) == id {
+			return true
+		}
+	}
+	return false
+}
+
+// match checks that the row matches the options. The first returned
+// value is false if the rest of the file is out of the options: rows of
+// a replica are ordered by LSN.
+func (filter *rowFilter) match(row *Row) (bool, bool) {
+	opts := filter.opts
+	if filter.key != nil {
+		filter.readIndex(row)
+	}
+	spaceID, hasSpaceID := row.SpaceID()
+	_, hasReplicaID := row.Header.Get(keyReplicaID)
+	hasReplica := hasReplicaID && containsID(opts.Replica, row.ReplicaID)
+
+	if len(opts.Replica) == 1 && hasReplica && row.LSN >= opts.To {
+		return false, false
+	}
+	if row.LSN < opts.From || row.LSN >= opts.To {
+		return true, false
+	}
+	if row.Timestamp < opts.TimestampFrom || row.Timestamp >= opts.TimestampTo {
+		return true, false
+	}
+	if len(opts.Space) == 0 && hasSpaceID && spaceID <= systemSpaceMaxID && !opts.ShowSystem {
+		return true, false
+	}
+	if len(opts.Space) > 0 && (!hasSpaceID || !containsID(opts.Space, spaceID)) {
+		return true, false
+	}
+	if len(opts.Replica) > 0 && !hasReplica {
+		return true, false
+	}
+	if len(filter.types) > 0 && !filter.types[row.Type] {
+		return true, false
+	}
+	if filter.key != nil && !filter.matchKey(row) {
+		return true, false
+	}
+	return true, true
+}
+
+// parseIndexParts returns numbers of the indexed fields. Parts are maps
+// with a "field" key or arrays with the field number first. It returns false
+// if the parts are unknown or contain JSON paths.
+func parseIndexParts(value interface{}) ([]int, bool) {
+	parts, ok := value.([]interface{})
+	if !ok || len(parts) == 0 {
+		return nil, false
+	}
+	fields := make([]int, 0, len(parts))
+	for _, part := range parts {
+		var field interface{}
+		switch part := part.(type) {
+		case Map:
+			for _, entry := range part {
+				switch entry.Key {
+				case "field":
+					field = entry.Value
+				case "path":
+					return nil, false
+				}
+			}
+		case []interface{}:
+			if len(part) > 0 {
+				field = part[0]
+			}
+		}
+		fieldNo, ok := field.(uint64)
+		if !ok {
+			return nil, false
+		}
+		fields = append(fields, int(fieldNo))
+	}
+	return fields, true
+}
+
+// readIndex updates the primary key fields of a space if the row changes
+// the primary index in _index.
+func (filter *rowFilter) readIndex(row *Row) {
+	if spaceID, ok := row.SpaceID(); !ok || spaceID != indexSpaceID {
+		return
+	}
+	value, _ := row.Body.Get(keyTuple)
+	if row.Type == typeUpdate || row.Type == typeDelete {
+		value, _ = row.Body.Get(keyKey)
+	}
+	fields, ok := value.([]interface{})
+	if !ok || len(fields) < 2 || fields[1] != uint64(0) {
+		return
+	}
+	spaceID, ok := fields[0].(uint64)
+	if !ok {
+		return
+	}
+
+	delete(filter.primaryKeys, spaceID)
+	if (row.Type == typeInsert || row.Type == typeReplace) && len(fields) > 5 {
+		if parts, ok := parseIndexParts(fields[5]); ok {
+			filter.primaryKeys[spaceID] = parts
+		}
+	}
+}
+
+// tupleKey returns the primary key of the tuple. The first fields are
+// considered to be the key if the primary index of the space is unknown.
+func (filter *rowFilter) tupleKey(spaceID uint64, tuple []interface{}) ([]interface{}, bool) {
+	parts, ok := filter.primaryKeys[spaceID]
+	if !ok {
+		if !filter.warned[spaceID] {
+			filter.warned[spaceID] = true
+			log.Warnf("The primary index of space %d is not found in _index, "+
+				"the first tuple fields are compared with the key.", spaceID)
+		}
+		return tuple, true
+	}
+
+	key := make([]interface{}, len(parts))
+	for i, fieldNo := range parts {
+		if fieldNo >= len(tuple) {
+			return nil, false
+		}
+		key[i] = tuple[fieldNo]
+	}
+	return key, true
+}
+
+// matchKey checks that the primary key of the changed tuple starts with
+// the key parts. Updates and deletes by secondary indexes do not match.
+func (filter *rowFilter) matchKey(row *Row) bool {
+	spaceID, _ := row.SpaceID()
+	var key []interface{}
+	switch row.Type {
+	case typeInsert, typeReplace, typeUpsert:
+		value, _ := row.Body.Get(keyTuple)
+		tuple, ok := value.([]interface{})
+		if !ok {
+			return false
+		}
+		if key, ok = filter.tupleKey(spaceID, tuple); !ok {
+			return false
+		}
+	case typeUpdate, typeDelete:
+		if indexID, ok := row.Body.Get(keyIndexID); ok && indexID != uint64(0) {
+			return false
+		}
+		value, _ := row.Body.Get(keyKey)
+		key, _ = value.([]interface{})
+	default:
+		return false
+	}
+
+	if len(key) < len(filter.key) {
+		return false
+	}
+	for i, part := range filter.key {
+		if !keyPartEqual(key[i], part) {
+			return false
+		}
+	}
+	return true
+}
+
+// keyPartEqual checks that the tuple field equals to the parsed key part.
+func keyPartEqual(field interface{}, part interface{}) bool {
+	switch part := part.(type) {
+	case json.Number:
+		switch field := field.(type) {
+		case uint64:
+			value, err := strconv.ParseUint(part.String(), 10, 64)
+			return err == nil && value == field
+		case int64:
+			value, err := strconv.ParseInt(part.String(), 10, 64)
+			return err == nil && value == field
+		case float64:
+			value, err := part.Float64()
+			return err == nil && value == field
+		case Ext:
+			text, isNumber, ok := formatExt(field)
+			if !ok || !isNumber {
+				return false
+			}
+			fieldValue, err := strconv.ParseFloat(text, 64)
+			value, partErr := part.Float64()
+			return err == nil && partErr == nil && fieldValue == value
+		}
+	case string:
+		switch field := field.(type) {
+		case string:
+			return field == part
+		case Ext:
+			text, _, ok := formatExt(field)
+			return ok && text == part
+		}
+	case bool:
+		value, ok := field.(bool)
+		return ok && value == part
+	case nil:
+		return field == nil
+	}
+	return false
+}
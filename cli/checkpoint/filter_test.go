@@ -0,0 +1,141 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected []interface{}
+	}{
+		{"1", []interface{}{json.Number("1")}},
+		{`"a"`, []interface{}{"a"}},
+		{"abc", []interface{}{"abc"}},
+		{"1 2", []interface{}{"1 2"}},
+		{`[1, "a", null]`, []interface{}{json.Number("1"), "a", nil}},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, parseKey(tc.value), tc.value)
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	timestamp, err := ParseTimestamp("1650033990.5")
+	require.NoError(t, err)
+	assert.Equal(t, 1650033990.5, timestamp)
+
+	timestamp, err = ParseTimestamp("2022-04-15T14:46:30.5Z")
+	require.NoError(t, err)
+	assert.Equal(t, 1650033990.5, timestamp)
+
+	timestamp, err = ParseTimestamp("2022-04-15T17:46:30+03:00")
+	require.NoError(t, err)
+	assert.Equal(t, 1650033990.0, timestamp)
+
+	_, err = ParseTimestamp("yesterday")
+	assert.EqualError(t, err, `invalid timestamp "yesterday", use RFC 3339 format like `+
+		`"2023-01-31T10:20:30Z" or seconds since the epoch`)
+}
+
+func TestKeyPartEqual(t *testing.T) {
+	decimal := Ext{extDecimal, []byte{0x01, 0x01, 0x5c}}
+	tests := []struct {
+		field    interface{}
+		part     interface{}
+		expected bool
+	}{
+		{uint64(1), json.Number("1"), true},
+		{uint64(1), json.Number("-1"), false},
+		{int64(-1), json.Number("-1"), true},
+		{1.5, json.Number("1.5"), true},
+		{decimal, json.Number("1.5"), true},
+		{decimal, json.Number("1.50"), true},
+		{decimal, json.Number("2"), false},
+		{"1", json.Number("1"), false},
+		{"a", "a", true},
+		{[]byte("a"), "a", false},
+		{decimal, "1.5", true},
+		{true, true, true},
+		{nil, nil, true},
+		{uint64(0), nil, false},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, keyPartEqual(tc.field, tc.part), "%v == %v",
+			tc.field, tc.part)
+	}
+}
+
+func TestRowFilter_keyIndex(t *testing.T) {
+	filter, err := newRowFilter(Opts{Key: "1"})
+	require.NoError(t, err)
+
+	row := &Row{Type: typeDelete, Body: Map{{uint64(keyKey), []interface{}{uint64(1)}}}}
+	assert.True(t, filter.matchKey(row))
+	row.Body = append(row.Body, MapEntry{uint64(keyIndexID), uint64(1)})
+	assert.False(t, filter.matchKey(row))
+}
+
+func TestRowFilter_primaryKey(t *testing.T) {
+	filter, err := newRowFilter(Opts{Key: "10"})
+	require.NoError(t, err)
+
+	insert := &Row{Type: typeInsert, Body: Map{
+		{uint64(keySpaceID), uint64(512)},
+		{uint64(keyTuple), []interface{}{"a", uint64(10)}},
+	}}
+	// The first fields are compared until the primary index is known.
+	assert.False(t, filter.matchKey(insert))
+	assert.True(t, filter.warned[512])
+
+	indexRow := func(rowType uint64, indexID uint64, parts interface{}) *Row {
+		return &Row{Type: rowType, Body: Map{
+			{uint64(keySpaceID), uint64(indexSpaceID)},
+			{uint64(keyTuple), []interface{}{uint64(512), indexID, "pk", "tree",
+				Map{}, parts}},
+		}}
+	}
+	filter.readIndex(indexRow(typeInsert, 0,
+		[]interface{}{Map{{"field", uint64(1)}, {"type", "unsigned"}}}))
+	assert.Equal(t, []int{1}, filter.primaryKeys[512])
+	assert.True(t, filter.matchKey(insert))
+
+	// Secondary indexes are ignored, array parts are supported.
+	filter.readIndex(indexRow(typeInsert, 1, []interface{}{[]interface{}{uint64(0), "str"}}))
+	assert.Equal(t, []int{1}, filter.primaryKeys[512])
+	filter.readIndex(indexRow(typeReplace, 0, []interface{}{[]interface{}{uint64(0), "str"}}))
+	assert.Equal(t, []int{0}, filter.primaryKeys[512])
+	assert.False(t, filter.matchKey(insert))
+
+	filter.readIndex(&Row{Type: typeDelete, Body: Map{
+		{uint64(keySpaceID), uint64(indexSpaceID)},
+		{uint64(keyKey), []interface{}{uint64(512), uint64(0)}},
+	}})
+	assert.NotContains(t, filter.primaryKeys, uint64(512))
+}
+
+func TestParseIndexParts(t *testing.T) {
+	cases := []struct {
+		parts    interface{}
+		expected []int
+		ok       bool
+	}{
+		{[]interface{}{Map{{"field", uint64(2)}}, Map{{"field", uint64(0)}}}, []int{2, 0}, true},
+		{[]interface{}{[]interface{}{uint64(1), "unsigned"}}, []int{1}, true},
+		{[]interface{}{Map{{"field", uint64(0)}, {"path", "a.b"}}}, nil, false},
+		{[]interface{}{}, nil, false},
+		{"parts", nil, false},
+	}
+
+	for _, tc := range cases {
+		parts, ok := parseIndexParts(tc.parts)
+		assert.Equal(t, tc.ok, ok, "%v", tc.parts)
+		assert.Equal(t, tc.expected, parts, "%v", tc.parts)
+	}
+}
@@ -0,0 +1,572 @@
+package checkpoint
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// typeNames contains names of the row types.
+var typeNames = map[uint64]string{
+	1:  "SELECT",
+	2:  "INSERT",
+	3:  "REPLACE",
+	4:  "UPDATE",
+	5:  "DELETE",
+	6:  "CALL_16",
+	7:  "AUTH",
+	8:  "EVAL",
+	9:  "UPSERT",
+	10: "CALL",
+	11: "EXECUTE",
+	12: "NOP",
+	13: "PREPARE",
+	14: "BEGIN",
+	15: "COMMIT",
+	16: "ROLLBACK",
+	30: "RAFT",
+	31: "PROMOTE",
+	32: "DEMOTE",
+	40: "CONFIRM",
+	41: "ROLLBACK",
+}
+
+// maxDMLType is a maximum type of rows with named body keys.
+const maxDMLType = 16
+
+// headerKeyNames contains names of the row header keys.
+var headerKeyNames = map[uint64]string{
+	0x00: "type",
+	0x01: "sync",
+	0x02: "replica_id",
+	0x03: "lsn",
+	0x04: "timestamp",
+	0x05: "schema_version",
+	0x06: "server_version",
+	0x07: "group_id",
+	0x08: "tsn",
+	0x09: "flags",
+	0x0a: "stream_id",
+}
+
+// bodyKeyNames contains names of the row body keys.
+var bodyKeyNames = map[uint64]string{
+	0x10: "space_id",
+	0x11: "index_id",
+	0x12: "limit",
+	0x13: "offset",
+	0x14: "iterator",
+	0x15: "index_base",
+	0x20: "key",
+	0x21: "tuple",
+	0x22: "function_name",
+	0x23: "user_name",
+	0x24: "instance_uuid",
+	0x25: "cluster_uuid",
+	0x26: "vclock",
+	0x27: "expr",
+	0x28: "operations",
+	0x2b: "options",
+}
+
+// Types of the msgpack extensions.
+const (
+	extDecimal  = 1
+	extUUID     = 2
+	extDatetime = 4
+)
+
+// formatters print a row in the output formats.
+var formatters = map[string]func(w *bufio.Writer, row *Row){
+	"yaml": writeYAMLRow,
+	"json": writeJSONRow,
+	"lua":  writeLuaRow,
+}
+
+// headerEntries returns the row header with named keys. The type is
+// replaced with its name, the main fields go first.
+func headerEntries(row *Row) Map {
+	var header Map
+	for _, key := range []uint64{keyLSN, keyReplicaID, keyType, keyTimestamp} {
+		if value, ok := row.Header.Get(key); ok {
+			if key == keyType {
+				if name, ok := typeNames[row.Type]; ok {
+					value = name
+				}
+			}
+			header = append(header, MapEntry{headerKeyNames[key], value})
+		}
+	}
+	for _, entry := range row.Header {
+		key, ok := entry.Key.(uint64)
+		if ok && (key == keyLSN || key == keyReplicaID || key == keyType ||
+			key == keyTimestamp) {
+			continue
+		}
+		header = append(header, MapEntry{namedKey(entry.Key, headerKeyNames), entry.Value})
+	}
+	return header
+}
+
+// bodyEntries returns the row body with named keys. Keys of the rows that
+// are not data changes are left as is.
+func bodyEntries(row *Row) Map {
+	body := make(Map, 0, len(row.Body))
+	for _, entry := range row.Body {
+		key := entry.Key
+		if row.Type <= maxDMLType {
+			key = namedKey(key, bodyKeyNames)
+		}
+		body = append(body, MapEntry{key, entry.Value})
+	}
+	return body
+}
+
+// namedKey returns a name of the key if it is known.
+func namedKey(key interface{}, names map[uint64]string) interface{} {
+	if id, ok := key.(uint64); ok {
+		if name, ok := names[id]; ok {
+			return name
+		}
+	}
+	return key
+}
+
+// formatNumber formats a float number like Lua does.
+func formatNumber(value float64) string {
+	switch {
+	case math.IsNaN(value):
+		return "nan"
+	case math.IsInf(value, 1):
+		return "inf"
+	case math.IsInf(value, -1):
+		return "-inf"
+	}
+	return strconv.FormatFloat(value, 'g', 14, 64)
+}
+
+// formatExt returns a text of the extension value and true if it is
+// a number. Returns false as the last value if the extension is unknown.
+func formatExt(ext Ext) (string, bool, bool) {
+	switch ext.Type {
+	case extDecimal:
+		if text, ok := formatDecimal(ext.Data); ok {
+			return text, true, true
+		}
+	case extUUID:
+		if len(ext.Data) == 16 {
+			d := ext.Data
+			return fmt.Sprintf("%x-%x-%x-%x-%x", d[0:4], d[4:6], d[6:8], d[8:10], d[10:16]),
+				false, true
+		}
+	case extDatetime:
+		if len(ext.Data) == 8 || len(ext.Data) == 16 {
+			seconds := int64(binary.LittleEndian.Uint64(ext.Data))
+			var nsec, offset int64
+			if len(ext.Data) == 16 {
+				nsec = int64(int32(binary.LittleEndian.Uint32(ext.Data[8:])))
+				offset = int64(int16(binary.LittleEndian.Uint16(ext.Data[12:])))
+			}
+			zone := time.FixedZone("", int(offset)*60)
+			return time.Unix(seconds, nsec).In(zone).Format(time.RFC3339Nano), false, true
+		}
+	}
+	return "", false, false
+}
+
+// formatDecimal formats a decimal encoded as a scale followed by packed BCD
+// digits with a sign nibble.
+func formatDecimal(data []byte) (string, bool) {
+	value, digits, err := decodeMsgpack(data)
+	if err != nil || len(digits) == 0 {
+		return "", false
+	}
+	var scale int64
+	switch v := value.(type) {
+	case uint64:
+		scale = int64(v)
+	case int64:
+		scale = v
+	default:
+		return "", false
+	}
+
+	var text []byte
+	for _, b := range digits {
+		text = append(text, '0'+b>>4, '0'+b&0x0f)
+	}
+	sign := text[len(text)-1] - '0'
+	text = text[:len(text)-1]
+	for _, digit := range text {
+		if digit > '9' {
+			return "", false
+		}
+	}
+	number := strings.TrimLeft(string(text), "0")
+	if scale > 0 {
+		if int64(len(number)) <= scale {
+			number = strings.Repeat("0", int(scale)-len(number)+1) + number
+		}
+		point := len(number) - int(scale)
+		number = number[:point] + "." + number[point:]
+	} else if scale < 0 && number != "" {
+		number += strings.Repeat("0", int(-scale))
+	}
+	if number == "" {
+		number = "0"
+	}
+	if sign == 0x0b || sign == 0x0d {
+		number = "-" + number
+	}
+	return number, true
+}
+
+// yamlPlainString matches strings that could be written in YAML without quotes.
+var yamlPlainString = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_./-]*$`)
+
+// yamlKeywords contains plain strings with a special meaning in YAML.
+var yamlKeywords = map[string]bool{
+	"y": true, "n": true, "yes": true, "no": true, "on": true, "off": true,
+	"true": true, "false": true, "null": true,
+}
+
+// writeYAMLString writes the string plain if it is possible outside of
+// flow collections, single quoted if it is printable and double quoted
+// otherwise.
+func writeYAMLString(w *bufio.Writer, value string, flow bool) {
+	if !flow && yamlPlainString.MatchString(value) &&
+		!yamlKeywords[strings.ToLower(value)] {
+		w.WriteString(value)
+		return
+	}
+	for _, r := range value {
+		if !strconv.IsPrint(r) {
+			w.WriteString(strconv.Quote(value))
+			return
+		}
+	}
+	w.WriteString("'" + strings.ReplaceAll(value, "'", "''") + "'")
+}
+
+// writeYAMLValue writes the value, collections are written in the flow style.
+func writeYAMLValue(w *bufio.Writer, value interface{}, flow bool) {
+	switch v := value.(type) {
+	case nil:
+		w.WriteString("null")
+	case bool:
+		w.WriteString(strconv.FormatBool(v))
+	case uint64:
+		w.WriteString(strconv.FormatUint(v, 10))
+	case int64:
+		w.WriteString(strconv.FormatInt(v, 10))
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			w.WriteString(".nan")
+		case math.IsInf(v, 0):
+			w.WriteString(strings.Replace(formatNumber(v), "inf", ".inf", 1))
+		default:
+			w.WriteString(formatNumber(v))
+		}
+	case string:
+		if !utf8.ValidString(v) {
+			writeYAMLValue(w, []byte(v), flow)
+			return
+		}
+		writeYAMLString(w, v, flow)
+	case []byte:
+		w.WriteString("!!binary " + base64.StdEncoding.EncodeToString(v))
+	case []interface{}:
+		w.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+			writeYAMLValue(w, item, true)
+		}
+		w.WriteByte(']')
+	case Map:
+		w.WriteByte('{')
+		for i, entry := range v {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+			writeYAMLValue(w, entry.Key, true)
+			w.WriteString(": ")
+			writeYAMLValue(w, entry.Value, true)
+		}
+		w.WriteByte('}')
+	case Ext:
+		text, isNumber, ok := formatExt(v)
+		switch {
+		case !ok:
+			writeYAMLValue(w, v.Data, flow)
+		case isNumber:
+			w.WriteString(text)
+		default:
+			writeYAMLString(w, text, flow)
+		}
+	}
+}
+
+// writeYAMLSection writes the entries as a block mapping under the name.
+func writeYAMLSection(w *bufio.Writer, name string, entries Map) {
+	if len(entries) == 0 {
+		w.WriteString(name + ": {}\n")
+		return
+	}
+	w.WriteString(name + ":\n")
+	for _, entry := range entries {
+		w.WriteString("  ")
+		writeYAMLValue(w, entry.Key, false)
+		w.WriteString(": ")
+		writeYAMLValue(w, entry.Value, false)
+		w.WriteString("\n")
+	}
+}
+
+// writeYAMLRow writes the row as a YAML document without the end marker.
+func writeYAMLRow(w *bufio.Writer, row *Row) {
+	w.WriteString("---\n")
+	writeYAMLSection(w, "HEADER", headerEntries(row))
+	if row.Body != nil {
+		writeYAMLSection(w, "BODY", bodyEntries(row))
+	}
+}
+
+// writeJSONString writes the string with JSON escaping.
+func writeJSONString(w *bufio.Writer, value string) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(value)
+	w.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+}
+
+// writeJSONValue writes the value in JSON.
+func writeJSONValue(w *bufio.Writer, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		w.WriteString("null")
+	case bool:
+		w.WriteString(strconv.FormatBool(v))
+	case uint64:
+		w.WriteString(strconv.FormatUint(v, 10))
+	case int64:
+		w.WriteString(strconv.FormatInt(v, 10))
+	case float64:
+		w.WriteString(formatNumber(v))
+	case string:
+		writeJSONString(w, v)
+	case []byte:
+		writeJSONString(w, string(v))
+	case []interface{}:
+		w.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			writeJSONValue(w, item)
+		}
+		w.WriteByte(']')
+	case Map:
+		w.WriteByte('{')
+		for i, entry := range v {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			// Keys of JSON objects are strings only.
+			switch key := entry.Key.(type) {
+			case string:
+				writeJSONString(w, key)
+			case float64:
+				writeJSONString(w, formatNumber(key))
+			default:
+				writeJSONString(w, fmt.Sprint(key))
+			}
+			w.WriteByte(':')
+			writeJSONValue(w, entry.Value)
+		}
+		w.WriteByte('}')
+	case Ext:
+		if text, _, ok := formatExt(v); ok {
+			writeJSONString(w, text)
+		} else {
+			writeJSONString(w, string(v.Data))
+		}
+	}
+}
+
+// writeJSONRow writes the row as a JSON object on a line.
+func writeJSONRow(w *bufio.Writer, row *Row) {
+	record := Map{{"HEADER", headerEntries(row)}}
+	if row.Body != nil {
+		record = append(record, MapEntry{"BODY", bodyEntries(row)})
+	}
+	writeJSONValue(w, record)
+	w.WriteByte('\n')
+}
+
+// writeLuaValue writes the value as a Lua value, strings are written
+// byte by byte.
+func writeLuaValue(w *bufio.Writer, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		w.WriteString("nil")
+	case bool:
+		w.WriteString(strconv.FormatBool(v))
+	case uint64:
+		w.WriteString(strconv.FormatUint(v, 10))
+	case int64:
+		w.WriteString(strconv.FormatInt(v, 10))
+	case float64:
+		w.WriteString(formatNumber(v))
+	case string:
+		writeLuaString(w, []byte(v))
+	case []byte:
+		writeLuaString(w, v)
+	case []interface{}:
+		w.WriteByte('{')
+		for i, item := range v {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+			fmt.Fprintf(w, "[%d] = ", i+1)
+			writeLuaValue(w, item)
+		}
+		w.WriteByte('}')
+	case Map:
+		w.WriteByte('{')
+		for i, entry := range v {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+			w.WriteByte('[')
+			writeLuaValue(w, entry.Key)
+			w.WriteString("] = ")
+			writeLuaValue(w, entry.Value)
+		}
+		w.WriteByte('}')
+	case Ext:
+		if text, _, ok := formatExt(v); ok {
+			w.WriteString(text)
+		} else {
+			writeLuaString(w, v.Data)
+		}
+	}
+}
+
+// writeLuaString writes the string with all bytes escaped.
+func writeLuaString(w *bufio.Writer, value []byte) {
+	w.WriteByte('\'')
+	for _, b := range value {
+		fmt.Fprintf(w, "\\x%02x", b)
+	}
+	w.WriteByte('\'')
+}
+
+// writeLuaRow writes the data change of the row as a Lua call of
+// the space method. Other rows are skipped.
+func writeLuaRow(w *bufio.Writer, row *Row) {
+	spaceID, ok := row.SpaceID()
+	// Both versions of NOP are ignored: without a body and with an empty one.
+	if row.Type == typeNop || row.Body == nil || !ok {
+		return
+	}
+	op := strings.ToLower(typeNames[row.Type])
+	if op == "" {
+		op = strconv.FormatUint(row.Type, 10)
+	}
+	fmt.Fprintf(w, "box.space[%d]:%s(", spaceID, op)
+
+	writeField := func(key uint64) {
+		value, _ := row.Body.Get(key)
+		writeLuaValue(w, value)
+	}
+	switch row.Type {
+	case typeInsert, typeReplace:
+		writeField(keyTuple)
+	case typeDelete:
+		writeField(keyKey)
+	case typeUpdate:
+		writeField(keyKey)
+		w.WriteString(", ")
+		writeField(keyTuple)
+	case typeUpsert:
+		writeField(keyTuple)
+		w.WriteString(", ")
+		writeField(keyOperations)
+	}
+	w.WriteString(")\n")
+}
+
+// operationKey is a space and a type of counted operations.
+type operationKey struct {
+	spaceID uint64
+	rowType uint64
+}
+
+// operationCounts contains numbers of operations per space and type.
+type operationCounts map[operationKey]uint64
+
+// add counts the row if it changes a space.
+func (counts operationCounts) add(row *Row) {
+	if spaceID, ok := row.SpaceID(); ok {
+		counts[operationKey{spaceID, row.Type}]++
+	}
+}
+
+// sortedKeys returns the counted operations ordered by space and type.
+func (counts operationCounts) sortedKeys() []operationKey {
+	keys := make([]operationKey, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].spaceID != keys[j].spaceID {
+			return keys[i].spaceID < keys[j].spaceID
+		}
+		return keys[i].rowType < keys[j].rowType
+	})
+	return keys
+}
+
+// writeCounts writes the numbers of operations in yaml or json format.
+func writeCounts(w *bufio.Writer, format string, counts operationCounts) {
+	keys := counts.sortedKeys()
+	if format == "json" {
+		w.WriteString("[")
+		for i, key := range keys {
+			if i > 0 {
+				w.WriteString(",")
+			}
+			fmt.Fprintf(w, `{"space_id":%d,"type":`, key.spaceID)
+			writeJSONValue(w, namedKey(key.rowType, typeNames))
+			fmt.Fprintf(w, `,"count":%d}`, counts[key])
+		}
+		w.WriteString("]\n")
+		return
+	}
+
+	if len(keys) == 0 {
+		w.WriteString("--- []\n...\n")
+		return
+	}
+	w.WriteString("---\n")
+	for _, key := range keys {
+		fmt.Fprintf(w, "- space_id: %d\n  type: ", key.spaceID)
+		writeYAMLValue(w, namedKey(key.rowType, typeNames), false)
+		fmt.Fprintf(w, "\n  count: %d\n", counts[key])
+	}
+	w.WriteString("...\n")
+}
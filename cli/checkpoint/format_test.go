@@ -0,0 +1,68 @@
+package checkpoint
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatExt(t *testing.T) {
+	tests := []struct {
+		name     string
+		ext      Ext
+		text     string
+		isNumber bool
+		ok       bool
+	}{
+		{"decimal", Ext{extDecimal, []byte{0x02, 0x12, 0x34, 0x5c}}, "123.45", true, true},
+		{"negative decimal", Ext{extDecimal, []byte{0x03, 0x1d}}, "-0.001", true, true},
+		{"decimal exponent", Ext{extDecimal, []byte{0xfe, 0x1c}}, "100", true, true},
+		{"uuid", Ext{extUUID, []byte{
+			0x8c, 0x27, 0x5d, 0xcd, 0x14, 0x79, 0x4d, 0xef,
+			0xae, 0x31, 0x6e, 0x7a, 0x76, 0x3f, 0xd8, 0x4c,
+		}}, "8c275dcd-1479-4def-ae31-6e7a763fd84c", false, true},
+		{"datetime", Ext{extDatetime, []byte{0x00, 0xcd, 0xb0, 0x63, 0, 0, 0, 0}},
+			"2023-01-01T00:00:00Z", false, true},
+		{"unknown", Ext{100, []byte{0x01}}, "", false, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			text, isNumber, ok := formatExt(tc.ext)
+			assert.Equal(t, tc.text, text)
+			assert.Equal(t, tc.isNumber, isNumber)
+			assert.Equal(t, tc.ok, ok)
+		})
+	}
+}
+
+func TestWriteYAMLValue(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		flow     bool
+		expected string
+	}{
+		{"plain", false, "plain"},
+		{"plain", true, "'plain'"},
+		{"yes", false, "'yes'"},
+		{"it's", false, "'it''s'"},
+		{"a\nb", false, `"a\nb"`},
+		{"\xff", false, "!!binary /w=="},
+		{[]byte("ab"), false, "!!binary YWI="},
+		{1.5, false, "1.5"},
+		{nil, true, "null"},
+		{[]interface{}{uint64(1), int64(-1), true}, false, "[1, -1, true]"},
+		{Map{{"a", []interface{}{}}}, false, "{'a': []}"},
+		{Ext{extDecimal, []byte{0x01, 0x01, 0x5c}}, true, "1.5"},
+	}
+
+	for _, tc := range tests {
+		var buf bytes.Buffer
+		w := bufio.NewWriter(&buf)
+		writeYAMLValue(w, tc.value, tc.flow)
+		w.Flush()
+		assert.Equal(t, tc.expected, buf.String())
+	}
+}
@@ -0,0 +1,210 @@
+package checkpoint
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// maxMsgpackDepth is a maximum nesting level of decoded values.
+const maxMsgpackDepth = 512
+
+// MapEntry is a key-value pair of a decoded map.
+type MapEntry struct {
+	Key   interface{}
+	Value interface{}
+}
+
+// Map is a decoded msgpack map, the order of the keys is kept.
+type Map []MapEntry
+
+// Get returns a value of the unsigned integer key.
+func (m Map) Get(key uint64) (interface{}, bool) {
+	for _, entry := range m {
+		if k, ok := entry.Key.(uint64); ok && k == key {
+			return entry.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Ext is a decoded msgpack extension.
+type Ext struct {
+	Type int8
+	Data []byte
+}
+
+// decodeMsgpack decodes a value of the data. Returns the value and the rest
+// of the data. Values are decoded to nil, bool, int64 (negative integers),
+// uint64, float64, string, []byte, []interface{}, Map and Ext.
+func decodeMsgpack(data []byte) (interface{}, []byte, error) {
+	return decodeMsgpackValue(data, 0)
+}
+
+func decodeMsgpackValue(data []byte, depth int) (interface{}, []byte, error) {
+	if depth > maxMsgpackDepth {
+		return nil, nil, fmt.Errorf("msgpack nesting is too deep")
+	}
+	if len(data) < 1 {
+		return nil, nil, errMsgpackTruncated
+	}
+	code := data[0]
+	data = data[1:]
+
+	switch {
+	case code <= 0x7f:
+		return uint64(code), data, nil
+	case code >= 0xe0:
+		return int64(int8(code)), data, nil
+	case code&0xf0 == 0x80:
+		return decodeMsgpackMap(data, int(code&0x0f), depth)
+	case code&0xf0 == 0x90:
+		return decodeMsgpackArray(data, int(code&0x0f), depth)
+	case code&0xe0 == 0xa0:
+		return decodeMsgpackBytes(data, int(code&0x1f), true)
+	}
+
+	switch code {
+	case 0xc0:
+		return nil, data, nil
+	case 0xc2:
+		return false, data, nil
+	case 0xc3:
+		return true, data, nil
+	case 0xc4, 0xc5, 0xc6, 0xd9, 0xda, 0xdb:
+		size, rest, err := readMsgpackSize(data, code)
+		if err != nil {
+			return nil, nil, err
+		}
+		return decodeMsgpackBytes(rest, size, code >= 0xd9)
+	case 0xc7, 0xc8, 0xc9:
+		size, rest, err := readMsgpackSize(data, code)
+		if err != nil {
+			return nil, nil, err
+		}
+		return decodeMsgpackExt(rest, size)
+	case 0xca:
+		if len(data) < 4 {
+			return nil, nil, errMsgpackTruncated
+		}
+		value := math.Float32frombits(binary.BigEndian.Uint32(data))
+		return float64(value), data[4:], nil
+	case 0xcb:
+		if len(data) < 8 {
+			return nil, nil, errMsgpackTruncated
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(data)), data[8:], nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		size := 1 << (code - 0xcc)
+		if len(data) < size {
+			return nil, nil, errMsgpackTruncated
+		}
+		return readBigEndian(data, size), data[size:], nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (code - 0xd0)
+		if len(data) < size {
+			return nil, nil, errMsgpackTruncated
+		}
+		value := readBigEndian(data, size)
+		// Sign extension of the value.
+		shift := 64 - 8*size
+		signed := int64(value<<shift) >> shift
+		if signed >= 0 {
+			return uint64(signed), data[size:], nil
+		}
+		return signed, data[size:], nil
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return decodeMsgpackExt(data, 1<<(code-0xd4))
+	case 0xdc, 0xdd, 0xde, 0xdf:
+		size, rest, err := readMsgpackSize(data, code)
+		if err != nil {
+			return nil, nil, err
+		}
+		if code >= 0xde {
+			return decodeMsgpackMap(rest, size, depth)
+		}
+		return decodeMsgpackArray(rest, size, depth)
+	}
+	return nil, nil, fmt.Errorf("invalid msgpack code %#x", code)
+}
+
+// errMsgpackTruncated is returned when the data ends in a middle of a value.
+var errMsgpackTruncated = fmt.Errorf("msgpack data is truncated")
+
+// readBigEndian reads an unsigned integer of the size.
+func readBigEndian(data []byte, size int) uint64 {
+	var value uint64
+	for _, b := range data[:size] {
+		value = value<<8 | uint64(b)
+	}
+	return value
+}
+
+// readMsgpackSize reads a size of a string, binary, extension, array or map
+// following the code.
+func readMsgpackSize(data []byte, code byte) (int, []byte, error) {
+	var size int
+	switch code {
+	case 0xc4, 0xc7, 0xd9:
+		size = 1
+	case 0xc5, 0xc8, 0xda, 0xdc, 0xde:
+		size = 2
+	default:
+		size = 4
+	}
+	if len(data) < size {
+		return 0, nil, errMsgpackTruncated
+	}
+	return int(readBigEndian(data, size)), data[size:], nil
+}
+
+func decodeMsgpackBytes(data []byte, size int, isString bool) (interface{}, []byte, error) {
+	if len(data) < size {
+		return nil, nil, errMsgpackTruncated
+	}
+	if isString {
+		return string(data[:size]), data[size:], nil
+	}
+	return append([]byte{}, data[:size]...), data[size:], nil
+}
+
+func decodeMsgpackExt(data []byte, size int) (interface{}, []byte, error) {
+	if len(data) < size+1 {
+		return nil, nil, errMsgpackTruncated
+	}
+	ext := Ext{Type: int8(data[0]), Data: append([]byte{}, data[1:size+1]...)}
+	return ext, data[size+1:], nil
+}
+
+func decodeMsgpackArray(data []byte, size int, depth int) (interface{}, []byte, error) {
+	// Each element takes at least one byte.
+	if len(data) < size {
+		return nil, nil, errMsgpackTruncated
+	}
+	array := make([]interface{}, size)
+	for i := range array {
+		var err error
+		if array[i], data, err = decodeMsgpackValue(data, depth+1); err != nil {
+			return nil, nil, err
+		}
+	}
+	return array, data, nil
+}
+
+func decodeMsgpackMap(data []byte, size int, depth int) (interface{}, []byte, error) {
+	// Each entry takes at least two bytes.
+	if len(data) < 2*size {
+		return nil, nil, errMsgpackTruncated
+	}
+	m := make(Map, size)
+	for i := range m {
+		var err error
+		if m[i].Key, data, err = decodeMsgpackValue(data, depth+1); err != nil {
+			return nil, nil, err
+		}
+		if m[i].Value, data, err = decodeMsgpackValue(data, depth+1); err != nil {
+			return nil, nil, err
+		}
+	}
+	return m, data, nil
+}
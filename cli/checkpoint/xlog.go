@@ -0,0 +1,336 @@
+package checkpoint
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// zstdDecoder decompresses transaction blocks. The size of a decompressed
+// block is limited as the size of a transaction block is.
+var zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderConcurrency(1),
+	zstd.WithDecoderMaxMemory(maxTxSize))
+
+// Markers of the transaction blocks and the end of a file.
+const (
+	rowMarker  = 0xd5ba0bab
+	zrowMarker = 0xd5ba0bba
+	eofMarker  = 0xd510aded
+)
+
+const (
+	// fixheaderSize is a size of a transaction block header.
+	fixheaderSize = 19
+	// maxMetaSize is a maximum size of a file meta.
+	maxMetaSize = 64 << 10
+	// maxTxSize is a maximum size of a transaction block.
+	maxTxSize = 1 << 30
+)
+
+// IPROTO keys of a row header.
+const (
+	keyType      = 0x00
+	keyReplicaID = 0x02
+	keyLSN       = 0x03
+	keyTimestamp = 0x04
+)
+
+// IPROTO keys of a row body.
+const (
+	keySpaceID    = 0x10
+	keyKey        = 0x20
+	keyTuple      = 0x21
+	keyOperations = 0x28
+)
+
+// Row types.
+const (
+	typeInsert  = 2
+	typeReplace = 3
+	typeUpdate  = 4
+	typeDelete  = 5
+	typeUpsert  = 9
+	typeNop     = 12
+)
+
+// ErrUnsupportedFile is returned if the file format version is not supported
+// by the native reader.
+var ErrUnsupportedFile = errors.New("unsupported file format")
+
+// supportedFiletypes contains types of files the reader can read.
+var supportedFiletypes = map[string]bool{"SNAP": true, "XLOG": true, "VYLOG": true}
+
+// supportedVersions contains versions of files the reader can read.
+var supportedVersions = map[string]bool{"0.12": true, "0.13": true}
+
+// castagnoliTable is a table of CRC32C checksums of the transactions.
+var castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Meta is a meta information of a .snap/.xlog file.
+type Meta struct {
+	// Filetype is a type of the file: SNAP, XLOG or VYLOG.
+	Filetype string
+	// Version is a version of the file format.
+	Version string
+	// Fields contains meta fields, like "Instance" or "VClock".
+	Fields map[string]string
+}
+
+// Row is a row of a .snap/.xlog file.
+type Row struct {
+	// Type is a row type: INSERT, REPLACE and so on.
+	Type uint64
+	// ReplicaID is an id of the replica that made the change.
+	ReplicaID uint64
+	// LSN is a log sequence number of the row.
+	LSN uint64
+	// Timestamp is a time of the change in seconds since the epoch.
+	Timestamp float64
+	// Header contains the row header keyed by IPROTO keys.
+	Header Map
+	// Body contains the row body keyed by IPROTO keys, it is nil
+	// if the row has no body.
+	Body Map
+}
+
+// SpaceID returns an id of the space changed by the row.
+func (row *Row) SpaceID() (uint64, bool) {
+	value, ok := row.Body.Get(keySpaceID)
+	if !ok {
+		return 0, false
+	}
+	spaceID, ok := value.(uint64)
+	return spaceID, ok
+}
+
+// Reader reads rows of a .snap/.xlog file.
+type Reader struct {
+	// Meta is a meta information of the file.
+	Meta Meta
+
+	reader *bufio.Reader
+	// offset is an offset of the next transaction block in the file.
+	offset int64
+	// rows contains unread rows of the current transaction.
+	rows []byte
+	// eof is true if the end of the file is reached.
+	eof bool
+}
+
+// NewReader creates Reader and reads the file meta.
+func NewReader(r io.Reader) (*Reader, error) {
+	reader := &Reader{reader: bufio.NewReader(r)}
+	if err := reader.readMeta(); err != nil {
+		return nil, err
+	}
+	return reader, nil
+}
+
+// readMeta reads the text meta that ends with an empty line.
+func (reader *Reader) readMeta() error {
+	var lines []string
+	for {
+		line, err := reader.reader.ReadString('\n')
+		reader.offset += int64(len(line))
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if len(lines) == 0 && !supportedFiletypes[strings.TrimSuffix(line, "\n")] {
+			return fmt.Errorf("unknown file type %q", strings.TrimSuffix(line, "\n"))
+		}
+		if err == io.EOF {
+			return fmt.Errorf("file meta is truncated")
+		}
+		if reader.offset > maxMetaSize {
+			return fmt.Errorf("file meta is too large")
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			break
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) < 2 {
+		return fmt.Errorf("file meta is truncated")
+	}
+
+	reader.Meta = Meta{Filetype: lines[0], Version: lines[1], Fields: map[string]string{}}
+	if !supportedVersions[reader.Meta.Version] {
+		return fmt.Errorf("%w: unknown version %q of %s file", ErrUnsupportedFile,
+			reader.Meta.Version, reader.Meta.Filetype)
+	}
+	for _, line := range lines[2:] {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid file meta line %q", line)
+		}
+		reader.Meta.Fields[parts[0]] = strings.TrimSpace(parts[1])
+	}
+	return nil
+}
+
+// Next returns the next row of the file. Returns io.EOF if there are
+// no more rows.
+func (reader *Reader) Next() (*Row, error) {
+	for len(reader.rows) == 0 {
+		if reader.eof {
+			return nil, io.EOF
+		}
+		if err := reader.readTx(); err != nil {
+			return nil, err
+		}
+	}
+
+	row, rest, err := decodeRow(reader.rows)
+	if err != nil {
+		// The rest of the transaction could not be read.
+		reader.rows = nil
+		return nil, err
+	}
+	reader.rows = rest
+	return row, nil
+}
+
+// readTx reads the next transaction block. A file without the end marker
+// is read to the end: it could be written at the moment.
+func (reader *Reader) readTx() error {
+	offset := reader.offset
+	var fixheader [fixheaderSize]byte
+	n, err := io.ReadFull(reader.reader, fixheader[:4])
+	reader.offset += int64(n)
+	if err == io.EOF {
+		reader.eof = true
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read transaction at offset %d: %w", offset, err)
+	}
+
+	marker := binary.BigEndian.Uint32(fixheader[:])
+	switch marker {
+	case eofMarker:
+		reader.eof = true
+		return nil
+	case rowMarker, zrowMarker:
+	default:
+		return fmt.Errorf("invalid transaction marker %#x at offset %d", marker, offset)
+	}
+
+	n, err = io.ReadFull(reader.reader, fixheader[4:])
+	reader.offset += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to read transaction at offset %d: %w", offset, err)
+	}
+	// The header contains the size, the obsolete checksum of the previous
+	// transaction and the checksum of the data padded to the fixed size.
+	var values [3]uint64
+	rest := fixheader[4:]
+	for i := range values {
+		value, tail, err := decodeMsgpack(rest)
+		if err != nil {
+			return fmt.Errorf("invalid transaction header at offset %d: %s", offset, err)
+		}
+		var ok bool
+		if values[i], ok = value.(uint64); !ok {
+			return fmt.Errorf("invalid transaction header at offset %d", offset)
+		}
+		rest = tail
+	}
+	size, checksum := values[0], values[2]
+	if size > maxTxSize {
+		return fmt.Errorf("transaction at offset %d is too large: %d bytes", offset, size)
+	}
+
+	data := make([]byte, size)
+	n, err = io.ReadFull(reader.reader, data)
+	reader.offset += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to read transaction at offset %d: %w", offset, err)
+	}
+	// Tarantool computes CRC32C without the initial and final inversion.
+	if actual := ^crc32.Update(^uint32(0), castagnoliTable, data); uint64(actual) != checksum {
+		return fmt.Errorf("checksum mismatch of transaction at offset %d", offset)
+	}
+
+	if marker == zrowMarker {
+		if data, err = zstdDecoder.DecodeAll(data, nil); err != nil {
+			return fmt.Errorf("failed to decompress transaction at offset %d: %s", offset, err)
+		}
+	}
+	reader.rows = data
+	return nil
+}
+
+// decodeRow decodes a row header and a body. Returns the row and the rest
+// of the data.
+func decodeRow(data []byte) (*Row, []byte, error) {
+	value, data, err := decodeMsgpack(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid row header: %s", err)
+	}
+	header, ok := value.(Map)
+	if !ok {
+		return nil, nil, fmt.Errorf("row header is not a map")
+	}
+
+	row := &Row{Header: header}
+	for _, entry := range header {
+		key, _ := entry.Key.(uint64)
+		switch key {
+		case keyType:
+			row.Type, _ = entry.Value.(uint64)
+		case keyReplicaID:
+			row.ReplicaID, _ = entry.Value.(uint64)
+		case keyLSN:
+			row.LSN, _ = entry.Value.(uint64)
+		case keyTimestamp:
+			row.Timestamp, _ = entry.Value.(float64)
+		}
+	}
+
+	// A body follows the header in the same transaction. NOP rows have
+	// no body or an empty one.
+	if len(data) == 0 || (row.Type == typeNop && data[0] != 0x80) {
+		return row, data, nil
+	}
+	if value, data, err = decodeMsgpack(data); err != nil {
+		return nil, nil, fmt.Errorf("invalid row body: %s", err)
+	}
+	if row.Body, ok = value.(Map); !ok {
+		return nil, nil, fmt.Errorf("row body is not a map")
+	}
+	return row, data, nil
+}
+
+// readFile calls the callback for each row of the file.
+func readFile(path string, callback func(row *Row) (bool, error)) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader, err := NewReader(file)
+	if err != nil {
+		return err
+	}
+	for {
+		row, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if next, err := callback(row); err != nil || !next {
+			return err
+		}
+	}
+}
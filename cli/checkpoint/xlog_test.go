@@ -0,0 +1,227 @@
+package checkpoint
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const testMeta = "XLOG\n0.13\nVersion: 2.11.0\nInstance: 8fb65242-878b-4dc6-a07b-444ae3decc18\n" +
+	"VClock: {1: 10}\n\n"
+
+// encodeTestRow encodes a row header and a body, the body is omitted if
+// it is nil.
+func encodeTestRow(t *testing.T, header, body map[int]interface{}) []byte {
+	var buf bytes.Buffer
+	encoder := msgpack.NewEncoder(&buf)
+	encoder.SetSortMapKeys(true)
+	require.NoError(t, encoder.Encode(header))
+	if body != nil {
+		require.NoError(t, encoder.Encode(body))
+	}
+	return buf.Bytes()
+}
+
+// encodeTestTx encodes a plain transaction block of the rows.
+func encodeTestTx(rows ...[]byte) []byte {
+	return encodeTestBlock(rowMarker, bytes.Join(rows, nil))
+}
+
+// encodeTestZTx encodes a zstd compressed transaction block of the rows.
+func encodeTestZTx(t *testing.T, rows ...[]byte) []byte {
+	encoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	defer encoder.Close()
+	return encodeTestBlock(zrowMarker, encoder.EncodeAll(bytes.Join(rows, nil), nil))
+}
+
+// encodeTestBlock encodes a transaction block of the data.
+func encodeTestBlock(marker uint32, data []byte) []byte {
+	crc := ^crc32.Update(^uint32(0), castagnoliTable, data)
+
+	// The size, the previous checksum and the checksum are padded with
+	// a string of zeros.
+	fixheader := make([]byte, fixheaderSize)
+	binary.BigEndian.PutUint32(fixheader, marker)
+	fixheader[4] = 0xce
+	binary.BigEndian.PutUint32(fixheader[5:], uint32(len(data)))
+	fixheader[9], fixheader[10] = 0x00, 0xce
+	binary.BigEndian.PutUint32(fixheader[11:], crc)
+	fixheader[15] = 0xa3
+	return append(fixheader, data...)
+}
+
+// writeTestFile writes a file of the transaction blocks.
+func writeTestFile(t *testing.T, meta string, txs ...[]byte) string {
+	path := filepath.Join(t.TempDir(), "test.xlog")
+	data := append([]byte(meta), bytes.Join(txs, nil)...)
+	require.NoError(t, os.WriteFile(path, data, 0644))
+	return path
+}
+
+// eofTx is the end of a file marker.
+var eofTx = []byte{0xd5, 0x10, 0xad, 0xed}
+
+// readTestRows reads all rows of the file.
+func readTestRows(t *testing.T, path string) ([]*Row, error) {
+	var rows []*Row
+	err := readFile(path, func(row *Row) (bool, error) {
+		rows = append(rows, row)
+		return true, nil
+	})
+	return rows, err
+}
+
+func TestReader(t *testing.T) {
+	file, err := os.Open(filepath.Join("testdata", "test.xlog"))
+	require.NoError(t, err)
+	defer file.Close()
+
+	reader, err := NewReader(file)
+	require.NoError(t, err)
+	assert.Equal(t, Meta{
+		Filetype: "XLOG",
+		Version:  "0.13",
+		Fields: map[string]string{
+			"Version":  "2.8.3-0-g01023db",
+			"Instance": "8fb65242-878b-4dc6-a07b-444ae3decc18",
+			"VClock":   "{}",
+		},
+	}, reader.Meta)
+
+	row, err := reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(typeUpdate), row.Type)
+	assert.Equal(t, uint64(1), row.LSN)
+	assert.Equal(t, uint64(1), row.ReplicaID)
+	assert.InDelta(t, 1650033990.995, row.Timestamp, 0.001)
+	spaceID, ok := row.SpaceID()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(272), spaceID)
+	key, _ := row.Body.Get(keyKey)
+	assert.Equal(t, []interface{}{"max_id"}, key)
+
+	row, err = reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(typeInsert), row.Type)
+	assert.Equal(t, uint64(2), row.LSN)
+
+	_, err = reader.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestReader_compressed(t *testing.T) {
+	rows, err := readTestRows(t, filepath.Join("testdata", "test.snap"))
+	require.NoError(t, err)
+	require.Len(t, rows, 515)
+	for i, row := range rows {
+		assert.Equal(t, uint64(i), row.LSN)
+	}
+	tuple, _ := rows[512].Body.Get(keyTuple)
+	assert.Equal(t, []interface{}{uint64(1), "8c275dcd-1479-4def-ae31-6e7a763fd84c"}, tuple)
+}
+
+func TestReader_rows(t *testing.T) {
+	insert := encodeTestRow(t,
+		map[int]interface{}{keyType: typeInsert, keyReplicaID: 1, keyLSN: 1},
+		map[int]interface{}{keySpaceID: 512, keyTuple: []interface{}{1, "a"}})
+	nop := encodeTestRow(t, map[int]interface{}{keyType: typeNop, keyLSN: 2}, nil)
+	oldNop := encodeTestRow(t, map[int]interface{}{keyType: typeNop, keyLSN: 3},
+		map[int]interface{}{})
+	del := encodeTestRow(t,
+		map[int]interface{}{keyType: typeDelete, keyLSN: 4},
+		map[int]interface{}{keySpaceID: 512, keyKey: []interface{}{-1}})
+
+	// The file has no end marker.
+	path := writeTestFile(t, testMeta, encodeTestTx(insert, nop, oldNop), encodeTestTx(del))
+	rows, err := readTestRows(t, path)
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+	assert.Equal(t, uint64(typeInsert), rows[0].Type)
+	assert.Nil(t, rows[1].Body)
+	assert.Equal(t, Map{}, rows[2].Body)
+	assert.Equal(t, uint64(4), rows[3].LSN)
+	key, _ := rows[3].Body.Get(keyKey)
+	assert.Equal(t, []interface{}{int64(-1)}, key)
+}
+
+func TestReader_zrows(t *testing.T) {
+	var rows [][]byte
+	for lsn := 1; lsn <= 100; lsn++ {
+		rows = append(rows, encodeTestRow(t,
+			map[int]interface{}{keyType: typeReplace, keyLSN: lsn},
+			map[int]interface{}{keySpaceID: 512, keyTuple: []interface{}{lsn, "value"}}))
+	}
+
+	path := writeTestFile(t, testMeta, encodeTestZTx(t, rows[:50]...),
+		encodeTestTx(rows[50]), encodeTestZTx(t, rows[51:]...), eofTx)
+	read, err := readTestRows(t, path)
+	require.NoError(t, err)
+	require.Len(t, read, 100)
+	for i, row := range read {
+		assert.Equal(t, uint64(i+1), row.LSN)
+	}
+
+	// The checksum is correct, but the data is not a zstd frame.
+	path = writeTestFile(t, testMeta, encodeTestBlock(zrowMarker, []byte("not zstd")))
+	_, err = readTestRows(t, path)
+	assert.ErrorContains(t, err, "failed to decompress transaction at offset 90:")
+}
+
+func TestReader_errors(t *testing.T) {
+	row := encodeTestRow(t, map[int]interface{}{keyType: typeInsert, keyLSN: 1},
+		map[int]interface{}{keySpaceID: 512, keyTuple: []interface{}{1}})
+	tx := encodeTestTx(row)
+	corrupted := append([]byte{}, tx...)
+	corrupted[len(corrupted)-1] ^= 0xff
+
+	tests := []struct {
+		name     string
+		meta     string
+		txs      [][]byte
+		expected string
+	}{
+		{"file type", "PNG\n0.13\n\n", nil, `unknown file type "PNG"`},
+		{"version", "XLOG\n0.14\n\n", nil,
+			`unsupported file format: unknown version "0.14" of XLOG file`},
+		{"meta", "XLOG\n0.13\n", nil, "file meta is truncated"},
+		{"marker", testMeta, [][]byte{{0xd5, 0xba, 0x0b, 0x00}},
+			"invalid transaction marker 0xd5ba0b00 at offset 90"},
+		{"checksum", testMeta, [][]byte{tx, corrupted},
+			"checksum mismatch of transaction at offset 122"},
+		{"truncated", testMeta, [][]byte{tx[:len(tx)-1]},
+			"failed to read transaction at offset 90: unexpected EOF"},
+		{"row", testMeta, [][]byte{encodeTestTx([]byte{0x91, 0x01})}, "row header is not a map"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := writeTestFile(t, tc.meta, tc.txs...)
+			_, err := readTestRows(t, path)
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+
+	path := writeTestFile(t, "XLOG\n0.14\n\n")
+	_, err := readTestRows(t, path)
+	assert.ErrorIs(t, err, ErrUnsupportedFile)
+}
+
+func TestReader_eof(t *testing.T) {
+	row := encodeTestRow(t, map[int]interface{}{keyType: typeInsert, keyLSN: 1},
+		map[int]interface{}{keySpaceID: 512, keyTuple: []interface{}{1}})
+	// Data after the end marker is ignored.
+	path := writeTestFile(t, testMeta, encodeTestTx(row), eofTx, encodeTestTx(row))
+	rows, err := readTestRows(t, path)
+	require.NoError(t, err)
+	assert.Len(t, rows, 1)
+}
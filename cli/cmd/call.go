@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/connect"
+	"github.com/tarantool/tt/cli/modules"
+	"github.com/tarantool/tt/cli/util"
+)
+
+var (
+	callUser      string
+	callPassword  string
+	callFormat    string
+	callConnFlags connectionFlags
+)
+
+// NewCallCmd creates call command.
+func NewCallCmd() *cobra.Command {
+	var callCmd = &cobra.Command{
+		Use: "call (<APP_NAME> | <APP_NAME:INSTANCE_NAME> | <URI>)" +
+			" <FUNCTION> [<ARG>...] [flags]",
+		Short: "Call a function on the tarantool instance",
+		Long: "Call a global or a stored function on the tarantool instance.\n\n" +
+			"An argument is decoded as JSON if possible, otherwise it is passed as" +
+			" a string:\n\n" +
+			"  tt call app:inst box.space.test:get 1\n" +
+			"  tt call app:inst my_func '{\"key\": \"value\"}' str\n\n" +
+			"The command supports the following environment variables:\n\n" +
+			"* " + usernameEnv + " - specifies a username\n" +
+			"* " + passwordEnv + " - specifies a password\n",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdCtx.CommandName = cmd.Name()
+			err := modules.RunCmd(&cmdCtx, cmd.CommandPath(), &modulesInfo,
+				internalCallModule, args)
+			handleCmdErr(cmd, err)
+		},
+		Args: cobra.MinimumNArgs(2),
+	}
+
+	callCmd.Flags().StringVarP(&callUser, "username", "u", "", "username")
+	callCmd.Flags().StringVarP(&callPassword, "password", "p", "", "password")
+	callCmd.Flags().StringVar(&callFormat, "format", connect.EvalFormatYAML,
+		"output format: "+strings.Join(connect.EvalFormats, ", "))
+	addConnectionFlags(callCmd, &callConnFlags)
+
+	return callCmd
+}
+
+// internalCallModule is a default call module.
+func internalCallModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
+	if util.Find(connect.EvalFormats, callFormat) == -1 {
+		return util.NewArgError(fmt.Sprintf("unsupported format: %s", callFormat))
+	}
+
+	connectCtx := connect.ConnectCtx{
+		Username: callUser,
+		Password: callPassword,
+	}
+	callConnFlags.apply(&connectCtx)
+	newArgs, err := resolveConnectOpts(cmdCtx, cliOpts, &connectCtx, args[:1])
+	if err != nil {
+		return err
+	}
+
+	results, err := connect.CallOnTarget(connectCtx, newArgs[0], args[1],
+		connect.ParseRequestArgs(args[2:]))
+	if err != nil {
+		return err
+	}
+
+	output, err := connect.FormatEvalResults(results, callFormat)
+	if err != nil {
+		return fmt.Errorf("failed to encode the result: %s", err)
+	}
+	// "Println" is used instead of "log..." to print the result without
+	// any decoration.
+	fmt.Println(strings.TrimRight(string(output), "\n"))
+
+	return nil
+}
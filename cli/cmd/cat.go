@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"os"
@@ -19,14 +20,26 @@ import (
 // catFlags contains flags for cat command.
 // Initialized with default values at creation.
 var catFlags = checkpoint.Opts{
-	From:       0,
-	To:         math.MaxUint64,
-	Space:      nil,
-	Format:     "yaml",
-	Replica:    nil,
-	ShowSystem: false,
+	From:        0,
+	To:          math.MaxUint64,
+	Space:       nil,
+	Format:      "yaml",
+	Replica:     nil,
+	ShowSystem:  false,
+	TimestampTo: math.Inf(1),
+	Type:        nil,
+	Key:         "",
+	Count:       false,
 }
 
+// catTimestampFrom and catTimestampTo are the values of --timestamp-from
+// and --timestamp-to flags, they are parsed into catFlags.
+var catTimestampFrom, catTimestampTo string
+
+// catUseTarantool is true if the files are read with tarantool instead
+// of the built-in reader.
+var catUseTarantool bool
+
 // NewCatCmd creates a new cat command.
 func NewCatCmd() *cobra.Command {
 	var catCmd = &cobra.Command{
@@ -52,6 +65,23 @@ func NewCatCmd() *cobra.Command {
 		"Filter the output by replica id. May be passed more than once")
 	catCmd.Flags().BoolVar(&catFlags.ShowSystem, "show-system", catFlags.ShowSystem,
 		"Show the contents of system spaces")
+	catCmd.Flags().StringVar(&catTimestampFrom, "timestamp-from", catTimestampFrom,
+		"Show operations starting from the given time (RFC 3339 or seconds since the epoch)")
+	catCmd.Flags().StringVar(&catTimestampTo, "timestamp-to", catTimestampTo,
+		"Show operations ending with the given time (RFC 3339 or seconds since the epoch)")
+	catCmd.Flags().StringSliceVar(&catFlags.Type, "type", catFlags.Type,
+		"Filter the output by operation type: insert, replace, update, delete or upsert."+
+			" May be passed more than once")
+	catCmd.Flags().StringVar(&catFlags.Key, "key", catFlags.Key,
+		"Filter the output by primary key value, a JSON array for a multipart key."+
+			" Key fields are found in _index rows of the files, otherwise the first"+
+			" tuple fields are compared. Updates and deletes by secondary indexes"+
+			" are not matched")
+	catCmd.Flags().BoolVar(&catFlags.Count, "count", catFlags.Count,
+		"Print the number of operations per space and type instead of the operations")
+	catCmd.Flags().BoolVar(&catUseTarantool, "use-tarantool", catUseTarantool,
+		"Read the files with tarantool instead of the built-in reader. Files"+
+			" unsupported by the built-in reader are read with tarantool anyway")
 
 	return catCmd
 }
@@ -62,6 +92,44 @@ func internalCatModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
 		return fmt.Errorf("it is required to specify at least one .xlog or .snap file")
 	}
 
+	var err error
+	if catTimestampFrom != "" {
+		if catFlags.TimestampFrom, err = checkpoint.ParseTimestamp(catTimestampFrom); err != nil {
+			return err
+		}
+	}
+	if catTimestampTo != "" {
+		if catFlags.TimestampTo, err = checkpoint.ParseTimestamp(catTimestampTo); err != nil {
+			return err
+		}
+	}
+
+	nativeOnly := catTimestampFrom != "" || catTimestampTo != "" || len(catFlags.Type) > 0 ||
+		catFlags.Key != "" || catFlags.Count
+	if !catUseTarantool {
+		// Files unsupported by the built-in reader are read with tarantool.
+		err := checkpoint.CheckFiles(args)
+		if !errors.Is(err, checkpoint.ErrUnsupportedFile) {
+			log.Infof("Running cat with files: %s\n", args)
+			return checkpoint.CatNative(os.Stdout, catFlags, args)
+		}
+		if nativeOnly {
+			return fmt.Errorf("%s, --timestamp-from, --timestamp-to, --type, --key and "+
+				"--count are not supported by tarantool", err)
+		}
+		if cmdCtx.Cli.TarantoolExecutable == "" {
+			return fmt.Errorf("%s, tarantool executable is required to read the file", err)
+		}
+		log.Warnf("%s, the files are read with tarantool", err)
+	}
+	if nativeOnly {
+		return fmt.Errorf("--timestamp-from, --timestamp-to, --type, --key and --count " +
+			"are not supported with --use-tarantool")
+	}
+	if cmdCtx.Cli.TarantoolExecutable == "" {
+		return fmt.Errorf("tarantool executable is not found")
+	}
+
 	// List of files is passed to lua cat script via environment variable in json format.
 	filesJson, err := json.Marshal(args)
 	if err != nil {
@@ -6,12 +6,14 @@ import (
 	"regexp"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/apex/log"
 	"github.com/spf13/cobra"
 	"github.com/tarantool/tt/cli/cmdcontext"
 	"github.com/tarantool/tt/cli/config"
 	"github.com/tarantool/tt/cli/connect"
+	"github.com/tarantool/tt/cli/connector"
 	"github.com/tarantool/tt/cli/modules"
 	"github.com/tarantool/tt/cli/running"
 	"github.com/tarantool/tt/cli/util"
@@ -29,8 +31,43 @@ var (
 	connectFile        string
 	connectLanguage    string
 	connectInteractive bool
+	connectHistory     bool
+	connectReadOnly    bool
+	connectConnFlags   connectionFlags
 )
 
+// instanceTargetUse is a usage string of a connection target argument.
+const instanceTargetUse = "(<APP_NAME> | <APP_NAME:INSTANCE_NAME> | <URI>)"
+
+// connectionFlags contains values of the connection options flags.
+type connectionFlags struct {
+	connectTimeout time.Duration
+	requestTimeout time.Duration
+	retryCount     int
+	retryBackoff   time.Duration
+}
+
+// addConnectionFlags adds the connection options flags to the command.
+func addConnectionFlags(cmd *cobra.Command, flags *connectionFlags) {
+	cmd.Flags().DurationVar(&flags.connectTimeout, "connect-timeout",
+		connector.DefaultConnectTimeout, "timeout for establishing a connection")
+	cmd.Flags().DurationVar(&flags.requestTimeout, "request-timeout", 0,
+		"timeout for a request, 0 - no timeout")
+	cmd.Flags().IntVar(&flags.retryCount, "retries", 0,
+		"number of connection retries after a failed attempt")
+	cmd.Flags().DurationVar(&flags.retryBackoff, "retry-backoff",
+		connector.DefaultRetryBackoff,
+		"delay before the first connection retry, it is doubled for each next retry")
+}
+
+// apply sets the connection options to the connect context.
+func (flags connectionFlags) apply(connectCtx *connect.ConnectCtx) {
+	connectCtx.ConnectTimeout = flags.connectTimeout
+	connectCtx.RequestTimeout = flags.requestTimeout
+	connectCtx.RetryCount = flags.retryCount
+	connectCtx.RetryBackoff = flags.retryBackoff
+}
+
 // NewConnectCmd creates connect command.
 func NewConnectCmd() *cobra.Command {
 	var connectCmd = &cobra.Command{
@@ -59,6 +96,11 @@ func NewConnectCmd() *cobra.Command {
 		connect.DefaultLanguage.String(), `language: lua or sql`)
 	connectCmd.Flags().BoolVarP(&connectInteractive, "interactive", "i",
 		false, `enter interactive mode after executing 'FILE'`)
+	connectCmd.Flags().BoolVar(&connectHistory, "history-per-target", false,
+		`keep a separate commands history for the connection target`)
+	connectCmd.Flags().BoolVar(&connectReadOnly, "read-only", false,
+		`reject SQL statements that modify data or the schema, implies the sql language`)
+	addConnectionFlags(connectCmd, &connectConnFlags)
 
 	return connectCmd
 }
@@ -166,16 +208,28 @@ func resolveConnectOpts(cmdCtx *cmdcontext.CmdCtx, cliOpts *config.CliOpts,
 // internalConnectModule is a default connect module.
 func internalConnectModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
 	connectCtx := connect.ConnectCtx{
-		Username:    connectUser,
-		Password:    connectPassword,
-		SrcFile:     connectFile,
-		Interactive: connectInteractive,
+		Username:         connectUser,
+		Password:         connectPassword,
+		SrcFile:          connectFile,
+		Interactive:      connectInteractive,
+		HistoryPerTarget: connectHistory,
+		ReadOnly:         connectReadOnly,
+		AuditLog:         cliOpts.AuditLog,
 	}
+	connectConnFlags.apply(&connectCtx)
 
 	var ok bool
 	if connectCtx.Language, ok = connect.ParseLanguage(connectLanguage); !ok {
 		return util.NewArgError(fmt.Sprintf("unsupported language: %s", connectLanguage))
 	}
+	if connectReadOnly {
+		switch connectCtx.Language {
+		case connect.DefaultLanguage:
+			connectCtx.Language = connect.SQLLanguage
+		case connect.LuaLanguage:
+			return util.NewArgError("read-only mode is supported only for the sql language")
+		}
+	}
 
 	newArgs, err := resolveConnectOpts(cmdCtx, cliOpts, &connectCtx, args)
 	if err != nil {
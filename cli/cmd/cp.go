@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/connect"
+	"github.com/tarantool/tt/cli/modules"
+	"github.com/tarantool/tt/cli/util"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+var (
+	cpUser       string
+	cpPassword   string
+	cpChunkSize  int
+	cpNoProgress bool
+	cpConnFlags  connectionFlags
+)
+
+// NewCpCmd creates cp command.
+func NewCpCmd() *cobra.Command {
+	var cpCmd = &cobra.Command{
+		Use: "cp <TARGET>:<REMOTE_PATH> <LOCAL_PATH> [flags]\n" +
+			"  tt cp <LOCAL_PATH> <TARGET>:<REMOTE_PATH> [flags]",
+		Short: "Copy files from or to the tarantool instance host",
+		Long: "Copy files from or to the tarantool instance host via the instance" +
+			" connection.\n\n" +
+			"TARGET is <APP_NAME>, <APP_NAME:INSTANCE_NAME> or <URI>, REMOTE_PATH is" +
+			" an absolute path on the instance host:\n\n" +
+			"  tt cp app:inst:/var/lib/tarantool/00000000000000000000.snap ./\n" +
+			"  tt cp ./init.lua localhost:3301:/tmp/init.lua\n\n" +
+			"A unix socket target must be specified as unix://<PATH>.\n" +
+			"The file is transferred in chunks into a file with .part suffix and its" +
+			" checksum is verified at the end. An interrupted copying is resumed on" +
+			" the next run.\n\n" +
+			"The command supports the following environment variables:\n\n" +
+			"* " + usernameEnv + " - specifies a username\n" +
+			"* " + passwordEnv + " - specifies a password\n",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdCtx.CommandName = cmd.Name()
+			err := modules.RunCmd(&cmdCtx, cmd.CommandPath(), &modulesInfo,
+				internalCpModule, args)
+			handleCmdErr(cmd, err)
+		},
+		Args: cobra.ExactArgs(2),
+	}
+
+	cpCmd.Flags().StringVarP(&cpUser, "username", "u", "", "username")
+	cpCmd.Flags().StringVarP(&cpPassword, "password", "p", "", "password")
+	cpCmd.Flags().IntVar(&cpChunkSize, "chunk-size", connect.DefaultCopyChunkSize,
+		"size of a chunk transferred with a single request in bytes")
+	cpCmd.Flags().BoolVar(&cpNoProgress, "no-progress", false, "do not show a progress bar")
+	addConnectionFlags(cpCmd, &cpConnFlags)
+
+	return cpCmd
+}
+
+// internalCpModule is a default cp module.
+func internalCpModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
+	if cpChunkSize <= 0 {
+		return util.NewArgError("chunk size must be positive")
+	}
+
+	src, srcIsRemote := connect.ParseRemotePath(args[0])
+	dst, dstIsRemote := connect.ParseRemotePath(args[1])
+	if srcIsRemote == dstIsRemote {
+		return util.NewArgError("exactly one of the paths must be a remote" +
+			" <TARGET>:<REMOTE_PATH> path")
+	}
+	remote := src
+	if dstIsRemote {
+		remote = dst
+	}
+
+	connectCtx := connect.ConnectCtx{
+		Username: cpUser,
+		Password: cpPassword,
+	}
+	cpConnFlags.apply(&connectCtx)
+	newArgs, err := resolveConnectOpts(cmdCtx, cliOpts, &connectCtx, []string{remote.Target})
+	if err != nil {
+		return err
+	}
+
+	copyOpts := connect.CopyOpts{ChunkSize: cpChunkSize}
+	if !cpNoProgress && terminal.IsTerminal(syscall.Stderr) {
+		copyOpts.Progress = os.Stderr
+	}
+
+	if srcIsRemote {
+		err = connect.CopyFromTarget(connectCtx, newArgs[0], src.Path, args[1], copyOpts)
+	} else {
+		err = connect.CopyToTarget(connectCtx, newArgs[0], args[0], dst.Path, copyOpts)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to copy: %s", err)
+	}
+	return nil
+}
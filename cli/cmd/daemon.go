@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/apex/log"
 	"github.com/spf13/cobra"
 	"github.com/tarantool/tt/cli/cmdcontext"
@@ -10,6 +12,14 @@ import (
 	"github.com/tarantool/tt/cli/process_utils"
 )
 
+var (
+	// daemonExecEnvironment is a name of the environment to run
+	// the command in.
+	daemonExecEnvironment string
+	// daemonExecSocket is a path to the daemon unix socket.
+	daemonExecSocket string
+)
+
 // NewDaemonCmd creates daemon command.
 func NewDaemonCmd() *cobra.Command {
 	var daemonCmd = &cobra.Command{
@@ -73,11 +83,33 @@ func NewDaemonCmd() *cobra.Command {
 		},
 	}
 
+	var execCmd = &cobra.Command{
+		Use:   "exec <COMMAND> [<PARAM>...]",
+		Short: "execute a command via the tt daemon unix socket",
+		Long: "Execute a command via the tt daemon unix socket. Access to the socket\n" +
+			"is controlled by its permissions, so the command could be requested by\n" +
+			"a user who does not own the instance files.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 0 {
+				log.Fatalf("Wrong number of arguments")
+			}
+
+			err := modules.RunCmd(&cmdCtx, cmd.CommandPath(), &modulesInfo,
+				internalDaemonExecModule, args)
+			handleCmdErr(cmd, err)
+		},
+	}
+	execCmd.Flags().StringVarP(&daemonExecEnvironment, "environment", "e", "",
+		"environment to run the command in")
+	execCmd.Flags().StringVar(&daemonExecSocket, "socket", "",
+		"path to the daemon unix socket, unix_socket of tt_daemon.yaml is used by default")
+
 	daemonSubCommands := []*cobra.Command{
 		startCmd,
 		stopCmd,
 		statusCmd,
 		restartCmd,
+		execCmd,
 	}
 
 	for _, cmd := range daemonSubCommands {
@@ -108,6 +140,8 @@ func internalDaemonStartModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
 	}
 
 	daemonCtx := daemon.NewDaemonCtx(opts)
+	daemonCtx.CliOpts = cliOpts
+	daemonCtx.CmdCtx = cmdCtx
 	if err := daemon.RunHTTPServerOnBackground(daemonCtx); err != nil {
 		log.Fatalf(err.Error())
 	}
@@ -142,3 +176,26 @@ func internalDaemonStatusModule(cmdCtx *cmdcontext.CmdCtx, args []string) error
 
 	return nil
 }
+
+// internalDaemonExecModule is a default exec module.
+func internalDaemonExecModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
+	socketPath := daemonExecSocket
+	if socketPath == "" {
+		opts, err := configure.GetDaemonOpts(cmdCtx.Cli.DaemonCfgPath)
+		if err != nil {
+			return err
+		}
+		if opts.UnixSocket == "" {
+			return fmt.Errorf("unix_socket is not set in the daemon configuration")
+		}
+		socketPath = opts.UnixSocket
+	}
+
+	res, err := daemon.Exec(socketPath, daemon.ExecRequest{
+		Name:        args[0],
+		Params:      args[1:],
+		Environment: daemonExecEnvironment,
+	})
+	fmt.Print(res)
+	return err
+}
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/connect"
+	"github.com/tarantool/tt/cli/modules"
+	"github.com/tarantool/tt/cli/running"
+	"github.com/tarantool/tt/cli/util"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+var (
+	evalUser       string
+	evalPassword   string
+	evalExpression string
+	evalArgs       []string
+	evalFormat     string
+	evalAll        bool
+	evalConnFlags  connectionFlags
+)
+
+// NewEvalCmd creates eval command.
+func NewEvalCmd() *cobra.Command {
+	var evalCmd = &cobra.Command{
+		Use: "eval (<APP_NAME> | <APP_NAME:INSTANCE_NAME> | <URI>)" +
+			" -e <EXPRESSION> [--arg <KEY=VALUE>]... [flags]\n" +
+			"  EXPRESSION | tt eval (<APP_NAME> | <APP_NAME:INSTANCE_NAME> | <URI>) [flags]",
+		Short: "Evaluate a Lua expression on the tarantool instance",
+		Long: "Evaluate a Lua expression on the tarantool instance.\n\n" +
+			"Arguments are passed to the expression as a table in the first vararg:\n\n" +
+			"  tt eval app:inst -e 'local args = ... return args.a + args.b' " +
+			"--arg a=1 --arg b=2\n\n" +
+			"A value of an argument is decoded as JSON if possible, otherwise it is" +
+			" passed as a string.\n" +
+			"The command exits with a non-zero code if the expression raises an error.\n\n" +
+			"The expression is evaluated concurrently on all instances of the application" +
+			" with --all flag or on instances matching a glob pattern:\n\n" +
+			"  tt eval app --all -e 'return box.info.replication'\n" +
+			"  tt eval 'app:storage*' -e 'return box.info.replication'\n\n" +
+			"The results are grouped by instance. An error on an instance does not abort" +
+			" the evaluation on the others.\n\n" +
+			"The command supports the following environment variables:\n\n" +
+			"* " + usernameEnv + " - specifies a username\n" +
+			"* " + passwordEnv + " - specifies a password\n",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdCtx.CommandName = cmd.Name()
+			err := modules.RunCmd(&cmdCtx, cmd.CommandPath(), &modulesInfo,
+				internalEvalModule, args)
+			handleCmdErr(cmd, err)
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	evalCmd.Flags().StringVarP(&evalUser, "username", "u", "", "username")
+	evalCmd.Flags().StringVarP(&evalPassword, "password", "p", "", "password")
+	evalCmd.Flags().StringVarP(&evalExpression, "expression", "e", "",
+		"Lua expression to evaluate. It is read from stdin if not specified")
+	evalCmd.Flags().StringArrayVar(&evalArgs, "arg", nil,
+		"argument in the KEY=VALUE format. May be passed more than once")
+	evalCmd.Flags().StringVar(&evalFormat, "format", connect.EvalFormatYAML,
+		"output format: "+strings.Join(connect.EvalFormats, ", "))
+	evalCmd.Flags().BoolVar(&evalAll, "all", false,
+		"evaluate the expression on all instances of the application")
+	addConnectionFlags(evalCmd, &evalConnFlags)
+
+	return evalCmd
+}
+
+// getEvalExpression returns the expression from the flag or stdin.
+func getEvalExpression() (string, error) {
+	if evalExpression != "" {
+		return evalExpression, nil
+	}
+
+	if terminal.IsTerminal(syscall.Stdin) {
+		return "", util.NewArgError("an expression is required: use -e or pipe it to stdin")
+	}
+
+	expr, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the expression: %s", err)
+	}
+	return string(expr), nil
+}
+
+// isInstancePattern returns true if the instance name part of the target is
+// a glob pattern.
+func isInstancePattern(target string) bool {
+	if isBaseURI(target) || isCredentialsURI(target) {
+		return false
+	}
+	colonIdx := strings.Index(target, ":")
+	return colonIdx != -1 && strings.ContainsAny(target[colonIdx+1:], "*?[")
+}
+
+// getEvalTargets returns control sockets of the application instances
+// matching the target. The target is an application name with --all flag or
+// an "app:pattern" string.
+func getEvalTargets(cmdCtx *cmdcontext.CmdCtx, target string) ([]connect.EvalTarget, error) {
+	appName, pattern := target, "*"
+	if colonIdx := strings.Index(target, ":"); colonIdx != -1 {
+		if evalAll {
+			return nil, util.NewArgError("--all flag can't be used with an instance name")
+		}
+		appName, pattern = target[:colonIdx], target[colonIdx+1:]
+	}
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, util.NewArgError(fmt.Sprintf("invalid instance pattern %q: %s",
+			pattern, err))
+	}
+	if evalUser != "" || evalPassword != "" {
+		return nil, fmt.Errorf("username and password are not supported" +
+			" with a connection via a control socket")
+	}
+
+	var runningCtx running.RunningCtx
+	if err := running.FillCtx(cliOpts, cmdCtx, &runningCtx, []string{appName}); err != nil {
+		return nil, err
+	}
+
+	targets := []connect.EvalTarget{}
+	for _, inst := range runningCtx.Instances {
+		// The pattern has been validated above.
+		if matched, _ := filepath.Match(pattern, inst.InstName); !matched {
+			continue
+		}
+		name := inst.AppName
+		if !inst.SingleApp {
+			name += ":" + inst.InstName
+		}
+		targets = append(targets, connect.EvalTarget{
+			Name:       name,
+			ConnString: inst.ConsoleSocket,
+		})
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no instances matching %q found", target)
+	}
+	// Instances order is not defined by the configuration.
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Name < targets[j].Name
+	})
+	return targets, nil
+}
+
+// evalOnInstances evaluates the expression on several instances and prints the
+// grouped results.
+func evalOnInstances(cmdCtx *cmdcontext.CmdCtx, target string, evalOpts connect.EvalOpts) error {
+	targets, err := getEvalTargets(cmdCtx, target)
+	if err != nil {
+		return err
+	}
+
+	var connectCtx connect.ConnectCtx
+	evalConnFlags.apply(&connectCtx)
+	targetResults := connect.EvalOnTargets(connectCtx, targets, evalOpts)
+	output, err := connect.FormatEvalTargetResults(targetResults, evalFormat)
+	if err != nil {
+		return fmt.Errorf("failed to encode the result: %s", err)
+	}
+	fmt.Println(strings.TrimRight(string(output), "\n"))
+
+	failed := 0
+	for _, result := range targetResults {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("the evaluation failed on %d of %d instances",
+			failed, len(targetResults))
+	}
+	return nil
+}
+
+// internalEvalModule is a default eval module.
+func internalEvalModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
+	if util.Find(connect.EvalFormats, evalFormat) == -1 {
+		return util.NewArgError(fmt.Sprintf("unsupported format: %s", evalFormat))
+	}
+
+	evalArgsParsed, err := connect.ParseEvalArgs(evalArgs)
+	if err != nil {
+		return util.NewArgError(err.Error())
+	}
+
+	expr, err := getEvalExpression()
+	if err != nil {
+		return err
+	}
+
+	evalOpts := connect.EvalOpts{
+		Expression: expr,
+		Args:       evalArgsParsed,
+		Format:     evalFormat,
+	}
+	if evalAll || isInstancePattern(args[0]) {
+		return evalOnInstances(cmdCtx, args[0], evalOpts)
+	}
+
+	connectCtx := connect.ConnectCtx{
+		Username: evalUser,
+		Password: evalPassword,
+	}
+	evalConnFlags.apply(&connectCtx)
+	newArgs, err := resolveConnectOpts(cmdCtx, cliOpts, &connectCtx, args)
+	if err != nil {
+		return err
+	}
+
+	results, err := connect.EvalOnTarget(connectCtx, newArgs[0], evalOpts)
+	if err != nil {
+		return err
+	}
+
+	output, err := connect.FormatEvalResults(results, evalFormat)
+	if err != nil {
+		return fmt.Errorf("failed to encode the result: %s", err)
+	}
+	// "Println" is used instead of "log..." to print the result without
+	// any decoration.
+	fmt.Println(strings.TrimRight(string(output), "\n"))
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsInstancePattern(t *testing.T) {
+	for target, expected := range map[string]bool{
+		"app":                 false,
+		"app:storage":         false,
+		"app:storage*":        true,
+		"app:storage?":        true,
+		"app:storage[12]":     true,
+		"localhost:3301":      false,
+		"unix://path/*.sock":  false,
+		"user:pass@host:3301": false,
+	} {
+		t.Run(target, func(t *testing.T) {
+			assert.Equal(t, expected, isInstancePattern(target))
+		})
+	}
+}
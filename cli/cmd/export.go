@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/connect"
+	"github.com/tarantool/tt/cli/modules"
+	"github.com/tarantool/tt/cli/util"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+var (
+	exportUser       string
+	exportPassword   string
+	exportConnFlags  connectionFlags
+	exportFormat     string
+	exportWhere      string
+	exportBatchSize  int
+	exportNoProgress bool
+)
+
+// NewExportCmd creates export command.
+func NewExportCmd() *cobra.Command {
+	var exportCmd = &cobra.Command{
+		Use:   "export " + instanceTargetUse + " <SPACE> [flags]",
+		Short: "Export tuples of the space into stdout",
+		Long: "Export tuples of the space into stdout.\n\n" +
+			"Tuples are selected in batches and fields are named by the space format." +
+			" A field without a format is named field_<NUMBER>. Tuples matching a key" +
+			" in an index could be exported with --where flag, the key is decoded as" +
+			" JSON if possible:\n\n" +
+			"  tt export app:inst customers --format jsonl > customers.jsonl\n" +
+			"  tt export app:inst customers --where 'city:\"Moscow\"' > moscow.csv\n" +
+			"  tt export app:inst customers --where 'pk:[1]'\n\n" +
+			"The command supports the following environment variables:\n\n" +
+			"* " + usernameEnv + " - specifies a username\n" +
+			"* " + passwordEnv + " - specifies a password\n",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdCtx.CommandName = cmd.Name()
+			err := modules.RunCmd(&cmdCtx, cmd.CommandPath(), &modulesInfo,
+				internalExportModule, args)
+			handleCmdErr(cmd, err)
+		},
+		Args: cobra.ExactArgs(2),
+	}
+
+	exportCmd.Flags().StringVarP(&exportUser, "username", "u", "", "username")
+	exportCmd.Flags().StringVarP(&exportPassword, "password", "p", "", "password")
+	exportCmd.Flags().StringVar(&exportFormat, "format", connect.DataFormatCSV,
+		"output format: "+strings.Join(connect.DataFormats, ", "))
+	exportCmd.Flags().StringVar(&exportWhere, "where", "",
+		"export tuples matching the key in the index, format: INDEX:KEY")
+	exportCmd.Flags().IntVar(&exportBatchSize, "batch-size", connect.DefaultBatchSize,
+		"number of tuples selected with a single request")
+	exportCmd.Flags().BoolVar(&exportNoProgress, "no-progress", false,
+		"do not show a progress")
+	addConnectionFlags(exportCmd, &exportConnFlags)
+
+	return exportCmd
+}
+
+// internalExportModule is a default export module.
+func internalExportModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
+	if util.Find(connect.DataFormats, exportFormat) == -1 {
+		return util.NewArgError(fmt.Sprintf("unsupported format: %s", exportFormat))
+	}
+	if exportBatchSize <= 0 {
+		return util.NewArgError("batch size must be positive")
+	}
+
+	connectCtx := connect.ConnectCtx{
+		Username: exportUser,
+		Password: exportPassword,
+	}
+	exportConnFlags.apply(&connectCtx)
+	newArgs, err := resolveConnectOpts(cmdCtx, cliOpts, &connectCtx, args[:1])
+	if err != nil {
+		return err
+	}
+
+	exportOpts := connect.ExportOpts{
+		Space:     args[1],
+		Format:    exportFormat,
+		Where:     exportWhere,
+		BatchSize: exportBatchSize,
+	}
+	if !exportNoProgress && terminal.IsTerminal(syscall.Stderr) {
+		exportOpts.Progress = os.Stderr
+	}
+
+	writer := bufio.NewWriter(os.Stdout)
+	exported, err := connect.ExportFromTarget(connectCtx, newArgs[0], writer, exportOpts)
+	if flushErr := writer.Flush(); err == nil && flushErr != nil {
+		err = fmt.Errorf("failed to write tuples: %s", flushErr)
+	}
+	if err != nil {
+		return err
+	}
+	log.Infof("Exported %d tuples", exported)
+	return nil
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/connect"
+	"github.com/tarantool/tt/cli/modules"
+	"github.com/tarantool/tt/cli/util"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+var (
+	importUser       string
+	importPassword   string
+	importConnFlags  connectionFlags
+	importFormat     string
+	importOnConflict string
+	importBatchSize  int
+	importNoProgress bool
+)
+
+// NewImportCmd creates import command.
+func NewImportCmd() *cobra.Command {
+	var importCmd = &cobra.Command{
+		Use:   "import " + instanceTargetUse + " <SPACE> [<FILE>] [flags]",
+		Short: "Import tuples into the space from a file or stdin",
+		Long: "Import tuples exported with tt export into the space from a file or stdin.\n\n" +
+			"Fields are mapped by the space format names, so the field order of the" +
+			" target space could differ. Tuples are inserted in batches, each batch is" +
+			" inserted in a separate transaction.\n\n" +
+			"  tt import app:inst customers customers.csv --on-conflict skip\n\n" +
+			"The command supports the following environment variables:\n\n" +
+			"* " + usernameEnv + " - specifies a username\n" +
+			"* " + passwordEnv + " - specifies a password\n",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdCtx.CommandName = cmd.Name()
+			err := modules.RunCmd(&cmdCtx, cmd.CommandPath(), &modulesInfo,
+				internalImportModule, args)
+			handleCmdErr(cmd, err)
+		},
+		Args: cobra.RangeArgs(2, 3),
+	}
+
+	importCmd.Flags().StringVarP(&importUser, "username", "u", "", "username")
+	importCmd.Flags().StringVarP(&importPassword, "password", "p", "", "password")
+	importCmd.Flags().StringVar(&importFormat, "format", connect.DataFormatCSV,
+		"input format: "+strings.Join(connect.DataFormats, ", "))
+	importCmd.Flags().StringVar(&importOnConflict, "on-conflict", connect.OnConflictError,
+		"policy for tuples with existing primary keys: "+
+			strings.Join(connect.OnConflictPolicies, ", "))
+	importCmd.Flags().IntVar(&importBatchSize, "batch-size", connect.DefaultBatchSize,
+		"number of tuples inserted in a single transaction")
+	importCmd.Flags().BoolVar(&importNoProgress, "no-progress", false,
+		"do not show a progress")
+	addConnectionFlags(importCmd, &importConnFlags)
+
+	return importCmd
+}
+
+// internalImportModule is a default import module.
+func internalImportModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
+	if util.Find(connect.DataFormats, importFormat) == -1 {
+		return util.NewArgError(fmt.Sprintf("unsupported format: %s", importFormat))
+	}
+	if util.Find(connect.OnConflictPolicies, importOnConflict) == -1 {
+		return util.NewArgError(fmt.Sprintf("unsupported conflict policy: %s",
+			importOnConflict))
+	}
+	if importBatchSize <= 0 {
+		return util.NewArgError("batch size must be positive")
+	}
+
+	var reader io.Reader = os.Stdin
+	if len(args) == 3 {
+		file, err := os.Open(args[2])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		reader = file
+	} else if terminal.IsTerminal(syscall.Stdin) {
+		return util.NewArgError("a file is required: specify it or pipe it to stdin")
+	}
+
+	connectCtx := connect.ConnectCtx{
+		Username: importUser,
+		Password: importPassword,
+	}
+	importConnFlags.apply(&connectCtx)
+	newArgs, err := resolveConnectOpts(cmdCtx, cliOpts, &connectCtx, args[:1])
+	if err != nil {
+		return err
+	}
+
+	importOpts := connect.ImportOpts{
+		Space:      args[1],
+		Format:     importFormat,
+		OnConflict: importOnConflict,
+		BatchSize:  importBatchSize,
+	}
+	if !importNoProgress && terminal.IsTerminal(syscall.Stderr) {
+		importOpts.Progress = os.Stderr
+	}
+
+	result, err := connect.ImportToTarget(connectCtx, newArgs[0], bufio.NewReader(reader),
+		importOpts)
+	if result.Inserted > 0 || result.Skipped > 0 || err == nil {
+		log.Infof("Imported %d tuples, skipped %d tuples", result.Inserted, result.Skipped)
+	}
+	return err
+}
@@ -132,6 +132,15 @@ func NewCmdRoot() *cobra.Command {
 		NewLogrotateCmd(),
 		NewCheckCmd(),
 		NewConnectCmd(),
+		NewEvalCmd(),
+		NewCallCmd(),
+		NewSQLCmd(),
+		NewWatchCmd(),
+		NewCpCmd(),
+		NewUserCmd(),
+		NewSchemaCmd(),
+		NewExportCmd(),
+		NewImportCmd(),
 		NewRocksCmd(),
 		NewCatCmd(),
 		NewPlayCmd(),
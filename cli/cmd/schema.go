@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/connect"
+	"github.com/tarantool/tt/cli/connector"
+	"github.com/tarantool/tt/cli/modules"
+	"github.com/tarantool/tt/cli/util"
+)
+
+var (
+	schemaUser       string
+	schemaPassword   string
+	schemaConnFlags  connectionFlags
+	schemaFormat     string
+	schemaShowSystem bool
+)
+
+// NewSchemaCmd creates schema command.
+func NewSchemaCmd() *cobra.Command {
+	var schemaCmd = &cobra.Command{
+		Use:   "schema",
+		Short: "Inspect spaces and indexes of the tarantool instance",
+	}
+
+	var spacesCmd = &cobra.Command{
+		Use:   "spaces " + instanceTargetUse + " [flags]",
+		Short: "list spaces with their sizes",
+		Run:   newSchemaRun(internalSchemaSpacesModule),
+		Args:  cobra.ExactArgs(1),
+	}
+
+	var indexesCmd = &cobra.Command{
+		Use:   "indexes " + instanceTargetUse + " <SPACE> [flags]",
+		Short: "list indexes of the space",
+		Run:   newSchemaRun(internalSchemaIndexesModule),
+		Args:  cobra.ExactArgs(2),
+	}
+
+	var formatCmd = &cobra.Command{
+		Use:   "format " + instanceTargetUse + " <SPACE> [flags]",
+		Short: "print a format of the space",
+		Run:   newSchemaRun(internalSchemaFormatModule),
+		Args:  cobra.ExactArgs(2),
+	}
+
+	var dumpCmd = &cobra.Command{
+		Use:   "dump " + instanceTargetUse + " [flags]",
+		Short: "dump the schema of all spaces to YAML",
+		Long: "Dump formats and indexes of all spaces to YAML.\n\n" +
+			"Spaces are keyed by names, identifiers and sizes of spaces are not" +
+			" included, so dumps of different instances could be compared:\n\n" +
+			"  diff <(tt schema dump app:first) <(tt schema dump app:second)",
+		Run:  newSchemaRun(internalSchemaDumpModule),
+		Args: cobra.ExactArgs(1),
+	}
+
+	schemaSubCommands := []*cobra.Command{
+		spacesCmd,
+		indexesCmd,
+		formatCmd,
+		dumpCmd,
+	}
+
+	for _, cmd := range schemaSubCommands {
+		cmd.Flags().StringVarP(&schemaUser, "username", "u", "", "username")
+		cmd.Flags().StringVarP(&schemaPassword, "password", "p", "", "password")
+		addConnectionFlags(cmd, &schemaConnFlags)
+		if cmd != dumpCmd {
+			cmd.Flags().StringVar(&schemaFormat, "format", connect.SQLFormatTable,
+				"output format: "+strings.Join(connect.SchemaFormats, ", "))
+		}
+		if cmd == spacesCmd || cmd == dumpCmd {
+			cmd.Flags().BoolVar(&schemaShowSystem, "show-system", false,
+				"show system spaces")
+		}
+		schemaCmd.AddCommand(cmd)
+	}
+
+	return schemaCmd
+}
+
+// newSchemaRun returns a run function of a schema subcommand.
+func newSchemaRun(module modules.InternalFunc) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		cmdCtx.CommandName = "schema"
+		err := modules.RunCmd(&cmdCtx, cmd.CommandPath(), &modulesInfo, module, args)
+		handleCmdErr(cmd, err)
+	}
+}
+
+// runSchemaCommand connects to the target and prints the output of
+// the function.
+func runSchemaCommand(cmdCtx *cmdcontext.CmdCtx, target string,
+	output func(conn connector.Connector) ([]byte, error)) error {
+	if util.Find(connect.SchemaFormats, schemaFormat) == -1 {
+		return util.NewArgError(fmt.Sprintf("unsupported format: %s", schemaFormat))
+	}
+
+	connectCtx := connect.ConnectCtx{
+		Username: schemaUser,
+		Password: schemaPassword,
+	}
+	schemaConnFlags.apply(&connectCtx)
+	newArgs, err := resolveConnectOpts(cmdCtx, cliOpts, &connectCtx, []string{target})
+	if err != nil {
+		return err
+	}
+
+	return connect.DoOnTarget(connectCtx, newArgs[0], func(conn connector.Connector) error {
+		data, err := output(conn)
+		if err != nil {
+			return err
+		}
+		fmt.Println(strings.TrimRight(string(data), "\n"))
+		return nil
+	})
+}
+
+// internalSchemaSpacesModule is a default schema spaces module.
+func internalSchemaSpacesModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
+	return runSchemaCommand(cmdCtx, args[0], func(conn connector.Connector) ([]byte, error) {
+		spaces, err := connect.GetSpaces(conn, schemaShowSystem)
+		if err != nil {
+			return nil, err
+		}
+		return connect.FormatSpaces(spaces, schemaFormat)
+	})
+}
+
+// internalSchemaIndexesModule is a default schema indexes module.
+func internalSchemaIndexesModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
+	return runSchemaCommand(cmdCtx, args[0], func(conn connector.Connector) ([]byte, error) {
+		schema, err := connect.GetSpaceSchema(conn, args[1])
+		if err != nil {
+			return nil, err
+		}
+		return connect.FormatIndexes(schema, schemaFormat)
+	})
+}
+
+// internalSchemaFormatModule is a default schema format module.
+func internalSchemaFormatModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
+	return runSchemaCommand(cmdCtx, args[0], func(conn connector.Connector) ([]byte, error) {
+		schema, err := connect.GetSpaceSchema(conn, args[1])
+		if err != nil {
+			return nil, err
+		}
+		return connect.FormatSpaceFormat(schema, schemaFormat)
+	})
+}
+
+// internalSchemaDumpModule is a default schema dump module.
+func internalSchemaDumpModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
+	return runSchemaCommand(cmdCtx, args[0], func(conn connector.Connector) ([]byte, error) {
+		dump, err := connect.DumpSchema(conn, schemaShowSystem)
+		if err != nil {
+			return nil, err
+		}
+		return connect.FormatSchemaDump(dump)
+	})
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/connect"
+	"github.com/tarantool/tt/cli/modules"
+	"github.com/tarantool/tt/cli/util"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+var (
+	sqlUser      string
+	sqlPassword  string
+	sqlBinds     []string
+	sqlFormat    string
+	sqlConnFlags connectionFlags
+)
+
+// NewSQLCmd creates sql command.
+func NewSQLCmd() *cobra.Command {
+	var sqlCmd = &cobra.Command{
+		Use: "sql (<APP_NAME> | <APP_NAME:INSTANCE_NAME> | <URI>)" +
+			" <STATEMENT> [--bind <VALUE>]... [flags]\n" +
+			"  STATEMENT | tt sql (<APP_NAME> | <APP_NAME:INSTANCE_NAME> | <URI>) [flags]",
+		Short: "Execute an SQL statement on the tarantool instance",
+		Long: "Execute an SQL statement on the tarantool instance.\n\n" +
+			"Values of the statement parameters are passed with --bind flags in" +
+			" the order of the parameters:\n\n" +
+			"  tt sql app:inst 'SELECT * FROM t WHERE id > ?' --bind 10\n\n" +
+			"A value is decoded as JSON if possible, otherwise it is passed as a string.\n\n" +
+			"The command supports the following environment variables:\n\n" +
+			"* " + usernameEnv + " - specifies a username\n" +
+			"* " + passwordEnv + " - specifies a password\n",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdCtx.CommandName = cmd.Name()
+			err := modules.RunCmd(&cmdCtx, cmd.CommandPath(), &modulesInfo,
+				internalSQLModule, args)
+			handleCmdErr(cmd, err)
+		},
+		Args: cobra.RangeArgs(1, 2),
+	}
+
+	sqlCmd.Flags().StringVarP(&sqlUser, "username", "u", "", "username")
+	sqlCmd.Flags().StringVarP(&sqlPassword, "password", "p", "", "password")
+	sqlCmd.Flags().StringArrayVar(&sqlBinds, "bind", nil,
+		"value of a statement parameter. May be passed more than once")
+	sqlCmd.Flags().StringVar(&sqlFormat, "format", connect.SQLFormatTable,
+		"output format: "+strings.Join(connect.SQLFormats, ", "))
+	addConnectionFlags(sqlCmd, &sqlConnFlags)
+
+	return sqlCmd
+}
+
+// getSQLStatement returns the statement from the arguments or stdin.
+func getSQLStatement(args []string) (string, error) {
+	if len(args) > 1 {
+		return args[1], nil
+	}
+
+	if terminal.IsTerminal(syscall.Stdin) {
+		return "", util.NewArgError("a statement is required: pass it as an argument" +
+			" or pipe it to stdin")
+	}
+
+	statement, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the statement: %s", err)
+	}
+	return string(statement), nil
+}
+
+// internalSQLModule is a default sql module.
+func internalSQLModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
+	if util.Find(connect.SQLFormats, sqlFormat) == -1 {
+		return util.NewArgError(fmt.Sprintf("unsupported format: %s", sqlFormat))
+	}
+
+	statement, err := getSQLStatement(args)
+	if err != nil {
+		return err
+	}
+
+	connectCtx := connect.ConnectCtx{
+		Username: sqlUser,
+		Password: sqlPassword,
+	}
+	sqlConnFlags.apply(&connectCtx)
+	newArgs, err := resolveConnectOpts(cmdCtx, cliOpts, &connectCtx, args[:1])
+	if err != nil {
+		return err
+	}
+
+	result, err := connect.ExecuteOnTarget(connectCtx, newArgs[0], statement,
+		connect.ParseRequestArgs(sqlBinds))
+	if err != nil {
+		return err
+	}
+
+	output, err := connect.FormatSQLResult(result, sqlFormat)
+	if err != nil {
+		return fmt.Errorf("failed to encode the result: %s", err)
+	}
+	// "Println" is used instead of "log..." to print the result without
+	// any decoration.
+	fmt.Println(strings.TrimRight(string(output), "\n"))
+
+	return nil
+}
@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/connect"
+	"github.com/tarantool/tt/cli/connector"
+	"github.com/tarantool/tt/cli/modules"
+	"github.com/tarantool/tt/cli/util"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+var (
+	userConnUser     string
+	userConnPassword string
+	userConnFlags    connectionFlags
+	userFormat       string
+	userNoPassword   bool
+	userIfNotExists  bool
+	userIfExists     bool
+)
+
+// NewUserCmd creates user command.
+func NewUserCmd() *cobra.Command {
+	var userCmd = &cobra.Command{
+		Use:   "user",
+		Short: "Manage users and privileges on the tarantool instance",
+		Long: "Manage users and privileges on the tarantool instance.\n\n" +
+			"A password is prompted via the terminal. It is read from the first line of" +
+			" stdin if stdin is not a terminal.",
+	}
+
+	var listCmd = &cobra.Command{
+		Use:   "list " + instanceTargetUse + " [flags]",
+		Short: "list users and roles with their privileges",
+		Run:   newUserRun(internalUserListModule),
+		Args:  cobra.ExactArgs(1),
+	}
+	listCmd.Flags().StringVar(&userFormat, "format", connect.SQLFormatTable,
+		"output format: "+strings.Join(connect.UserFormats, ", "))
+
+	var createCmd = &cobra.Command{
+		Use:   "create " + instanceTargetUse + " <USER> [flags]",
+		Short: "create a user",
+		Run:   newUserRun(internalUserCreateModule),
+		Args:  cobra.ExactArgs(2),
+	}
+	createCmd.Flags().BoolVar(&userNoPassword, "no-password", false,
+		"create the user without a password")
+	createCmd.Flags().BoolVar(&userIfNotExists, "if-not-exists", false,
+		"do not fail if the user exists")
+
+	var passwdCmd = &cobra.Command{
+		Use:   "passwd " + instanceTargetUse + " <USER> [flags]",
+		Short: "change a password of the user",
+		Run:   newUserRun(internalUserPasswdModule),
+		Args:  cobra.ExactArgs(2),
+	}
+
+	var grantCmd = &cobra.Command{
+		Use: "grant " + instanceTargetUse +
+			" <USER> <PRIVILEGES> <OBJECT_TYPE> [<OBJECT_NAME>] [flags]",
+		Short: "grant privileges to the user",
+		Long: "Grant privileges to the user.\n\n" +
+			"  tt user grant app:inst alice read,write space test\n" +
+			"  tt user grant app:inst alice execute universe\n" +
+			"  tt user grant app:inst alice execute role replication",
+		Run:  newUserRun(internalUserGrantModule),
+		Args: cobra.RangeArgs(4, 5),
+	}
+
+	var revokeCmd = &cobra.Command{
+		Use: "revoke " + instanceTargetUse +
+			" <USER> <PRIVILEGES> <OBJECT_TYPE> [<OBJECT_NAME>] [flags]",
+		Short: "revoke privileges from the user",
+		Run:   newUserRun(internalUserRevokeModule),
+		Args:  cobra.RangeArgs(4, 5),
+	}
+
+	var dropCmd = &cobra.Command{
+		Use:   "drop " + instanceTargetUse + " <USER> [flags]",
+		Short: "drop the user",
+		Run:   newUserRun(internalUserDropModule),
+		Args:  cobra.ExactArgs(2),
+	}
+	dropCmd.Flags().BoolVar(&userIfExists, "if-exists", false,
+		"do not fail if the user does not exist")
+
+	userSubCommands := []*cobra.Command{
+		listCmd,
+		createCmd,
+		passwdCmd,
+		grantCmd,
+		revokeCmd,
+		dropCmd,
+	}
+
+	for _, cmd := range userSubCommands {
+		cmd.Flags().StringVarP(&userConnUser, "username", "u", "", "username")
+		cmd.Flags().StringVarP(&userConnPassword, "password", "p", "", "password")
+		addConnectionFlags(cmd, &userConnFlags)
+		userCmd.AddCommand(cmd)
+	}
+
+	return userCmd
+}
+
+// newUserRun returns a run function of a user subcommand.
+func newUserRun(module modules.InternalFunc) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		cmdCtx.CommandName = "user"
+		err := modules.RunCmd(&cmdCtx, cmd.CommandPath(), &modulesInfo, module, args)
+		handleCmdErr(cmd, err)
+	}
+}
+
+// runOnUserTarget connects to the target and calls the function with
+// the connection.
+func runOnUserTarget(cmdCtx *cmdcontext.CmdCtx, target string,
+	do func(conn connector.Connector) error) error {
+	connectCtx := connect.ConnectCtx{
+		Username: userConnUser,
+		Password: userConnPassword,
+	}
+	userConnFlags.apply(&connectCtx)
+	newArgs, err := resolveConnectOpts(cmdCtx, cliOpts, &connectCtx, []string{target})
+	if err != nil {
+		return err
+	}
+	return connect.DoOnTarget(connectCtx, newArgs[0], do)
+}
+
+// readUserPassword reads a new password of the user. The password is
+// prompted twice via the terminal or is read from the first line of stdin.
+func readUserPassword(user string) (string, error) {
+	if !terminal.IsTerminal(syscall.Stdin) {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", fmt.Errorf("failed to read the password: %s", err)
+		}
+		password := strings.TrimRight(line, "\r\n")
+		if password == "" {
+			return "", fmt.Errorf("the password is empty")
+		}
+		return password, nil
+	}
+
+	readPassword := func(prompt string) (string, error) {
+		fmt.Fprint(os.Stderr, prompt)
+		password, err := terminal.ReadPassword(syscall.Stdin)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("failed to read the password: %s", err)
+		}
+		return string(password), nil
+	}
+
+	password, err := readPassword(fmt.Sprintf("Enter password for %s: ", user))
+	if err != nil {
+		return "", err
+	}
+	if password == "" {
+		return "", fmt.Errorf("the password is empty")
+	}
+	repeated, err := readPassword("Repeat password: ")
+	if err != nil {
+		return "", err
+	}
+	if password != repeated {
+		return "", fmt.Errorf("the passwords do not match")
+	}
+	return password, nil
+}
+
+// getGrantOpts returns privileges to grant or revoke from the arguments.
+func getGrantOpts(args []string) connect.GrantOpts {
+	opts := connect.GrantOpts{
+		Privileges: args[0],
+		ObjectType: args[1],
+	}
+	if len(args) > 2 {
+		opts.ObjectName = args[2]
+	}
+	return opts
+}
+
+// internalUserListModule is a default user list module.
+func internalUserListModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
+	if util.Find(connect.UserFormats, userFormat) == -1 {
+		return util.NewArgError(fmt.Sprintf("unsupported format: %s", userFormat))
+	}
+
+	return runOnUserTarget(cmdCtx, args[0], func(conn connector.Connector) error {
+		users, err := connect.ListUsers(conn)
+		if err != nil {
+			return err
+		}
+		output, err := connect.FormatUsers(users, userFormat)
+		if err != nil {
+			return fmt.Errorf("failed to encode the result: %s", err)
+		}
+		fmt.Println(strings.TrimRight(string(output), "\n"))
+		return nil
+	})
+}
+
+// internalUserCreateModule is a default user create module.
+func internalUserCreateModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
+	password := ""
+	if !userNoPassword {
+		var err error
+		if password, err = readUserPassword(args[1]); err != nil {
+			return err
+		}
+	}
+
+	return runOnUserTarget(cmdCtx, args[0], func(conn connector.Connector) error {
+		if err := connect.CreateUser(conn, args[1], password, userIfNotExists); err != nil {
+			return err
+		}
+		log.Infof("User %q is created", args[1])
+		return nil
+	})
+}
+
+// internalUserPasswdModule is a default user passwd module.
+func internalUserPasswdModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
+	password, err := readUserPassword(args[1])
+	if err != nil {
+		return err
+	}
+
+	return runOnUserTarget(cmdCtx, args[0], func(conn connector.Connector) error {
+		if err := connect.ChangeUserPassword(conn, args[1], password); err != nil {
+			return err
+		}
+		log.Infof("Password of user %q is changed", args[1])
+		return nil
+	})
+}
+
+// internalUserGrantModule is a default user grant module.
+func internalUserGrantModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
+	return runOnUserTarget(cmdCtx, args[0], func(conn connector.Connector) error {
+		if err := connect.GrantUser(conn, args[1], getGrantOpts(args[2:])); err != nil {
+			return err
+		}
+		log.Infof("Privileges are granted to user %q", args[1])
+		return nil
+	})
+}
+
+// internalUserRevokeModule is a default user revoke module.
+func internalUserRevokeModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
+	return runOnUserTarget(cmdCtx, args[0], func(conn connector.Connector) error {
+		if err := connect.RevokeUser(conn, args[1], getGrantOpts(args[2:])); err != nil {
+			return err
+		}
+		log.Infof("Privileges are revoked from user %q", args[1])
+		return nil
+	})
+}
+
+// internalUserDropModule is a default user drop module.
+func internalUserDropModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
+	return runOnUserTarget(cmdCtx, args[0], func(conn connector.Connector) error {
+		if err := connect.DropUser(conn, args[1], userIfExists); err != nil {
+			return err
+		}
+		log.Infof("User %q is dropped", args[1])
+		return nil
+	})
+}
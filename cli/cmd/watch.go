@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/connect"
+	"github.com/tarantool/tt/cli/modules"
+	"github.com/tarantool/tt/cli/util"
+)
+
+var (
+	watchUser      string
+	watchPassword  string
+	watchFormat    string
+	watchConnFlags connectionFlags
+)
+
+// NewWatchCmd creates watch command.
+func NewWatchCmd() *cobra.Command {
+	var watchCmd = &cobra.Command{
+		Use:   "watch (<APP_NAME> | <APP_NAME:INSTANCE_NAME> | <URI>) <KEY> [flags]",
+		Short: "Watch changes of a key on the tarantool instance",
+		Long: "Watch changes of a key set with box.broadcast() on the tarantool instance.\n\n" +
+			"The current value and then each change of the value are printed with" +
+			" a timestamp until the command is interrupted. Tarantool 2.10+ is required.\n\n" +
+			"The command supports the following environment variables:\n\n" +
+			"* " + usernameEnv + " - specifies a username\n" +
+			"* " + passwordEnv + " - specifies a password\n",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdCtx.CommandName = cmd.Name()
+			err := modules.RunCmd(&cmdCtx, cmd.CommandPath(), &modulesInfo,
+				internalWatchModule, args)
+			handleCmdErr(cmd, err)
+		},
+		Args: cobra.ExactArgs(2),
+	}
+
+	watchCmd.Flags().StringVarP(&watchUser, "username", "u", "", "username")
+	watchCmd.Flags().StringVarP(&watchPassword, "password", "p", "", "password")
+	watchCmd.Flags().StringVar(&watchFormat, "format", connect.EvalFormatYAML,
+		"output format: "+strings.Join(connect.WatchFormats, ", "))
+	addConnectionFlags(watchCmd, &watchConnFlags)
+
+	return watchCmd
+}
+
+// internalWatchModule is a default watch module.
+func internalWatchModule(cmdCtx *cmdcontext.CmdCtx, args []string) error {
+	if util.Find(connect.WatchFormats, watchFormat) == -1 {
+		return util.NewArgError(fmt.Sprintf("unsupported format: %s", watchFormat))
+	}
+
+	connectCtx := connect.ConnectCtx{
+		Username: watchUser,
+		Password: watchPassword,
+	}
+	watchConnFlags.apply(&connectCtx)
+	newArgs, err := resolveConnectOpts(cmdCtx, cliOpts, &connectCtx, args[:1])
+	if err != nil {
+		return err
+	}
+
+	stop := make(chan struct{})
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-interrupt
+		close(stop)
+	}()
+
+	return connect.WatchOnTarget(connectCtx, newArgs[0], args[1], watchFormat,
+		os.Stdout, stop)
+}
@@ -30,14 +30,25 @@ var luaCodeFiles = []generateLuaCodeOpts{
 		VariablesMap: map[string]string{
 			"evalFuncBody":           "cli/connect/lua/eval_func_body.lua",
 			"getSuggestionsFuncBody": "cli/connect/lua/get_suggestions_func_body.lua",
+			"getSQLSchemaFuncBody":   "cli/connect/lua/get_sql_schema_func_body.lua",
+			"evalExprFuncBody":       "cli/connect/lua/eval_expr_func_body.lua",
+			"fileOpFuncBody":         "cli/connect/lua/file_op_func_body.lua",
+			"userFuncBody":           "cli/connect/lua/user_func_body.lua",
+			"schemaFuncBody":         "cli/connect/lua/schema_func_body.lua",
+			"importFuncBody":         "cli/connect/lua/import_func_body.lua",
+			"evalTimedFuncBody":      "cli/connect/lua/eval_timed_func_body.lua",
 		},
 	},
 	{
 		PackageName: "connector",
 		FileName:    "cli/connector/lua_code_gen.go",
 		VariablesMap: map[string]string{
-			"callFuncTmpl": "cli/connector/lua/call_func_template.lua",
-			"evalFuncTmpl": "cli/connector/lua/eval_func_template.lua",
+			"callFuncTmpl":              "cli/connector/lua/call_func_template.lua",
+			"evalFuncTmpl":              "cli/connector/lua/eval_func_template.lua",
+			"selectFuncBody":            "cli/connector/lua/select_func_body.lua",
+			"executeFuncBody":           "cli/connector/lua/execute_func_body.lua",
+			"resolveSpaceIndexFuncBody": "cli/connector/lua/resolve_space_index_func_body.lua",
+			"watchFuncBody":             "cli/connector/lua/watch_func_body.lua",
 		},
 	},
 	{
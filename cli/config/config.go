@@ -29,6 +29,7 @@ type Config struct {
 //     distfiles: path
 //   ee:
 //     credential_path: path
+//   audit_log: path
 
 // ModuleOpts is used to store all module options.
 type ModulesOpts struct {
@@ -112,4 +113,7 @@ type CliOpts struct {
 	Templates []TemplateOpts
 	// Repo is a struct used to store paths to local files.
 	Repo *RepoOpts
+	// AuditLog is a path to the audit log of statements executed with
+	// tt connect. The audit log is not written if it is empty.
+	AuditLog string `mapstructure:"audit_log" yaml:"audit_log,omitempty"`
 }
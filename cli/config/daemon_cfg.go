@@ -21,6 +21,20 @@ type DaemonCfg struct {
 //	listen_interface: string
 //	port: num
 //	pidfile: string (file name)
+//	auth_token: string
+//	auth_hmac_key: string
+//	allowed_ips: [string]
+//	tls_cert_file: path
+//	tls_key_file: path
+//	tls_ca_file: path
+//	allowed_commands: [string]
+//	jobs_history_size: num
+//	environments:
+//	  name: path (tt.yaml)
+//	supervisor: bool
+//	supervisor_interval: num (seconds)
+//	unix_socket: path
+//	unix_socket_mode: string (octal permissions)
 type DaemonOpts struct {
 	// PIDFile is name of file contains pid of daemon process.
 	PIDFile string `mapstructure:"pidfile"`
@@ -49,4 +63,43 @@ type DaemonOpts struct {
 	// RunDir is a path to directory that stores various instance
 	// runtime artifacts like console socket, PID file, etc.
 	RunDir string `mapstructure:"run_dir" yaml:"run_dir"`
+	// AuthToken is a bearer token required in requests.
+	AuthToken string `mapstructure:"auth_token" yaml:"auth_token"`
+	// AuthHMACKey is a key to check HMAC-SHA256 signatures of requests.
+	AuthHMACKey string `mapstructure:"auth_hmac_key" yaml:"auth_hmac_key"`
+	// AllowedIPs is a list of IP addresses and CIDR networks allowed
+	// to send requests. All addresses are allowed if it is empty.
+	AllowedIPs []string `mapstructure:"allowed_ips" yaml:"allowed_ips"`
+	// TLSCertFile is a path to the server TLS certificate. The server
+	// uses plain HTTP if it is empty.
+	TLSCertFile string `mapstructure:"tls_cert_file" yaml:"tls_cert_file"`
+	// TLSKeyFile is a path to the server TLS private key.
+	TLSKeyFile string `mapstructure:"tls_key_file" yaml:"tls_key_file"`
+	// TLSCAFile is a path to CA certificates to verify client certificates.
+	// Client certificates are required if it is set.
+	TLSCAFile string `mapstructure:"tls_ca_file" yaml:"tls_ca_file"`
+	// AllowedCommands is a list of tt commands available via the HTTP API.
+	// Only start, stop, status, restart and logrotate are available if it is
+	// empty.
+	AllowedCommands []string `mapstructure:"allowed_commands" yaml:"allowed_commands"`
+	// JobsHistorySize is a number of finished jobs of the HTTP API kept
+	// in memory. The default is 100.
+	JobsHistorySize int `mapstructure:"jobs_history_size" yaml:"jobs_history_size"`
+	// Environments maps names of tt environments managed by the daemon to
+	// paths of their tt.yaml files. The daemon manages the environment it
+	// is started in if it is empty.
+	Environments map[string]string `mapstructure:"environments" yaml:"environments"`
+	// Supervisor enables starting of enabled instances on the daemon start
+	// and restarting of dead instances.
+	Supervisor bool `mapstructure:"supervisor" yaml:"supervisor"`
+	// SupervisorInterval is a period in seconds of instance checks by
+	// the supervisor. The default is 5 seconds.
+	SupervisorInterval int `mapstructure:"supervisor_interval" yaml:"supervisor_interval"`
+	// UnixSocket is a path to a unix socket the daemon listens on besides
+	// the TCP port. Requests to the socket are not authenticated, access is
+	// controlled by permissions of the socket file.
+	UnixSocket string `mapstructure:"unix_socket" yaml:"unix_socket"`
+	// UnixSocketMode is permissions of the unix socket file as an octal
+	// string. The default is "0660".
+	UnixSocketMode string `mapstructure:"unix_socket_mode" yaml:"unix_socket_mode"`
 }
@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 
@@ -194,6 +195,13 @@ func updateCliOpts(cliOpts *config.CliOpts, configDir string) error {
 		}
 	}
 
+	if cliOpts.AuditLog != "" {
+		if cliOpts.AuditLog, err = adjustPathWithConfigLocation(cliOpts.AuditLog,
+			configDir, ""); err != nil {
+			return err
+		}
+	}
+
 	for i := range cliOpts.Templates {
 		if cliOpts.Templates[i].Path, err = adjustPathWithConfigLocation(
 			cliOpts.Templates[i].Path, configDir, "."); err != nil {
@@ -311,9 +319,82 @@ func GetDaemonOpts(configurePath string) (*config.DaemonOpts, error) {
 			varLogPath)
 	}
 
+	if err := adjustDaemonTLSOpts(cfg.DaemonConfig, filepath.Dir(configurePath)); err != nil {
+		return nil, fmt.Errorf("failed to parse daemon configuration: %s", err)
+	}
+
+	if err := adjustDaemonEnvironments(cfg.DaemonConfig,
+		filepath.Dir(configurePath)); err != nil {
+		return nil, fmt.Errorf("failed to parse daemon configuration: %s", err)
+	}
+
+	if err := adjustDaemonSocketOpts(cfg.DaemonConfig,
+		filepath.Dir(configurePath)); err != nil {
+		return nil, fmt.Errorf("failed to parse daemon configuration: %s", err)
+	}
+
 	return cfg.DaemonConfig, nil
 }
 
+// adjustDaemonTLSOpts checks TLS options of the daemon and makes relative
+// paths relative to the configuration directory.
+func adjustDaemonTLSOpts(opts *config.DaemonOpts, configDir string) error {
+	if (opts.TLSCertFile == "") != (opts.TLSKeyFile == "") {
+		return fmt.Errorf("tls_cert_file and tls_key_file must be specified together")
+	}
+	if opts.TLSCAFile != "" && opts.TLSCertFile == "" {
+		return fmt.Errorf("tls_ca_file requires tls_cert_file and tls_key_file")
+	}
+
+	for _, path := range []*string{&opts.TLSCertFile, &opts.TLSKeyFile, &opts.TLSCAFile} {
+		if *path != "" && !filepath.IsAbs(*path) {
+			*path = filepath.Join(configDir, *path)
+		}
+	}
+	return nil
+}
+
+// adjustDaemonEnvironments checks environments of the daemon and makes
+// relative paths to their configurations absolute.
+func adjustDaemonEnvironments(opts *config.DaemonOpts, configDir string) error {
+	for name, path := range opts.Environments {
+		if name == "" {
+			return fmt.Errorf("environment name must not be empty")
+		}
+		if strings.ContainsAny(name, "/ ") {
+			return fmt.Errorf("environment name %q must not contain slashes or spaces", name)
+		}
+		if path == "" {
+			return fmt.Errorf("configuration path of environment %q is not specified", name)
+		}
+		path, err := adjustPathWithConfigLocation(path, configDir, "")
+		if err != nil {
+			return err
+		}
+		opts.Environments[name] = path
+	}
+	return nil
+}
+
+// adjustDaemonSocketOpts checks unix socket options of the daemon and makes
+// a relative socket path relative to the configuration directory.
+func adjustDaemonSocketOpts(opts *config.DaemonOpts, configDir string) error {
+	if opts.UnixSocketMode != "" {
+		if opts.UnixSocket == "" {
+			return fmt.Errorf("unix_socket_mode requires unix_socket")
+		}
+		mode, err := strconv.ParseUint(opts.UnixSocketMode, 8, 32)
+		if err != nil || mode > 0777 {
+			return fmt.Errorf("unix_socket_mode must be octal permissions like \"0660\", got %q",
+				opts.UnixSocketMode)
+		}
+	}
+	if opts.UnixSocket != "" && !filepath.IsAbs(opts.UnixSocket) {
+		opts.UnixSocket = filepath.Join(configDir, opts.UnixSocket)
+	}
+	return nil
+}
+
 // ValidateCliOpts checks for ambiguous config options.
 func ValidateCliOpts(cliCtx *cmdcontext.CliCtx) error {
 	if cliCtx.LocalLaunchDir != "" {
@@ -363,6 +444,34 @@ func Cli(cmdCtx *cmdcontext.CmdCtx) error {
 	return configureDefaultCli(cmdCtx)
 }
 
+// CliEnvironment returns tt options and the command context of the tt
+// environment with the configuration file. Unlike Cli, it does not search
+// for the configuration and never executes a local tt, so a process could
+// use it to manage several environments.
+func CliEnvironment(configPath string) (*config.CliOpts, *cmdcontext.CmdCtx, error) {
+	cliOpts, configPath, err := GetCliOpts(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if configPath == "" {
+		return nil, nil, fmt.Errorf("configuration file is not found")
+	}
+
+	cmdCtx := &cmdcontext.CmdCtx{}
+	if cmdCtx.Cli.ConfigPath, err = filepath.Abs(configPath); err != nil {
+		return nil, nil, err
+	}
+	cmdCtx.Cli.ConfigDir = filepath.Dir(cmdCtx.Cli.ConfigPath)
+	cmdCtx.Cli.TarantoolExecutable, _ = exec.LookPath("tarantool")
+
+	if cliOpts.App != nil && cliOpts.App.BinDir != "" {
+		if err = detectLocalTarantool(cmdCtx, cliOpts); err != nil {
+			return nil, nil, err
+		}
+	}
+	return cliOpts, cmdCtx, nil
+}
+
 // ExternalCmd configures external commands.
 func ExternalCmd(rootCmd *cobra.Command, cmdCtx *cmdcontext.CmdCtx,
 	modulesInfo *modules.ModulesInfo, args []string) {
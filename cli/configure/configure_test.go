@@ -288,6 +288,7 @@ func TestUpdateCliOpts(t *testing.T) {
 			IncludeDir: "../include_dir",
 			LogMaxAge:  42,
 		},
+		AuditLog: "audit/tt.log",
 	}
 	configDir := "/etc/tarantool"
 
@@ -304,4 +305,107 @@ func TestUpdateCliOpts(t *testing.T) {
 	assert.Equal(t, 42, cliOpts.App.LogMaxAge)
 	assert.Equal(t, logMaxBackups, cliOpts.App.LogMaxBackups)
 	assert.Equal(t, logMaxSize, cliOpts.App.LogMaxSize)
+	assert.Equal(t, filepath.Join(configDir, "audit", "tt.log"), cliOpts.AuditLog)
+}
+
+func TestGetDaemonOpts_tls(t *testing.T) {
+	configDir := t.TempDir()
+	configPath := filepath.Join(configDir, "tt_daemon.yaml")
+
+	cases := []struct {
+		cfg    string
+		errMsg string
+	}{
+		{"tls_cert_file: cert.pem", "tls_cert_file and tls_key_file must be specified together"},
+		{"tls_key_file: key.pem", "tls_cert_file and tls_key_file must be specified together"},
+		{"tls_ca_file: ca.pem", "tls_ca_file requires tls_cert_file and tls_key_file"},
+		{`unix_socket_mode: "0600"`, "unix_socket_mode requires unix_socket"},
+		{"unix_socket: tt.sock\n  unix_socket_mode: rw",
+			`unix_socket_mode must be octal permissions like "0660", got "rw"`},
+		{"unix_socket: tt.sock\n  unix_socket_mode: \"01777\"",
+			`unix_socket_mode must be octal permissions like "0660", got "01777"`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.cfg, func(t *testing.T) {
+			require.NoError(t, os.WriteFile(configPath,
+				[]byte("daemon:\n  "+tc.cfg+"\n"), 0644))
+			_, err := GetDaemonOpts(configPath)
+			require.EqualError(t, err, "failed to parse daemon configuration: "+tc.errMsg)
+		})
+	}
+
+	require.NoError(t, os.WriteFile(configPath, []byte(`daemon:
+  tls_cert_file: tls/cert.pem
+  tls_key_file: /etc/tt/key.pem
+  tls_ca_file: ca.pem
+  auth_token: secret
+  allowed_ips: ["127.0.0.1", "10.0.0.0/8"]
+  jobs_history_size: 10
+  supervisor: true
+  supervisor_interval: 30
+  unix_socket: run/tt_daemon.sock
+  unix_socket_mode: "0600"
+`), 0644))
+	opts, err := GetDaemonOpts(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(configDir, "tls", "cert.pem"), opts.TLSCertFile)
+	assert.Equal(t, "/etc/tt/key.pem", opts.TLSKeyFile)
+	assert.Equal(t, filepath.Join(configDir, "ca.pem"), opts.TLSCAFile)
+	assert.Equal(t, "secret", opts.AuthToken)
+	assert.Equal(t, []string{"127.0.0.1", "10.0.0.0/8"}, opts.AllowedIPs)
+	assert.Equal(t, 10, opts.JobsHistorySize)
+	assert.True(t, opts.Supervisor)
+	assert.Equal(t, 30, opts.SupervisorInterval)
+	assert.Equal(t, filepath.Join(configDir, "run", "tt_daemon.sock"), opts.UnixSocket)
+	assert.Equal(t, "0600", opts.UnixSocketMode)
+}
+
+func TestGetDaemonOpts_environments(t *testing.T) {
+	configDir := t.TempDir()
+	configPath := filepath.Join(configDir, "tt_daemon.yaml")
+
+	cases := []struct {
+		cfg    string
+		errMsg string
+	}{
+		{`"": tt.yaml`, "environment name must not be empty"},
+		{"prod/1: tt.yaml", `environment name "prod/1" must not contain slashes or spaces`},
+		{`prod: ""`, `configuration path of environment "prod" is not specified`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.cfg, func(t *testing.T) {
+			require.NoError(t, os.WriteFile(configPath,
+				[]byte("daemon:\n  environments:\n    "+tc.cfg+"\n"), 0644))
+			_, err := GetDaemonOpts(configPath)
+			require.EqualError(t, err, "failed to parse daemon configuration: "+tc.errMsg)
+		})
+	}
+
+	require.NoError(t, os.WriteFile(configPath, []byte(`daemon:
+  environments:
+    prod: prod/tt.yaml
+    dev: /opt/dev/tt.yaml
+`), 0644))
+	opts, err := GetDaemonOpts(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"prod": filepath.Join(configDir, "prod", "tt.yaml"),
+		"dev":  "/opt/dev/tt.yaml",
+	}, opts.Environments)
+}
+
+func TestCliEnvironment(t *testing.T) {
+	configDir := t.TempDir()
+	configPath := filepath.Join(configDir, "tt.yaml")
+
+	_, _, err := CliEnvironment(configPath)
+	require.EqualError(t, err, "configuration file is not found")
+
+	require.NoError(t, os.WriteFile(configPath, []byte("tt:\n  app:\n    run_dir: run\n"),
+		0644))
+	cliOpts, cmdCtx, err := CliEnvironment(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, configPath, cmdCtx.Cli.ConfigPath)
+	assert.Equal(t, configDir, cmdCtx.Cli.ConfigDir)
+	assert.Equal(t, filepath.Join(configDir, "run"), cliOpts.App.RunDir)
 }
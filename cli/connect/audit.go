@@ -0,0 +1,144 @@
+package connect
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/tarantool/tt/cli/connector"
+)
+
+// auditRecord describes a record of the audit log about an executed statement.
+type auditRecord struct {
+	// Time is a time of the statement start.
+	Time time.Time `json:"time"`
+	// User is a name of the OS user.
+	User string `json:"user"`
+	// Target is a connection target without a password.
+	Target string `json:"target"`
+	// Language is a language of the statement.
+	Language string `json:"language"`
+	// Statement is the executed statement without passwords.
+	Statement string `json:"statement"`
+	// Success is true if the statement is executed without an error.
+	Success bool `json:"success"`
+	// Error is an error message of a failed statement.
+	Error string `json:"error,omitempty"`
+	// DurationMs is a duration of the statement execution in milliseconds.
+	DurationMs float64 `json:"duration_ms"`
+}
+
+// uriPasswordRe matches a password of a connection string.
+var uriPasswordRe = regexp.MustCompile(`([\w.-]+):[^\s@/:'"]+@`)
+
+// maskPasswords replaces passwords of connection strings in the text.
+func maskPasswords(text string) string {
+	return uriPasswordRe.ReplaceAllString(text, "$1:***@")
+}
+
+// auditLog appends records about executed statements to a file. A nil audit
+// log writes nothing.
+type auditLog struct {
+	// filepath is a path to the audit log file.
+	filepath string
+	// user is a name of the OS user.
+	user string
+}
+
+// newAuditLog creates an audit log writing to the file and checks that
+// the file could be opened for append. It returns nil if the path is empty.
+func newAuditLog(path string) (*auditLog, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	audit := &auditLog{filepath: path}
+	if usr, err := user.Current(); err == nil {
+		audit.user = usr.Username
+	} else {
+		audit.user = os.Getenv("USER")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create audit log directory: %s", err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %s", err)
+	}
+	file.Close()
+	return audit, nil
+}
+
+// write appends a record about the statement. The error is a transport error
+// or an error of the statement execution.
+func (audit *auditLog) write(target string, lang Language, statement string,
+	start time.Time, duration time.Duration, execErr error) error {
+	if audit == nil {
+		return nil
+	}
+
+	if lang == DefaultLanguage {
+		lang = LuaLanguage
+	}
+	record := auditRecord{
+		Time:       start,
+		User:       audit.user,
+		Target:     maskPasswords(target),
+		Language:   lang.String(),
+		Statement:  maskPasswords(statement),
+		Success:    execErr == nil,
+		DurationMs: float64(duration) / float64(time.Millisecond),
+	}
+	if execErr != nil {
+		record.Error = execErr.Error()
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %s", err)
+	}
+
+	// The whole record is written with a single write call to keep appends
+	// from concurrent consoles atomic.
+	file, err := os.OpenFile(audit.filepath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %s", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to append to audit log: %s", err)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync audit log: %s", err)
+	}
+	return nil
+}
+
+// getAuditTarget returns a connection target for the audit log.
+func getAuditTarget(connOpts connector.ConnectOpts) string {
+	if connOpts.Username != "" {
+		return connOpts.Username + "@" + connOpts.Address
+	}
+	return connOpts.Address
+}
+
+// getEvalResultError returns an error from a YAML response of the console
+// if the statement is failed.
+func getEvalResultError(data string) error {
+	var decoded []map[string]interface{}
+	if err := yaml.Unmarshal([]byte(data), &decoded); err != nil || len(decoded) != 1 {
+		return nil
+	}
+	if msg, ok := decoded[0]["error"]; ok && len(decoded[0]) == 1 {
+		return fmt.Errorf("%v", msg)
+	}
+	return nil
+}
@@ -0,0 +1,466 @@
+package connect
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	"github.com/c-bata/go-prompt"
+	"gopkg.in/yaml.v3"
+
+	"github.com/tarantool/tt/cli/connector"
+)
+
+const (
+	startOfYAMLOutput = "---\n"
+	endOfYAMLOutput   = "...\n"
+)
+
+// consoleCmd describes a console meta-command.
+type consoleCmd struct {
+	// aliases is a list of the command names. The first one is used in help.
+	aliases []string
+	// args describes arguments of the command for the help output.
+	args string
+	// help is a short description of the command.
+	help string
+	// rawArgs passes the rest of the input as a single argument, so
+	// the whitespace in it is kept.
+	rawArgs bool
+	// run executes the command with the arguments.
+	run func(console *Console, args []string) error
+	// argsSuggestions returns a list of suggestions for the command
+	// arguments. Could be nil.
+	argsSuggestions func(console *Console) []string
+}
+
+// shortcut describes a key binding of the console.
+type shortcut struct {
+	keys string
+	help string
+}
+
+// consoleCmds is a list of supported console meta-commands.
+var consoleCmds []consoleCmd
+
+// shortcuts is a list of supported console key bindings.
+var shortcuts = []shortcut{
+	{"Ctrl + C", "interrupt the current unfinished expression"},
+	{"Ctrl + D", "quit the console on an empty line"},
+	{"Ctrl + A, Home", "move to the beginning of the line"},
+	{"Ctrl + E, End", "move to the end of the line"},
+	{"Alt + B, Ctrl + Left", "move one word left"},
+	{"Alt + F, Ctrl + Right", "move one word right"},
+	{"Ctrl + W", "delete the word before the cursor"},
+	{"Ctrl + K", "delete text after the cursor"},
+	{"Ctrl + U", "delete text before the cursor"},
+	{"Ctrl + L", "clear the screen"},
+	{"Up, Down", "move between lines of a multiline statement or navigate through the history"},
+	{"Alt + Enter", "insert a new line into the statement"},
+	{"Ctrl + R", "search the history backward for the typed text, press again for the next match"},
+	{"Tab", "complete the current word"},
+}
+
+func init() {
+	consoleCmds = []consoleCmd{
+		{
+			aliases: []string{"\\help", "\\h", "\\?"},
+			help:    "show this help",
+			run:     runHelpCmd,
+		},
+		{
+			aliases: []string{"\\shortcuts"},
+			help:    "show available hotkeys and shortcuts",
+			run:     runShortcutsCmd,
+		},
+		{
+			aliases: []string{"\\quit", "\\q"},
+			help:    "quit the console",
+			run:     runQuitCmd,
+		},
+		{
+			aliases: []string{"\\connect"},
+			args:    "<URI>",
+			help:    "connect to another instance",
+			run:     runConnectCmd,
+		},
+		{
+			aliases: []string{"\\info"},
+			help:    "show information about the current connection",
+			run:     runInfoCmd,
+		},
+		{
+			aliases: []string{"\\history"},
+			args:    "[PATTERN]",
+			help:    "show the commands history, filtered by the pattern if specified",
+			run:     runHistoryCmd,
+		},
+		{
+			aliases: []string{"\\refresh"},
+			help:    "refresh the schema cache used by the SQL auto-completion",
+			run:     runRefreshCmd,
+		},
+		{
+			aliases: []string{"\\watch"},
+			args:    "<KEY>",
+			help:    "print changes of a key set with box.broadcast() until Ctrl + C is pressed",
+			run:     runWatchCmd,
+		},
+		{
+			aliases: []string{"\\x"},
+			help:    "toggle expanded output",
+			run:     runExpandedCmd,
+		},
+		{
+			aliases: []string{"\\timing"},
+			help:    "toggle printing of round-trip and server execution time of statements",
+			run:     runTimingCmd,
+		},
+		{
+			aliases: []string{"\\explain"},
+			args:    "<STATEMENT>",
+			help:    "show a query plan of the SQL statement",
+			rawArgs: true,
+			run:     runExplainCmd,
+		},
+		{
+			aliases: []string{strings.TrimSpace(setLanguagePrefix)},
+			args:    "<LANGUAGE>",
+			help:    "set language lua or sql",
+			run:     runSetLanguageCmd,
+			argsSuggestions: func(console *Console) []string {
+				return []string{LuaLanguage.String(), SQLLanguage.String()}
+			},
+		},
+	}
+}
+
+// parseConsoleCmd parses the input as a console meta-command. It returns the
+// command, its arguments and true if the input is a known command.
+func parseConsoleCmd(in string) (consoleCmd, []string, bool) {
+	trimmed := strings.TrimSpace(in)
+	if !strings.HasPrefix(trimmed, "\\") {
+		return consoleCmd{}, nil, false
+	}
+
+	for _, cmd := range consoleCmds {
+		for _, alias := range cmd.aliases {
+			if trimmed == alias {
+				return cmd, []string{}, true
+			}
+			if strings.HasPrefix(trimmed, alias+" ") {
+				rest := strings.TrimPrefix(trimmed, alias)
+				if cmd.rawArgs {
+					return cmd, []string{strings.TrimSpace(rest)}, true
+				}
+				return cmd, strings.Fields(rest), true
+			}
+		}
+	}
+
+	return consoleCmd{}, nil, false
+}
+
+// getConsoleCmdSuggestions returns suggestions for a console meta-command
+// input. Suggestions are cut to the start of the last word, because
+// go-prompt replaces only the last word by the selected suggestion.
+func getConsoleCmdSuggestions(console *Console, in prompt.Document) []prompt.Suggest {
+	text := in.TextBeforeCursor()
+	if !strings.HasPrefix(text, "\\") {
+		return nil
+	}
+	lastWordStart := in.FindStartOfPreviousWordUntilSeparator(tarantoolWordSeparators)
+
+	suggestions := []prompt.Suggest{}
+	for _, cmd := range consoleCmds {
+		for _, alias := range cmd.aliases {
+			if strings.HasPrefix(alias, text) && alias != text {
+				suggestions = append(suggestions, prompt.Suggest{
+					Text:        alias[lastWordStart:],
+					Description: cmd.help,
+				})
+			} else if strings.HasPrefix(text, alias+" ") && cmd.argsSuggestions != nil {
+				arg := strings.TrimLeft(strings.TrimPrefix(text, alias), " ")
+				if strings.Contains(arg, " ") {
+					continue
+				}
+				for _, argText := range cmd.argsSuggestions(console) {
+					if strings.HasPrefix(argText, arg) {
+						suggestions = append(suggestions, prompt.Suggest{
+							Text: argText,
+						})
+					}
+				}
+			}
+		}
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		return suggestions[i].Text < suggestions[j].Text
+	})
+	return suggestions
+}
+
+// runHelpCmd prints a list of the console meta-commands.
+func runHelpCmd(console *Console, args []string) error {
+	lines := make([][2]string, 0, len(consoleCmds))
+	width := 0
+	for _, cmd := range consoleCmds {
+		usage := strings.Join(cmd.aliases, ", ")
+		if cmd.args != "" {
+			usage += " " + cmd.args
+		}
+		if len(usage) > width {
+			width = len(usage)
+		}
+		lines = append(lines, [2]string{usage, cmd.help})
+	}
+
+	fmt.Println("Available commands:")
+	fmt.Println()
+	for _, line := range lines {
+		fmt.Printf("  %-*s  %s\n", width, line[0], line[1])
+	}
+	fmt.Println()
+	return nil
+}
+
+// runShortcutsCmd prints a list of the console key bindings.
+func runShortcutsCmd(console *Console, args []string) error {
+	width := 0
+	for _, s := range shortcuts {
+		if len(s.keys) > width {
+			width = len(s.keys)
+		}
+	}
+
+	fmt.Println("Available hotkeys and shortcuts:")
+	fmt.Println()
+	for _, s := range shortcuts {
+		fmt.Printf("  %-*s  %s\n", width, s.keys, s.help)
+	}
+	fmt.Println()
+	return nil
+}
+
+// runQuitCmd marks the console as finished.
+func runQuitCmd(console *Console, args []string) error {
+	console.quit = true
+	return nil
+}
+
+// runConnectCmd replaces the console connection by a new one.
+func runConnectCmd(console *Console, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: \\connect <URI>")
+	}
+
+	connOpts := connector.MakeConnectOpts(args[0], "", "")
+	// Keep the timeouts and the retry options of the current connection.
+	connOpts.ConnectTimeout = console.connOpts.ConnectTimeout
+	connOpts.RequestTimeout = console.connOpts.RequestTimeout
+	connOpts.RetryCount = console.connOpts.RetryCount
+	connOpts.RetryBackoff = console.connOpts.RetryBackoff
+
+	conn, err := connectConsole(connOpts, console.language)
+	if err != nil {
+		return err
+	}
+
+	console.conn.Close()
+	console.conn = conn
+	console.connOpts = connOpts
+	console.sqlSchema = nil
+
+	console.title = ""
+	setTitle(console)
+	setPrefix(console)
+
+	fmt.Printf("Connected to %s\n", console.title)
+	return nil
+}
+
+// runWatchCmd prints changes of the key until Ctrl + C is pressed. A separate
+// connection is used, so the console connection is not blocked.
+func runWatchCmd(console *Console, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: \\watch <KEY>")
+	}
+
+	conn, err := connector.Connect(console.connOpts)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %s", err)
+	}
+	defer conn.Close()
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	go func() {
+		select {
+		case <-interrupt:
+			close(stop)
+		case <-done:
+		}
+	}()
+
+	return watch(conn, args[0], EvalFormatYAML, os.Stdout, stop)
+}
+
+// runInfoCmd prints information about the current connection.
+func runInfoCmd(console *Console, args []string) error {
+	version := "unknown"
+	var response []string
+	opts := connector.RequestOpts{
+		ResData: &response,
+	}
+	if _, err := console.conn.Eval("return box.info.version", []interface{}{},
+		opts); err == nil && len(response) > 0 {
+		version = response[0]
+	}
+
+	language := console.language
+	if language == DefaultLanguage {
+		language = LuaLanguage
+	}
+
+	onOff := func(value bool) string {
+		if value {
+			return "on"
+		}
+		return "off"
+	}
+
+	fmt.Printf("Connection:      %s (%s://%s)\n", console.title,
+		console.connOpts.Network, console.connOpts.Address)
+	if console.connOpts.Username != "" {
+		fmt.Printf("User:            %s\n", console.connOpts.Username)
+	}
+	fmt.Printf("Language:        %s\n", language)
+	fmt.Printf("Server version:  %s\n", version)
+	fmt.Printf("Expanded output: %s\n", onOff(console.expanded))
+	fmt.Printf("Timing:          %s\n", onOff(console.timing))
+	fmt.Printf("Read-only:       %s\n", onOff(console.readOnly))
+	return nil
+}
+
+// runHistoryCmd prints the commands history.
+func runHistoryCmd(console *Console, args []string) error {
+	if console.history == nil {
+		return fmt.Errorf("the history is not available")
+	}
+
+	for _, entry := range console.history.search(strings.Join(args, " ")) {
+		fmt.Println(entry)
+	}
+	return nil
+}
+
+// runRefreshCmd reloads the schema used by the SQL auto-completion.
+func runRefreshCmd(console *Console, args []string) error {
+	schema, err := loadSQLSchema(console.conn)
+	if err != nil {
+		return fmt.Errorf("failed to refresh the schema: %s", err)
+	}
+	console.sqlSchema = schema
+	fmt.Printf("Schema is refreshed: %d spaces found.\n", len(schema.tables))
+	return nil
+}
+
+// runExpandedCmd toggles the expanded output.
+func runExpandedCmd(console *Console, args []string) error {
+	console.expanded = !console.expanded
+	if console.expanded {
+		fmt.Println("Expanded output is on.")
+	} else {
+		fmt.Println("Expanded output is off.")
+	}
+	return nil
+}
+
+// runTimingCmd toggles printing of the statements execution time.
+func runTimingCmd(console *Console, args []string) error {
+	console.timing = !console.timing
+	if console.timing {
+		fmt.Println("Timing is on.")
+	} else {
+		fmt.Println("Timing is off.")
+	}
+	return nil
+}
+
+// runExplainCmd prints a query plan of the SQL statement.
+func runExplainCmd(console *Console, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: \\explain <STATEMENT>")
+	}
+
+	statement := strings.TrimSpace(strings.TrimSuffix(args[0], ";"))
+	result, err := console.conn.Execute("EXPLAIN QUERY PLAN "+statement,
+		[]interface{}{}, connector.RequestOpts{})
+	if err != nil {
+		return fmt.Errorf("failed to explain the statement: %s", err)
+	}
+	fmt.Print(string(FormatQueryPlan(result)))
+	return nil
+}
+
+// runSetLanguageCmd changes the console language.
+func runSetLanguageCmd(console *Console, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: %s<LANGUAGE>", setLanguagePrefix)
+	}
+
+	lang, ok := ParseLanguage(args[0])
+	if !ok {
+		return fmt.Errorf("unsupported language: %s", args[0])
+	}
+	if console.readOnly && lang != SQLLanguage {
+		return fmt.Errorf("only sql language is available in read-only mode")
+	}
+
+	if err := ChangeLanguage(console.conn, lang); err != nil {
+		return fmt.Errorf("failed to change language: %s", err)
+	}
+	console.language = lang
+	return nil
+}
+
+// FormatExpanded re-encodes a YAML response of the instance in the block
+// style: every element of a sequence or a mapping is placed on its own line.
+// The input is returned as is if it is not a YAML document.
+func FormatExpanded(data string) string {
+	if !strings.HasPrefix(data, startOfYAMLOutput) {
+		return data
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(data), &node); err != nil {
+		return data
+	}
+	setBlockStyle(&node)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return data
+	}
+	encoder.Close()
+
+	return startOfYAMLOutput + buf.String() + endOfYAMLOutput
+}
+
+// setBlockStyle removes the flow style from the node and all its children.
+func setBlockStyle(node *yaml.Node) {
+	node.Style &^= yaml.FlowStyle
+	for _, child := range node.Content {
+		setBlockStyle(child)
+	}
+}
@@ -0,0 +1,131 @@
+package connect
+
+import (
+	"testing"
+	"time"
+
+	"github.com/c-bata/go-prompt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConsoleCmd(t *testing.T) {
+	cases := []struct {
+		in    string
+		alias string
+		args  []string
+	}{
+		{"\\help", "\\help", []string{}},
+		{"  \\h  ", "\\help", []string{}},
+		{"\\?", "\\help", []string{}},
+		{"\\q", "\\quit", []string{}},
+		{"\\quit", "\\quit", []string{}},
+		{"\\shortcuts", "\\shortcuts", []string{}},
+		{"\\connect localhost:3301", "\\connect", []string{"localhost:3301"}},
+		{"\\info", "\\info", []string{}},
+		{"\\x", "\\x", []string{}},
+		{"\\timing", "\\timing", []string{}},
+		{"\\explain SELECT  * FROM t WHERE a = 'x  y' ",
+			"\\explain", []string{"SELECT  * FROM t WHERE a = 'x  y'"}},
+		{"\\set language sql", "\\set language", []string{"sql"}},
+		{"\\set language  lua ", "\\set language", []string{"lua"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			cmd, args, ok := parseConsoleCmd(tc.in)
+			require.True(t, ok)
+			assert.Equal(t, tc.alias, cmd.aliases[0])
+			assert.Equal(t, tc.args, args)
+		})
+	}
+}
+
+func TestParseConsoleCmd_unknown(t *testing.T) {
+	cases := []string{
+		"",
+		"help",
+		"return 1",
+		"\\",
+		"\\helpme",
+		"\\quitx",
+		"\\set output lua",
+		"\\set languages sql",
+	}
+
+	for _, tc := range cases {
+		t.Run(tc, func(t *testing.T) {
+			_, _, ok := parseConsoleCmd(tc)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func getSuggestionsTexts(in string) []string {
+	buf := prompt.NewBuffer()
+	buf.InsertText(in, false, true)
+
+	texts := []string{}
+	for _, suggestion := range getConsoleCmdSuggestions(&Console{}, *buf.Document()) {
+		texts = append(texts, suggestion.Text)
+	}
+	return texts
+}
+
+func TestGetConsoleCmdSuggestions(t *testing.T) {
+	cases := []struct {
+		in       string
+		expected []string
+	}{
+		{"return", []string{}},
+		{"\\q", []string{"quit"}},
+		{"\\s", []string{"set language", "shortcuts"}},
+		{"\\set l", []string{"language"}},
+		{"\\set language ", []string{"lua", "sql"}},
+		{"\\set language s", []string{"sql"}},
+		{"\\set language sql ", []string{}},
+		{"\\conn", []string{"connect"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			assert.Equal(t, tc.expected, getSuggestionsTexts(tc.in))
+		})
+	}
+}
+
+func TestFormatExpanded(t *testing.T) {
+	cases := []struct {
+		in       string
+		expected string
+	}{
+		{
+			"---\n- [1, 'a', {'b': 2}]\n...\n",
+			"---\n- - 1\n  - 'a'\n  - 'b': 2\n...\n",
+		},
+		{
+			"---\n- {'x': 1, 'a': [2, 3]}\n...\n",
+			"---\n- 'x': 1\n  'a':\n    - 2\n    - 3\n...\n",
+		},
+		{
+			"---\n- 1\n- 2\n...\n",
+			"---\n- 1\n- 2\n...\n",
+		},
+		{
+			"1;",
+			"1;",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			assert.Equal(t, tc.expected, FormatExpanded(tc.in))
+		})
+	}
+}
+
+func TestFormatTiming(t *testing.T) {
+	assert.Equal(t, "Time: 1.500 ms", formatTiming(1500*time.Microsecond, nil))
+	assert.Equal(t, "Time: 2.000 ms (server: 0.250 ms)",
+		formatTiming(2*time.Millisecond, []interface{}{0.00025}))
+}
@@ -6,7 +6,9 @@ import (
 	"os"
 	"path"
 	"syscall"
+	"time"
 
+	"github.com/apex/log"
 	"github.com/tarantool/tt/cli/connector"
 	"golang.org/x/crypto/ssh/terminal"
 	"gopkg.in/yaml.v2"
@@ -24,6 +26,23 @@ type ConnectCtx struct {
 	Language Language
 	// Interactive mode is used.
 	Interactive bool
+	// HistoryPerTarget enables a separate commands history for the
+	// connection target.
+	HistoryPerTarget bool
+	// ConnectTimeout is a timeout for establishing a connection.
+	ConnectTimeout time.Duration
+	// RequestTimeout is a timeout for a request.
+	RequestTimeout time.Duration
+	// RetryCount is a number of connection retries.
+	RetryCount int
+	// RetryBackoff is a delay before the first connection retry.
+	RetryBackoff time.Duration
+	// ReadOnly rejects SQL statements that could modify data or the schema
+	// before sending them.
+	ReadOnly bool
+	// AuditLog is a path to the audit log of executed statements. The audit
+	// log is not written if it is empty.
+	AuditLog string
 }
 
 const (
@@ -34,7 +53,12 @@ const (
 func getConnOpts(connString string, connCtx ConnectCtx) connector.ConnectOpts {
 	username := connCtx.Username
 	password := connCtx.Password
-	return connector.MakeConnectOpts(connString, username, password)
+	connOpts := connector.MakeConnectOpts(connString, username, password)
+	connOpts.ConnectTimeout = connCtx.ConnectTimeout
+	connOpts.RequestTimeout = connCtx.RequestTimeout
+	connOpts.RetryCount = connCtx.RetryCount
+	connOpts.RetryBackoff = connCtx.RetryBackoff
+	return connOpts
 }
 
 // getEvalCmd returns a command from the input source (file or stdin).
@@ -74,7 +98,17 @@ func Connect(connectCtx ConnectCtx, args []string) error {
 	connString := args[0]
 	connOpts := getConnOpts(connString, connectCtx)
 
-	if err := runConsole(connOpts, "", connectCtx.Language); err != nil {
+	consoleOpts := ConsoleOpts{
+		ConnOpts: connOpts,
+		Language: connectCtx.Language,
+		ReadOnly: connectCtx.ReadOnly,
+		AuditLog: connectCtx.AuditLog,
+	}
+	if connectCtx.HistoryPerTarget {
+		consoleOpts.HistoryTarget = connString
+	}
+
+	if err := runConsole(consoleOpts); err != nil {
 		return fmt.Errorf("failed to run interactive console: %s", err)
 	}
 
@@ -91,6 +125,25 @@ func Eval(connectCtx ConnectCtx, args []string) ([]byte, error) {
 		return nil, err
 	}
 
+	audit, err := newAuditLog(connectCtx.AuditLog)
+	if err != nil {
+		return nil, err
+	}
+	writeAudit := func(start time.Time, duration time.Duration, execErr error) {
+		err := audit.write(getAuditTarget(connOpts), connectCtx.Language, command,
+			start, duration, execErr)
+		if err != nil {
+			log.Warnf("Failed to write audit log: %s", err)
+		}
+	}
+
+	if connectCtx.ReadOnly {
+		if err := checkReadOnlySQL(command); err != nil {
+			writeAudit(time.Now(), 0, err)
+			return nil, err
+		}
+	}
+
 	// Connecting to the instance.
 	conn, err := connector.Connect(connOpts)
 	if err != nil {
@@ -106,10 +159,18 @@ func Eval(connectCtx ConnectCtx, args []string) ([]byte, error) {
 	}
 
 	// Execution of the command.
+	start := time.Now()
 	response, err := conn.Eval(evalFuncBody,
 		[]interface{}{command},
 		connector.RequestOpts{},
 	)
+	execErr := err
+	if err == nil && len(response) > 0 {
+		if data, ok := response[0].(string); ok {
+			execErr = getEvalResultError(data)
+		}
+	}
+	writeAudit(start, time.Since(start), execErr)
 	if err != nil {
 		return nil, err
 	}
@@ -127,8 +188,8 @@ func Eval(connectCtx ConnectCtx, args []string) ([]byte, error) {
 }
 
 // runConsole run a new console.
-func runConsole(connOpts connector.ConnectOpts, title string, lang Language) error {
-	console, err := NewConsole(connOpts, title, lang)
+func runConsole(opts ConsoleOpts) error {
+	console, err := NewConsole(opts)
 	if err != nil {
 		return fmt.Errorf("failed to create new console: %s", err)
 	}
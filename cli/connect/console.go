@@ -3,7 +3,6 @@ package connect
 import (
 	"bufio"
 	"fmt"
-	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -11,6 +10,7 @@ import (
 	"strings"
 	"syscall"
 	"time"
+	"unicode/utf8"
 
 	"github.com/adam-hanna/arrayOperations"
 	"github.com/apex/log"
@@ -19,27 +19,36 @@ import (
 
 	"github.com/c-bata/go-prompt"
 	"github.com/tarantool/tt/cli/connector"
-	"github.com/tarantool/tt/cli/util"
 )
 
 // EvalFunc defines a function type for evaluating an expression via connection.
 type EvalFunc func(console *Console, funcBodyFmt string, args ...interface{}) (interface{}, error)
 
 const (
-	HistoryFileName = ".tarantool_history"
+	HistoryFileName = ".tt_history"
+	HistoryDirName  = ".tarantool_history.d"
+	// TarantoolHistoryFileName is a history file of the tarantool console.
+	// It is line-oriented, so it is only read once to import the commands.
+	TarantoolHistoryFileName = ".tarantool_history"
 
 	MaxLivePrefixIndent = 15
 	MaxHistoryLines     = 10000
+
+	// reconnectRetryCount is a minimum number of connection retries on
+	// reconnect after the connection is lost.
+	reconnectRetryCount = 5
 )
 
 var (
 	ControlLeftBytes  []byte
 	ControlRightBytes []byte
+	AltEnterBytes     []byte
 )
 
 func init() {
 	ControlLeftBytes = []byte{0x1b, 0x62}
 	ControlRightBytes = []byte{0x1b, 0x66}
+	AltEnterBytes = []byte{0x1b, 0x0d}
 }
 
 // Console describes the console connected to the tarantool instance.
@@ -50,18 +59,37 @@ type Console struct {
 
 	language Language
 
-	historyFile     *os.File
-	historyFilePath string
-	historyLines    []string
+	history    *commandHistory
+	inputLines []string
+	// historySearch is a state of the Ctrl + R history search.
+	historySearch historySearch
 
 	prefix            string
 	livePrefixEnabled bool
 	livePrefix        string
+	incompletePrefix  string
 	livePrefixFunc    func() (string, bool)
 
+	// interactive is true if the input is read from a terminal. An
+	// incomplete statement is edited as a whole multiline buffer then.
+	interactive bool
+	// buffer is the current input of the prompt.
+	buffer prompt.Document
+	// initialText is a text of the prompt buffer after a restart.
+	initialText string
+
 	connOpts connector.ConnectOpts
 	conn     connector.Connector
 
+	audit *auditLog
+
+	expanded      bool
+	timing        bool
+	readOnly      bool
+	quit          bool
+	restartPrompt bool
+	sqlSchema     *sqlSchema
+
 	executor   func(in string)
 	completer  func(in prompt.Document) []prompt.Suggest
 	validators map[Language]ValidateCloser
@@ -69,32 +97,50 @@ type Console struct {
 	prompt *prompt.Prompt
 }
 
+// ConsoleOpts describes options of the console.
+type ConsoleOpts struct {
+	// ConnOpts contains options for the connection.
+	ConnOpts connector.ConnectOpts
+	// Title is a title of the console. The address is used if it is empty.
+	Title string
+	// Language is a language of the console.
+	Language Language
+	// HistoryTarget is a connection target to keep a separate history for.
+	// The common history file is used if it is empty.
+	HistoryTarget string
+	// ReadOnly rejects SQL statements that could modify data or the schema
+	// before sending them.
+	ReadOnly bool
+	// AuditLog is a path to the audit log of executed statements. The audit
+	// log is not written if it is empty.
+	AuditLog string
+}
+
 // NewConsole creates a new console connected to the tarantool instance.
-func NewConsole(connOpts connector.ConnectOpts, title string, lang Language) (*Console, error) {
+func NewConsole(opts ConsoleOpts) (*Console, error) {
+	connOpts := opts.ConnOpts
+	lang := opts.Language
 	console := &Console{
-		title:    title,
+		title:    opts.Title,
 		connOpts: connOpts,
 		language: lang,
+		readOnly: opts.ReadOnly,
 	}
 
 	var err error
 
+	if console.audit, err = newAuditLog(opts.AuditLog); err != nil {
+		return nil, err
+	}
+
 	// Load Tarantool console history from file.
-	if err := loadHistory(console); err != nil {
+	if err := loadHistory(console, opts.HistoryTarget); err != nil {
 		log.Debugf("Failed to load Tarantool console history: %s", err)
 	}
 
 	// Connect to specified address.
-	console.conn, err = connector.Connect(connOpts)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect: %s", err)
-	}
-
-	// Change a language.
-	if lang != DefaultLanguage {
-		if err := ChangeLanguage(console.conn, lang); err != nil {
-			return nil, fmt.Errorf("unable to change a language: %s", err)
-		}
+	if console.conn, err = connectConsole(connOpts, lang); err != nil {
+		return nil, err
 	}
 
 	// Initialize user commands executor.
@@ -126,23 +172,45 @@ func (console *Console) Run() error {
 		for pipedInputScanner.Scan() {
 			line := pipedInputScanner.Text()
 			console.executor(line)
+			if console.quit {
+				break
+			}
 		}
 		return nil
 	} else {
 		log.Infof("Connected to %s\n", console.title)
 	}
+	console.interactive = true
+
+	for {
+		// Get options for Prompt instance.
+		options := getPromptOptions(console)
+		if console.initialText != "" {
+			// Continue editing of the incomplete statement.
+			options = append(options, prompt.OptionInitialBufferText(console.initialText))
+			buf := prompt.NewBuffer()
+			buf.InsertText(console.initialText, false, true)
+			console.buffer = *buf.Document()
+			console.initialText = ""
+		}
 
-	// Get options for Prompt instance.
-	options := getPromptOptions(console)
+		// Create Prompt instance.
+		console.prompt = prompt.New(
+			console.executor,
+			console.completer,
+			options...,
+		)
 
-	// Create Prompt instance.
-	console.prompt = prompt.New(
-		console.executor,
-		console.completer,
-		options...,
-	)
+		console.prompt.Run()
 
-	console.prompt.Run()
+		// The prompt is restarted to reload its history with a completed
+		// multiline command as a single entry or to continue editing of an
+		// incomplete statement.
+		if console.quit || !console.restartPrompt {
+			break
+		}
+		console.restartPrompt = false
+	}
 
 	// Sets the terminal modes to “sane” values to workaround
 	// bug https://github.com/c-bata/go-prompt/issues/228
@@ -155,10 +223,6 @@ func (console *Console) Run() error {
 
 // Close frees up resources used by the console.
 func (console *Console) Close() {
-	if console.historyFile != nil {
-		console.historyFile.Close()
-		console.historyFile = nil
-	}
 	for _, v := range console.validators {
 		v.Close()
 	}
@@ -168,50 +232,65 @@ func (console *Console) Close() {
 	}
 }
 
-func loadHistory(console *Console) error {
-	var err error
-
-	homeDir, err := util.GetHomeDir()
+// connectConsole connects to the tarantool instance and changes the
+// language of the connection.
+func connectConsole(connOpts connector.ConnectOpts,
+	lang Language) (connector.Connector, error) {
+	conn, err := connector.Connect(connOpts)
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %s", err)
+		return nil, fmt.Errorf("failed to connect: %s", err)
 	}
 
-	console.historyFilePath = filepath.Join(homeDir, HistoryFileName)
+	if lang != DefaultLanguage {
+		if err := ChangeLanguage(conn, lang); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("unable to change a language: %s", err)
+		}
+	}
+	return conn, nil
+}
 
-	console.historyLines, err = util.GetLastNLines(console.historyFilePath, MaxHistoryLines)
+// reconnect replaces a lost connection by a new one to the same address.
+// A failed connection attempt is retried at least reconnectRetryCount times.
+func (console *Console) reconnect() error {
+	connOpts := console.connOpts
+	if connOpts.RetryCount < reconnectRetryCount {
+		connOpts.RetryCount = reconnectRetryCount
+	}
+
+	conn, err := connectConsole(connOpts, console.language)
 	if err != nil {
-		return fmt.Errorf("failed to read history from file: %s", err)
+		return err
 	}
 
-	// Open history file for appending.
-	// See https://unix.stackexchange.com/questions/346062/concurrent-writing-to-a-log-file-from-many-processes
-	console.historyFile, err = os.OpenFile(
-		console.historyFilePath,
-		os.O_APPEND|os.O_CREATE|os.O_WRONLY,
-		0644,
-	)
+	console.conn.Close()
+	console.conn = conn
+	console.sqlSchema = nil
+	return nil
+}
 
+func loadHistory(console *Console, target string) error {
+	historyFilePath, err := getHistoryFilePath(target)
 	if err != nil {
-		log.Debugf("Failed to open history file for append: %s", err)
+		return err
 	}
 
-	return nil
+	_, statErr := os.Stat(historyFilePath)
+	console.history, err = newCommandHistory(historyFilePath, MaxHistoryLines)
+	if err != nil || target != "" || !os.IsNotExist(statErr) {
+		return err
+	}
+	return console.history.importLines(
+		filepath.Join(filepath.Dir(historyFilePath), TarantoolHistoryFileName))
 }
 
 func getExecutor(console *Console) prompt.Executor {
 	executor := func(in string) {
+		console.buffer = prompt.Document{}
 		if console.input == "" {
-			trimmed := strings.TrimSpace(in)
-			if strings.HasPrefix(trimmed, setLanguagePrefix) {
-				newLang := strings.TrimPrefix(trimmed, setLanguagePrefix)
-				if lang, ok := ParseLanguage(newLang); ok {
-					if err := ChangeLanguage(console.conn, lang); err != nil {
-						log.Warnf("Failed to change language: %s", err)
-					} else {
-						console.language = lang
-					}
-				} else {
-					log.Warnf("Unsupported language: %s", newLang)
+			if cmd, args, ok := parseConsoleCmd(in); ok {
+				if err := cmd.run(console, args); err != nil {
+					log.Warnf("%s", err)
 				}
 				return
 			}
@@ -219,17 +298,44 @@ func getExecutor(console *Console) prompt.Executor {
 
 		var completed bool
 		validator := console.validators[console.language]
+		if console.interactive && strings.TrimSpace(in) != "" && !validator.Validate(in) {
+			console.continueStatement(in)
+			return
+		}
 		console.input, completed = AddStmtPart(console.input, in, validator)
+		if console.input != "" {
+			console.inputLines = append(console.inputLines, in)
+		}
 		if !completed {
 			console.livePrefixEnabled = true
 			return
 		}
 
-		if err := appendToHistoryFile(console, strings.TrimSpace(console.input)); err != nil {
-			log.Debugf("Failed to append command to history file: %s", err)
+		command := strings.TrimSpace(strings.Join(console.inputLines, "\n"))
+		if console.history != nil {
+			if err := console.history.appendCommand(command); err != nil {
+				log.Debugf("Failed to append command to history file: %s", err)
+			}
+		}
+		console.inputLines = nil
+		console.restartPrompt = true
+
+		if console.readOnly {
+			if err := checkReadOnlySQL(console.input); err != nil {
+				console.input = ""
+				console.livePrefixEnabled = false
+				log.Errorf("Statement is rejected: %s", err)
+				console.writeAudit(command, time.Now(), 0, err)
+				return
+			}
+		}
+
+		funcBody := evalFuncBody
+		if console.timing {
+			funcBody = evalTimedFuncBody
 		}
 
-		var results []string
+		var results []interface{}
 		args := []interface{}{console.input}
 		opts := connector.RequestOpts{
 			PushCallback: func(pushedData interface{}) {
@@ -244,39 +350,139 @@ func getExecutor(console *Console) prompt.Executor {
 			ResData: &results,
 		}
 
-		var data string
-		if _, err := console.conn.Eval(evalFuncBody, args, opts); err != nil {
-			if err == io.EOF {
-				log.Fatalf("Connection was closed. Probably instance process isn't running anymore")
+		start := time.Now()
+		_, err := console.conn.Eval(funcBody, args, opts)
+		roundTrip := time.Since(start)
+		console.input = ""
+		console.livePrefixEnabled = false
+
+		var execErr error
+		if err != nil {
+			execErr = err
+		} else if len(results) > 0 {
+			data, _ := results[0].(string)
+			execErr = getEvalResultError(data)
+		}
+		console.writeAudit(command, start, roundTrip, execErr)
+
+		if connector.IsConnectionError(err) {
+			log.Warnf("Connection was closed. Probably instance process isn't running anymore." +
+				" The command result is unknown")
+			if err := console.reconnect(); err != nil {
+				log.Errorf("Failed to reconnect: %s", err)
 			} else {
-				log.Fatalf("Failed to execute command: %s", err)
+				log.Infof("Reconnected to %s", console.title)
 			}
-		} else {
-			data = results[0]
+			return
+		} else if err != nil {
+			log.Errorf("Failed to execute command: %s", err)
+			return
 		}
 
+		data, _ := results[0].(string)
+		if console.expanded {
+			data = FormatExpanded(data)
+		}
 		fmt.Printf("%s\n", data)
 
-		console.input = ""
-		console.livePrefixEnabled = false
+		if console.timing {
+			fmt.Println(formatTiming(roundTrip, results[1:]))
+		}
 	}
 
 	return executor
 }
 
+// continueStatement restarts the prompt with the incomplete statement and
+// a new line in the buffer, so the statement is edited as a whole.
+func (console *Console) continueStatement(in string) {
+	console.initialText = in + "\n"
+	console.restartPrompt = true
+
+	// Erase the submitted lines, the prompt draws them again.
+	width := 0
+	if cols, _, err := terminal.GetSize(int(os.Stdout.Fd())); err == nil {
+		width = cols
+	}
+	fmt.Print(getEraseLinesSeq(console.incompletePrefix+in, width))
+}
+
+// getEraseLinesSeq returns an escape sequence to move the cursor up to
+// the beginning of the written text and to erase the screen below. Wrapped
+// lines are counted if the terminal width is known.
+func getEraseLinesSeq(text string, width int) string {
+	rows := 0
+	for _, line := range strings.Split(text, "\n") {
+		rows++
+		if length := utf8.RuneCountInString(line); width > 0 && length > 0 {
+			rows += (length - 1) / width
+		}
+	}
+	return fmt.Sprintf("\x1b[%dA\r\x1b[J", rows)
+}
+
+// isIncomplete returns true if the prompt buffer contains an incomplete
+// statement.
+func (console *Console) isIncomplete() bool {
+	text := console.buffer.Text
+	if !console.interactive || strings.TrimSpace(text) == "" ||
+		strings.HasPrefix(text, "\\") {
+		return false
+	}
+	validator, ok := console.validators[console.language]
+	return ok && !validator.Validate(text)
+}
+
+// highlightEnabled returns true if the prompt buffer is highlighted as Lua.
+func (console *Console) highlightEnabled() bool {
+	return console.interactive && console.language != SQLLanguage &&
+		!strings.HasPrefix(console.buffer.Text, "\\")
+}
+
+// writeAudit appends a record about the statement to the audit log.
+func (console *Console) writeAudit(statement string, start time.Time,
+	duration time.Duration, execErr error) {
+	err := console.audit.write(getAuditTarget(console.connOpts), console.language,
+		statement, start, duration, execErr)
+	if err != nil {
+		log.Warnf("Failed to write audit log: %s", err)
+	}
+}
+
+// formatTiming returns a line with the round-trip time of a request and
+// the server execution time from the rest of the timed eval results.
+func formatTiming(roundTrip time.Duration, rest []interface{}) string {
+	line := fmt.Sprintf("Time: %.3f ms", float64(roundTrip)/float64(time.Millisecond))
+	if len(rest) > 0 {
+		if server, ok := rest[0].(float64); ok {
+			line += fmt.Sprintf(" (server: %.3f ms)", server*1000)
+		}
+	}
+	return line
+}
+
 func getCompleter(console *Console) prompt.Completer {
 	completer := func(in prompt.Document) []prompt.Suggest {
+		// The completer is called on each input change, the buffer is used
+		// for the prefix and the highlighting.
+		console.buffer = in
 		if len(in.Text) == 0 {
 			return nil
 		}
 
+		if console.input == "" && strings.HasPrefix(in.Text, "\\") {
+			return getConsoleCmdSuggestions(console, in)
+		}
+
+		lastWordStart := in.FindStartOfPreviousWordUntilSeparator(tarantoolWordSeparators)
+
 		if console.language == SQLLanguage {
 			// Tarantool does not implements auto-completion for SQL:
 			// https://github.com/tarantool/tarantool/issues/2304
-			return nil
+			// So it is implemented on the client side.
+			return getSQLCompletion(console, in, lastWordStart)
 		}
 
-		lastWordStart := in.FindStartOfPreviousWordUntilSeparator(tarantoolWordSeparators)
 		lastWord := in.Text[lastWordStart:]
 
 		if len(lastWord) == 0 {
@@ -331,9 +537,17 @@ func setPrefix(console *Console) {
 	}
 
 	console.livePrefix = fmt.Sprintf("%s> ", strings.Repeat(" ", livePrefixIndent))
+	console.incompletePrefix = fmt.Sprintf("%s* ", console.title)
 
 	console.livePrefixFunc = func() (string, bool) {
-		return console.livePrefix, console.livePrefixEnabled
+		if console.livePrefixEnabled {
+			return console.livePrefix, true
+		}
+		if console.isIncomplete() {
+			return console.incompletePrefix, true
+		}
+		// The prefix could be changed by the \connect command.
+		return console.prefix, true
 	}
 }
 
@@ -343,13 +557,24 @@ func getPromptOptions(console *Console) []prompt.Option {
 		prompt.OptionPrefix(console.prefix),
 		prompt.OptionLivePrefix(console.livePrefixFunc),
 
-		prompt.OptionHistory(console.historyLines),
+		prompt.OptionHistory(getHistoryCommands(console)),
 
 		prompt.OptionSuggestionBGColor(prompt.DarkGray),
 		prompt.OptionPreviewSuggestionTextColor(prompt.DefaultColor),
 
 		prompt.OptionCompletionWordSeparator(tarantoolWordSeparators),
 
+		prompt.OptionWriter(&highlightWriter{
+			ConsoleWriter: prompt.NewStdoutWriter(),
+			console:       console,
+		}),
+
+		// Exit the console after the \quit command or restart it after a
+		// completed command.
+		prompt.OptionSetExitCheckerOnInput(func(in string, breakline bool) bool {
+			return breakline && (console.quit || console.restartPrompt)
+		}),
+
 		prompt.OptionAddASCIICodeBind(
 			// Move to one word left.
 			prompt.ASCIICodeBind{
@@ -369,6 +594,13 @@ func getPromptOptions(console *Console) []prompt.Option {
 					buf.CursorRight(wordLen)
 				},
 			},
+			// Insert a new line.
+			prompt.ASCIICodeBind{
+				ASCIICode: AltEnterBytes,
+				Fn: func(buf *prompt.Buffer) {
+					buf.InsertText("\n", false, true)
+				},
+			},
 		),
 		// Interrupt current unfinished expression.
 		prompt.OptionAddKeyBind(
@@ -376,28 +608,45 @@ func getPromptOptions(console *Console) []prompt.Option {
 				Key: prompt.ControlC,
 				Fn: func(buf *prompt.Buffer) {
 					console.input = ""
+					console.inputLines = nil
 					console.livePrefixEnabled = false
+					console.historySearch = historySearch{}
 					fmt.Println("^C")
 				},
 			},
+			// Search the history backward.
+			prompt.KeyBind{
+				Key: prompt.ControlR,
+				Fn:  console.searchHistoryBackward,
+			},
 		),
 	}
 
 	return options
 }
 
-func appendToHistoryFile(console *Console, in string) error {
-	if console.historyFile == nil {
-		return fmt.Errorf("no history file found")
+// searchHistoryBackward replaces the prompt text with the previous history
+// command containing the typed text. The text is kept if there is no match.
+func (console *Console) searchHistoryBackward(buf *prompt.Buffer) {
+	if console.history == nil {
+		return
 	}
-
-	if _, err := console.historyFile.WriteString(in + "\n"); err != nil {
-		return fmt.Errorf("failed to append to history file: %s", err)
+	match, ok := console.historySearch.next(console.history.commands, buf.Text())
+	if !ok {
+		return
 	}
+	d := buf.Document()
+	buf.DeleteBeforeCursor(len([]rune(d.TextBeforeCursor())))
+	buf.Delete(len([]rune(d.TextAfterCursor())))
+	buf.InsertText(match, false, true)
+}
 
-	if err := console.historyFile.Sync(); err != nil {
-		return fmt.Errorf("failed to sync history file: %s", err)
+// getHistoryCommands returns a list of commands for the prompt history.
+func getHistoryCommands(console *Console) []string {
+	if console.history == nil {
+		return []string{}
 	}
-
-	return nil
+	commands := make([]string, len(console.history.commands))
+	copy(commands, console.history.commands)
+	return commands
 }
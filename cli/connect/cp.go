@@ -0,0 +1,354 @@
+package connect
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/apex/log"
+
+	"github.com/tarantool/tt/cli/connector"
+	"github.com/tarantool/tt/cli/util"
+)
+
+const (
+	// DefaultCopyChunkSize is a default size of a file chunk transferred with
+	// a single request.
+	DefaultCopyChunkSize = 1024 * 1024
+	// partFileSuffix is a suffix of a file being copied. A file with the
+	// suffix is used to resume an interrupted copying.
+	partFileSuffix = ".part"
+)
+
+// RemotePath is a path to a file on a remote instance.
+type RemotePath struct {
+	// Target is a connection target: an application name, an instance name
+	// or an URI.
+	Target string
+	// Path is an absolute path to the file on the instance host.
+	Path string
+}
+
+// ParseRemotePath parses a "<TARGET>:<ABSOLUTE_PATH>" string. It returns
+// false if the string is a local path.
+func ParseRemotePath(arg string) (RemotePath, bool) {
+	// A relative local path could contain ":/" too.
+	if strings.HasPrefix(arg, ".") || strings.HasPrefix(arg, "/") {
+		return RemotePath{}, false
+	}
+	// A target (e.g. an URI) may contain ":/" as well, so the last one
+	// delimits the path.
+	idx := strings.LastIndex(arg, ":/")
+	if idx <= 0 {
+		return RemotePath{}, false
+	}
+	return RemotePath{Target: arg[:idx], Path: arg[idx+1:]}, true
+}
+
+// CopyOpts describes options of a file copying.
+type CopyOpts struct {
+	// ChunkSize is a size of a file chunk transferred with a single request.
+	// DefaultCopyChunkSize is used if it is zero.
+	ChunkSize int
+	// Progress is a writer for a progress bar. The progress is not printed
+	// if it is nil.
+	Progress io.Writer
+}
+
+// chunkSize returns the size of a chunk to transfer.
+func (opts CopyOpts) chunkSize() int64 {
+	if opts.ChunkSize <= 0 {
+		return DefaultCopyChunkSize
+	}
+	return int64(opts.ChunkSize)
+}
+
+// remoteFileStat describes a file on a remote instance.
+type remoteFileStat struct {
+	Exists bool  `msgpack:"exists"`
+	IsDir  bool  `msgpack:"is_dir"`
+	Size   int64 `msgpack:"size"`
+}
+
+// remoteFileOp performs the file operation on the instance.
+func remoteFileOp(evaler connector.Evaler, resData interface{}, op string,
+	args ...interface{}) error {
+	opts := connector.RequestOpts{ResData: resData}
+	_, err := evaler.Eval(fileOpFuncBody, append([]interface{}{op}, args...), opts)
+	return err
+}
+
+// remoteStat returns information about the remote file.
+func remoteStat(evaler connector.Evaler, filePath string) (remoteFileStat, error) {
+	var stats []remoteFileStat
+	if err := remoteFileOp(evaler, &stats, "stat", filePath); err != nil {
+		return remoteFileStat{}, err
+	}
+	if len(stats) != 1 {
+		return remoteFileStat{}, fmt.Errorf("unexpected response: %v", stats)
+	}
+	return stats[0], nil
+}
+
+// remoteRead reads a chunk of the remote file at the offset.
+func remoteRead(evaler connector.Evaler, filePath string, offset, size int64) ([]byte, error) {
+	var data []string
+	if err := remoteFileOp(evaler, &data, "read", filePath, offset, size); err != nil {
+		return nil, err
+	}
+	if len(data) != 1 {
+		return nil, fmt.Errorf("unexpected response: %v", data)
+	}
+	return []byte(data[0]), nil
+}
+
+// remoteSHA256 computes SHA256 of the first length bytes of the remote file.
+// The whole file is hashed if length is negative.
+func remoteSHA256(evaler connector.Evaler, filePath string, length int64) (string, error) {
+	var lengthArg interface{}
+	if length >= 0 {
+		lengthArg = length
+	}
+	var hashes []string
+	if err := remoteFileOp(evaler, &hashes, "sha256", filePath, lengthArg); err != nil {
+		return "", err
+	}
+	if len(hashes) != 1 {
+		return "", fmt.Errorf("unexpected response: %v", hashes)
+	}
+	return hashes[0], nil
+}
+
+// fileSHA256HexPrefix computes SHA256 of the first length bytes of the file.
+// The result is returned in a hex form.
+func fileSHA256HexPrefix(filePath string, length int64) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.CopyN(hasher, file, length); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", hasher.Sum(nil)), nil
+}
+
+// resumeOffset returns an offset to resume copying from. A partially copied
+// file is continued only if it is a prefix of the source file.
+func resumeOffset(partSize, srcSize int64, srcHash, partHash func(int64) (string, error)) int64 {
+	if partSize <= 0 || partSize > srcSize {
+		return 0
+	}
+
+	expected, err := srcHash(partSize)
+	if err != nil {
+		log.Debugf("Unable to compute a checksum of the source file: %s", err)
+		return 0
+	}
+	actual, err := partHash(partSize)
+	if err != nil {
+		log.Debugf("Unable to compute a checksum of the partial file: %s", err)
+		return 0
+	}
+	if expected != actual {
+		log.Warnf("The partially copied file differs from the source, starting over")
+		return 0
+	}
+	return partSize
+}
+
+// Download copies the remote file into the local path. If the local path
+// is a directory, the file is copied into it. An interrupted copying is
+// resumed from a partially copied file.
+func Download(evaler connector.Evaler, remotePath, localPath string, opts CopyOpts) error {
+	stat, err := remoteStat(evaler, remotePath)
+	if err != nil {
+		return err
+	}
+	if !stat.Exists {
+		return fmt.Errorf("remote file %q does not exist", remotePath)
+	}
+	if stat.IsDir {
+		return fmt.Errorf("remote path %q is a directory", remotePath)
+	}
+
+	if info, err := os.Stat(localPath); err == nil && info.IsDir() {
+		localPath = filepath.Join(localPath, path.Base(remotePath))
+	}
+	partPath := localPath + partFileSuffix
+
+	var offset int64
+	if info, err := os.Stat(partPath); err == nil {
+		offset = resumeOffset(info.Size(), stat.Size,
+			func(length int64) (string, error) {
+				return remoteSHA256(evaler, remotePath, length)
+			},
+			func(length int64) (string, error) {
+				return fileSHA256HexPrefix(partPath, length)
+			})
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE
+	if offset == 0 {
+		flags |= os.O_TRUNC
+	}
+	file, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %q: %s", partPath, err)
+	}
+	defer file.Close()
+
+	bar := newProgressBar(opts.Progress, path.Base(remotePath), stat.Size)
+	for bar.update(offset); offset < stat.Size; bar.update(offset) {
+		size := opts.chunkSize()
+		if stat.Size-offset < size {
+			size = stat.Size - offset
+		}
+		data, err := remoteRead(evaler, remotePath, offset, size)
+		if err != nil {
+			bar.finish()
+			return err
+		}
+		if len(data) == 0 {
+			bar.finish()
+			return fmt.Errorf("remote file %q is truncated at %d bytes", remotePath, offset)
+		}
+		if _, err := file.WriteAt(data, offset); err != nil {
+			bar.finish()
+			return fmt.Errorf("failed to write %q: %s", partPath, err)
+		}
+		offset += int64(len(data))
+	}
+	bar.finish()
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write %q: %s", partPath, err)
+	}
+
+	remoteHash, err := remoteSHA256(evaler, remotePath, -1)
+	if err != nil {
+		return err
+	}
+	localHash, err := util.FileSHA256Hex(partPath)
+	if err != nil {
+		return err
+	}
+	if remoteHash != localHash {
+		os.Remove(partPath)
+		return fmt.Errorf("checksum mismatch: %s (remote) != %s (local)", remoteHash, localHash)
+	}
+
+	return os.Rename(partPath, localPath)
+}
+
+// Upload copies the local file into the remote path. If the remote path is
+// a directory, the file is copied into it. An interrupted copying is resumed
+// from a partially copied file.
+func Upload(evaler connector.Evaler, localPath, remotePath string, opts CopyOpts) error {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("local path %q is a directory", localPath)
+	}
+
+	stat, err := remoteStat(evaler, remotePath)
+	if err != nil {
+		return err
+	}
+	if stat.IsDir {
+		remotePath = path.Join(remotePath, filepath.Base(localPath))
+	}
+	partPath := remotePath + partFileSuffix
+
+	partStat, err := remoteStat(evaler, partPath)
+	if err != nil {
+		return err
+	}
+	var offset int64
+	if partStat.Exists {
+		offset = resumeOffset(partStat.Size, info.Size(),
+			func(length int64) (string, error) {
+				return fileSHA256HexPrefix(localPath, length)
+			},
+			func(length int64) (string, error) {
+				return remoteSHA256(evaler, partPath, length)
+			})
+	}
+	if offset == 0 {
+		// Create or truncate the remote file, so an empty file is copied too.
+		if err := remoteFileOp(evaler, nil, "write", partPath, int64(0), ""); err != nil {
+			return err
+		}
+	}
+
+	buf := make([]byte, opts.chunkSize())
+	bar := newProgressBar(opts.Progress, filepath.Base(localPath), info.Size())
+	for bar.update(offset); offset < info.Size(); bar.update(offset) {
+		n, err := file.ReadAt(buf, offset)
+		if n == 0 && err != nil {
+			bar.finish()
+			return fmt.Errorf("failed to read %q: %s", localPath, err)
+		}
+		if err := remoteFileOp(evaler, nil, "write", partPath, offset,
+			string(buf[:n])); err != nil {
+			bar.finish()
+			return err
+		}
+		offset += int64(n)
+	}
+	bar.finish()
+
+	localHash, err := util.FileSHA256Hex(localPath)
+	if err != nil {
+		return err
+	}
+	remoteHash, err := remoteSHA256(evaler, partPath, -1)
+	if err != nil {
+		return err
+	}
+	if remoteHash != localHash {
+		remoteFileOp(evaler, nil, "remove", partPath)
+		return fmt.Errorf("checksum mismatch: %s (local) != %s (remote)", localHash, remoteHash)
+	}
+
+	return remoteFileOp(evaler, nil, "rename", partPath, remotePath)
+}
+
+// CopyFromTarget connects to the target and downloads the remote file.
+func CopyFromTarget(connectCtx ConnectCtx, connString, remotePath, localPath string,
+	opts CopyOpts) error {
+	connOpts := getConnOpts(connString, connectCtx)
+	conn, err := connector.Connect(connOpts)
+	if err != nil {
+		return fmt.Errorf("unable to establish connection: %s", err)
+	}
+	defer conn.Close()
+
+	return Download(conn, remotePath, localPath, opts)
+}
+
+// CopyToTarget connects to the target and uploads the local file.
+func CopyToTarget(connectCtx ConnectCtx, connString, localPath, remotePath string,
+	opts CopyOpts) error {
+	connOpts := getConnOpts(connString, connectCtx)
+	conn, err := connector.Connect(connOpts)
+	if err != nil {
+		return fmt.Errorf("unable to establish connection: %s", err)
+	}
+	defer conn.Close()
+
+	return Upload(conn, localPath, remotePath, opts)
+}
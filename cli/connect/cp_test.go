@@ -0,0 +1,253 @@
+package connect_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+
+	. "github.com/tarantool/tt/cli/connect"
+	"github.com/tarantool/tt/cli/connector"
+	"github.com/tarantool/tt/cli/util"
+)
+
+// fileOpEvalerMock performs remote file operations on local files.
+type fileOpEvalerMock struct {
+	// ops is a list of performed operations.
+	ops []string
+	// failOn is an operation to fail.
+	failOn string
+}
+
+func (evaler *fileOpEvalerMock) fileOp(op, path string, args []interface{}) (interface{},
+	error) {
+	switch op {
+	case "stat":
+		info, err := os.Stat(path)
+		if err != nil {
+			return map[string]interface{}{"exists": false}, nil
+		}
+		return map[string]interface{}{
+			"exists": true,
+			"is_dir": info.IsDir(),
+			"size":   info.Size(),
+		}, nil
+	case "read":
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		offset, size := args[0].(int64), args[1].(int64)
+		if offset+size > int64(len(data)) {
+			size = int64(len(data)) - offset
+		}
+		return string(data[offset : offset+size]), nil
+	case "write":
+		flags := os.O_WRONLY | os.O_CREATE
+		offset := args[0].(int64)
+		if offset == 0 {
+			flags |= os.O_TRUNC
+		}
+		file, err := os.OpenFile(path, flags, 0644)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		_, err = file.WriteAt([]byte(args[1].(string)), offset)
+		return true, err
+	case "sha256":
+		if args[0] == nil {
+			return util.FileSHA256Hex(path)
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		tmp := filepath.Join(filepath.Dir(path), "prefix.tmp")
+		defer os.Remove(tmp)
+		if err := ioutil.WriteFile(tmp, data[:args[0].(int64)], 0644); err != nil {
+			return nil, err
+		}
+		return util.FileSHA256Hex(tmp)
+	case "rename":
+		return true, os.Rename(path, args[0].(string))
+	case "remove":
+		return true, os.Remove(path)
+	}
+	return nil, errors.New("unknown file operation")
+}
+
+func (evaler *fileOpEvalerMock) Eval(expr string, args []interface{},
+	opts connector.RequestOpts) ([]interface{}, error) {
+	op := args[0].(string)
+	evaler.ops = append(evaler.ops, op)
+	if op == evaler.failOn {
+		return nil, errors.New("connection error")
+	}
+
+	result, err := evaler.fileOp(op, args[1].(string), args[2:])
+	if err != nil {
+		return nil, err
+	}
+	if opts.ResData != nil {
+		encoded, err := msgpack.Marshal([]interface{}{result})
+		if err != nil {
+			return nil, err
+		}
+		if err := msgpack.Unmarshal(encoded, opts.ResData); err != nil {
+			return nil, err
+		}
+	}
+	return []interface{}{result}, nil
+}
+
+func countOps(ops []string, op string) int {
+	count := 0
+	for _, cur := range ops {
+		if cur == op {
+			count++
+		}
+	}
+	return count
+}
+
+func TestParseRemotePath(t *testing.T) {
+	cases := []struct {
+		arg      string
+		ok       bool
+		expected RemotePath
+	}{
+		{"app:inst:/var/lib/00.snap", true, RemotePath{"app:inst", "/var/lib/00.snap"}},
+		{"app:/tmp/file", true, RemotePath{"app", "/tmp/file"}},
+		{"localhost:3301:/tmp/file", true, RemotePath{"localhost:3301", "/tmp/file"}},
+		{"tcp://localhost:3301:/tmp/file", true,
+			RemotePath{"tcp://localhost:3301", "/tmp/file"}},
+		{"unix:///tmp/app.sock:/tmp/file", true, RemotePath{"unix:///tmp/app.sock", "/tmp/file"}},
+		{"./local", false, RemotePath{}},
+		{"./dir:/file", false, RemotePath{}},
+		{"/tmp/file", false, RemotePath{}},
+		{"file", false, RemotePath{}},
+		{"app:inst:file", false, RemotePath{}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.arg, func(t *testing.T) {
+			remotePath, ok := ParseRemotePath(tc.arg)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, remotePath)
+		})
+	}
+}
+
+func TestDownload(t *testing.T) {
+	dir := t.TempDir()
+	remoteDir, localDir := filepath.Join(dir, "remote"), filepath.Join(dir, "local")
+	require.NoError(t, os.Mkdir(remoteDir, 0755))
+	require.NoError(t, os.Mkdir(localDir, 0755))
+
+	content := strings.Repeat("0123456789", 10)
+	remotePath := filepath.Join(remoteDir, "file.snap")
+	require.NoError(t, ioutil.WriteFile(remotePath, []byte(content), 0644))
+
+	evaler := &fileOpEvalerMock{}
+	var progress strings.Builder
+	err := Download(evaler, remotePath, localDir, CopyOpts{ChunkSize: 30, Progress: &progress})
+	require.NoError(t, err)
+
+	data, err := ioutil.ReadFile(filepath.Join(localDir, "file.snap"))
+	require.NoError(t, err)
+	assert.Equal(t, content, string(data))
+	assert.Equal(t, 4, countOps(evaler.ops, "read"))
+	assert.NoFileExists(t, filepath.Join(localDir, "file.snap.part"))
+	assert.Contains(t, progress.String(), "file.snap [")
+	assert.Contains(t, progress.String(), "100% 100B/100B\n")
+}
+
+func TestDownload_resume(t *testing.T) {
+	dir := t.TempDir()
+	content := strings.Repeat("0123456789", 10)
+	remotePath := filepath.Join(dir, "remote.snap")
+	require.NoError(t, ioutil.WriteFile(remotePath, []byte(content), 0644))
+	localPath := filepath.Join(dir, "local.snap")
+
+	evaler := &fileOpEvalerMock{failOn: "read"}
+	err := Download(evaler, remotePath, localPath, CopyOpts{ChunkSize: 30})
+	require.EqualError(t, err, "connection error")
+
+	// Emulate a copying interrupted after the first chunk.
+	require.NoError(t, ioutil.WriteFile(localPath+".part", []byte(content[:30]), 0644))
+	evaler = &fileOpEvalerMock{}
+	require.NoError(t, Download(evaler, remotePath, localPath, CopyOpts{ChunkSize: 30}))
+	data, err := ioutil.ReadFile(localPath)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(data))
+	assert.Equal(t, 3, countOps(evaler.ops, "read"))
+
+	// A part file with another content is overwritten.
+	require.NoError(t, ioutil.WriteFile(localPath+".part", []byte("garbage"), 0644))
+	evaler = &fileOpEvalerMock{}
+	require.NoError(t, Download(evaler, remotePath, localPath, CopyOpts{ChunkSize: 30}))
+	data, err = ioutil.ReadFile(localPath)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(data))
+	assert.Equal(t, 4, countOps(evaler.ops, "read"))
+}
+
+func TestDownload_errors(t *testing.T) {
+	dir := t.TempDir()
+	evaler := &fileOpEvalerMock{}
+
+	err := Download(evaler, filepath.Join(dir, "missing"), dir, CopyOpts{})
+	assert.EqualError(t, err, "remote file \""+filepath.Join(dir, "missing")+
+		"\" does not exist")
+
+	err = Download(evaler, dir, filepath.Join(dir, "local"), CopyOpts{})
+	assert.EqualError(t, err, "remote path \""+dir+"\" is a directory")
+}
+
+func TestUpload(t *testing.T) {
+	dir := t.TempDir()
+	remoteDir := filepath.Join(dir, "remote")
+	require.NoError(t, os.Mkdir(remoteDir, 0755))
+
+	content := strings.Repeat("abcdefghij", 10)
+	localPath := filepath.Join(dir, "init.lua")
+	require.NoError(t, ioutil.WriteFile(localPath, []byte(content), 0644))
+
+	evaler := &fileOpEvalerMock{}
+	require.NoError(t, Upload(evaler, localPath, remoteDir, CopyOpts{ChunkSize: 40}))
+	data, err := ioutil.ReadFile(filepath.Join(remoteDir, "init.lua"))
+	require.NoError(t, err)
+	assert.Equal(t, content, string(data))
+	// The remote file is created before the chunks are written.
+	assert.Equal(t, 4, countOps(evaler.ops, "write"))
+	assert.NoFileExists(t, filepath.Join(remoteDir, "init.lua.part"))
+
+	// Resume from the remote part file.
+	remotePath := filepath.Join(remoteDir, "copy.lua")
+	require.NoError(t, ioutil.WriteFile(remotePath+".part", []byte(content[:80]), 0644))
+	evaler = &fileOpEvalerMock{}
+	require.NoError(t, Upload(evaler, localPath, remotePath, CopyOpts{ChunkSize: 40}))
+	data, err = ioutil.ReadFile(remotePath)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(data))
+	assert.Equal(t, 1, countOps(evaler.ops, "write"))
+}
+
+func TestUpload_emptyFile(t *testing.T) {
+	dir := t.TempDir()
+	localPath := filepath.Join(dir, "empty")
+	require.NoError(t, ioutil.WriteFile(localPath, nil, 0644))
+
+	remotePath := filepath.Join(dir, "copy")
+	require.NoError(t, Upload(&fileOpEvalerMock{}, localPath, remotePath, CopyOpts{}))
+	data, err := ioutil.ReadFile(remotePath)
+	require.NoError(t, err)
+	assert.Empty(t, data)
+}
@@ -0,0 +1,261 @@
+package connect
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v2"
+
+	"github.com/tarantool/tt/cli/connector"
+)
+
+const (
+	// EvalFormatJSON encodes results as a JSON array.
+	EvalFormatJSON = "json"
+	// EvalFormatYAML encodes results as a YAML document.
+	EvalFormatYAML = "yaml"
+	// EvalFormatMsgpackHex encodes results as a hex string of a msgpack array.
+	EvalFormatMsgpackHex = "msgpack-hex"
+)
+
+// EvalFormats is a list of supported formats of the evaluation results.
+var EvalFormats = []string{EvalFormatJSON, EvalFormatYAML, EvalFormatMsgpackHex}
+
+// EvalOpts describes options of a Lua expression evaluation.
+type EvalOpts struct {
+	// Expression is a Lua expression or a chunk to evaluate.
+	Expression string
+	// Args are passed to the expression as a table in the first vararg.
+	Args map[string]interface{}
+	// Format is an output format of the results.
+	Format string
+}
+
+// EvalError describes an error raised by the evaluated expression.
+type EvalError struct {
+	// Message is the error message.
+	Message string
+	// Traceback is the Lua traceback of the error.
+	Traceback string
+}
+
+// Error returns the error message with the traceback.
+func (err EvalError) Error() string {
+	if err.Traceback == "" {
+		return err.Message
+	}
+	return err.Message + "\n" + strings.TrimLeft(err.Traceback, "\n")
+}
+
+// evalResult is a response of the expression evaluation function.
+type evalResult struct {
+	Ok        bool          `msgpack:"ok"`
+	Results   []interface{} `msgpack:"results"`
+	Error     string        `msgpack:"error"`
+	Traceback string        `msgpack:"traceback"`
+}
+
+// ParseEvalArgs parses arguments in the "key=value" format. A value is
+// decoded as JSON if possible and is used as a string otherwise.
+func ParseEvalArgs(args []string) (map[string]interface{}, error) {
+	parsed := map[string]interface{}{}
+	for _, arg := range args {
+		kv := strings.SplitN(arg, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("invalid argument %q: expected key=value", arg)
+		}
+
+		parsed[kv[0]] = parseArgValue(kv[1])
+	}
+	return parsed, nil
+}
+
+// parseArgValue decodes the value as JSON if possible and returns it as
+// a string otherwise.
+func parseArgValue(arg string) interface{} {
+	var value interface{}
+	if err := json.Unmarshal([]byte(arg), &value); err != nil {
+		return arg
+	}
+	return value
+}
+
+// EvalExpression evaluates the Lua expression via the connection and returns
+// the results. An error raised by the expression is returned as EvalError.
+func EvalExpression(evaler connector.Evaler, expr string,
+	args map[string]interface{}) ([]interface{}, error) {
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+
+	var response []evalResult
+	opts := connector.RequestOpts{
+		ResData: &response,
+	}
+	if _, err := evaler.Eval(evalExprFuncBody, []interface{}{expr, args}, opts); err != nil {
+		return nil, err
+	}
+
+	if len(response) != 1 {
+		return nil, fmt.Errorf("unexpected response: %v", response)
+	}
+	if !response[0].Ok {
+		return nil, EvalError{
+			Message:   response[0].Error,
+			Traceback: response[0].Traceback,
+		}
+	}
+
+	results := response[0].Results
+	if results == nil {
+		results = []interface{}{}
+	}
+	return results, nil
+}
+
+// EvalOnTarget connects to the target and evaluates the expression.
+func EvalOnTarget(connectCtx ConnectCtx, connString string, opts EvalOpts) ([]interface{}, error) {
+	connOpts := getConnOpts(connString, connectCtx)
+	conn, err := connector.Connect(connOpts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to establish connection: %s", err)
+	}
+	defer conn.Close()
+
+	return EvalExpression(conn, opts.Expression, opts.Args)
+}
+
+// EvalTarget is a named target of the evaluation.
+type EvalTarget struct {
+	// Name is a name of the target, e.g. "app:instance".
+	Name string
+	// ConnString is a connection string of the target.
+	ConnString string
+}
+
+// EvalTargetResult is a result of the evaluation on a target.
+type EvalTargetResult struct {
+	// Name is a name of the target.
+	Name string
+	// Results are the evaluation results.
+	Results []interface{}
+	// Err is an error of the evaluation on the target.
+	Err error
+}
+
+// EvalOnTargets concurrently evaluates the expression on all targets. An error
+// on a target does not abort the evaluation on the others. Results are returned
+// in the order of the targets.
+func EvalOnTargets(connectCtx ConnectCtx, targets []EvalTarget,
+	opts EvalOpts) []EvalTargetResult {
+	targetResults := make([]EvalTargetResult, len(targets))
+
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		go func(i int, target EvalTarget) {
+			defer wg.Done()
+			results, err := EvalOnTarget(connectCtx, target.ConnString, opts)
+			targetResults[i] = EvalTargetResult{
+				Name:    target.Name,
+				Results: results,
+				Err:     err,
+			}
+		}(i, target)
+	}
+	wg.Wait()
+
+	return targetResults
+}
+
+// normalizeValue converts maps with interface{} keys into maps with string
+// keys recursively, so the value could be encoded into JSON.
+func normalizeValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(typed))
+		for k, v := range typed {
+			converted[fmt.Sprint(k)] = normalizeValue(v)
+		}
+		return converted
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(typed))
+		for k, v := range typed {
+			converted[k] = normalizeValue(v)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(typed))
+		for i, v := range typed {
+			converted[i] = normalizeValue(v)
+		}
+		return converted
+	default:
+		return value
+	}
+}
+
+// FormatEvalResults encodes results of an evaluation in the format.
+func FormatEvalResults(results interface{}, format string) ([]byte, error) {
+	switch format {
+	case EvalFormatJSON:
+		return json.MarshalIndent(normalizeValue(results), "", "  ")
+	case EvalFormatYAML:
+		encoded, err := yaml.Marshal(normalizeValue(results))
+		if err != nil {
+			return nil, err
+		}
+		return []byte(startOfYAMLOutput + string(encoded) + endOfYAMLOutput), nil
+	case EvalFormatMsgpackHex:
+		var buf bytes.Buffer
+		encoder := msgpack.NewEncoder(&buf)
+		encoder.UseCompactInts(true)
+		if err := encoder.Encode(results); err != nil {
+			return nil, err
+		}
+		return []byte(hex.EncodeToString(buf.Bytes())), nil
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// FormatEvalTargetResults encodes results of an evaluation on several targets
+// in the format. JSON results are encoded as an object keyed by a target name,
+// other formats are grouped by a target name with a comment line.
+func FormatEvalTargetResults(targetResults []EvalTargetResult, format string) ([]byte, error) {
+	if format == EvalFormatJSON {
+		object := make(map[string]interface{}, len(targetResults))
+		for _, result := range targetResults {
+			if result.Err != nil {
+				object[result.Name] = map[string]interface{}{"error": result.Err.Error()}
+			} else {
+				object[result.Name] = map[string]interface{}{"results": result.Results}
+			}
+		}
+		return FormatEvalResults(object, format)
+	}
+
+	var buf bytes.Buffer
+	for _, result := range targetResults {
+		buf.WriteString("# " + result.Name + "\n")
+		if result.Err != nil {
+			buf.WriteString("error: " + result.Err.Error() + "\n")
+			continue
+		}
+
+		output, err := FormatEvalResults(result.Results, format)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(output)
+		if !bytes.HasSuffix(output, []byte("\n")) {
+			buf.WriteString("\n")
+		}
+	}
+	return buf.Bytes(), nil
+}
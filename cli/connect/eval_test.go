@@ -0,0 +1,149 @@
+package connect_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/tarantool/tt/cli/connect"
+	"github.com/tarantool/tt/cli/connector"
+)
+
+func TestParseEvalArgs(t *testing.T) {
+	args, err := ParseEvalArgs([]string{
+		"str=value",
+		"num=42",
+		"float=1.5",
+		"bool=true",
+		"list=[1, 2]",
+		"map={\"a\": 1}",
+		"quoted=\"42\"",
+		"empty=",
+		"eq=a=b",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"str":    "value",
+		"num":    float64(42),
+		"float":  1.5,
+		"bool":   true,
+		"list":   []interface{}{float64(1), float64(2)},
+		"map":    map[string]interface{}{"a": float64(1)},
+		"quoted": "42",
+		"empty":  "",
+		"eq":     "a=b",
+	}, args)
+}
+
+func TestParseEvalArgs_invalid(t *testing.T) {
+	for _, arg := range []string{"", "key", "=value"} {
+		t.Run(arg, func(t *testing.T) {
+			_, err := ParseEvalArgs([]string{arg})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestFormatEvalResults(t *testing.T) {
+	results := []interface{}{
+		uint64(1),
+		"str",
+		map[interface{}]interface{}{"key": []interface{}{true, nil}},
+	}
+
+	cases := []struct {
+		format   string
+		expected string
+	}{
+		{EvalFormatJSON,
+			"[\n  1,\n  \"str\",\n  {\n    \"key\": [\n      true,\n      null\n    ]\n  }\n]"},
+		{EvalFormatYAML, "---\n- 1\n- str\n- key:\n  - true\n  - null\n...\n"},
+		{EvalFormatMsgpackHex, "9301a373747281a36b657992c3c0"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.format, func(t *testing.T) {
+			output, err := FormatEvalResults(results, tc.format)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(output))
+		})
+	}
+
+	_, err := FormatEvalResults(results, "xml")
+	assert.EqualError(t, err, "unsupported format: xml")
+}
+
+type evalerMock struct {
+	expr string
+	args []interface{}
+	err  error
+}
+
+func (evaler *evalerMock) Eval(expr string, args []interface{},
+	opts connector.RequestOpts) ([]interface{}, error) {
+	evaler.expr = expr
+	evaler.args = args
+	return nil, evaler.err
+}
+
+func TestEvalExpression_connectionError(t *testing.T) {
+	evaler := &evalerMock{err: errors.New("connection error")}
+	_, err := EvalExpression(evaler, "return 1", nil)
+	assert.EqualError(t, err, "connection error")
+	assert.NotEmpty(t, evaler.expr)
+	require.Len(t, evaler.args, 2)
+	assert.Equal(t, "return 1", evaler.args[0])
+	assert.Equal(t, map[string]interface{}{}, evaler.args[1])
+}
+
+func TestEvalError_Error(t *testing.T) {
+	err := EvalError{Message: "eval:1: boom"}
+	assert.EqualError(t, err, "eval:1: boom")
+
+	err.Traceback = "\nstack traceback:\n\teval:1: in main chunk"
+	assert.EqualError(t, err, "eval:1: boom\nstack traceback:\n\teval:1: in main chunk")
+}
+
+func TestEvalOnTargets_connectionError(t *testing.T) {
+	dir := t.TempDir()
+	targets := []EvalTarget{
+		{Name: "app:first", ConnString: dir + "/first.control"},
+		{Name: "app:second", ConnString: dir + "/second.control"},
+	}
+
+	results := EvalOnTargets(ConnectCtx{}, targets, EvalOpts{Expression: "return 1"})
+	require.Len(t, results, 2)
+	for i, result := range results {
+		assert.Equal(t, targets[i].Name, result.Name)
+		assert.Nil(t, result.Results)
+		assert.Error(t, result.Err)
+	}
+}
+
+func TestFormatEvalTargetResults(t *testing.T) {
+	results := []EvalTargetResult{
+		{Name: "app:first", Results: []interface{}{uint64(1)}},
+		{Name: "app:second", Err: errors.New("boom")},
+	}
+
+	cases := []struct {
+		format   string
+		expected string
+	}{
+		{EvalFormatJSON, "{\n" +
+			"  \"app:first\": {\n    \"results\": [\n      1\n    ]\n  },\n" +
+			"  \"app:second\": {\n    \"error\": \"boom\"\n  }\n}"},
+		{EvalFormatYAML, "# app:first\n---\n- 1\n...\n# app:second\nerror: boom\n"},
+		{EvalFormatMsgpackHex, "# app:first\n9101\n# app:second\nerror: boom\n"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.format, func(t *testing.T) {
+			output, err := FormatEvalTargetResults(results, tc.format)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(output))
+		})
+	}
+}
@@ -0,0 +1,433 @@
+package connect
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/tarantool/tt/cli/connector"
+)
+
+const (
+	// DataFormatCSV encodes tuples as CSV rows with a header.
+	DataFormatCSV = "csv"
+	// DataFormatJSONL encodes tuples as JSON objects, one per line.
+	DataFormatJSONL = "jsonl"
+	// DataFormatMsgpack encodes tuples as a stream of msgpack maps.
+	DataFormatMsgpack = "msgpack"
+
+	// DefaultBatchSize is a default number of tuples transferred with
+	// a single request.
+	DefaultBatchSize = 1000
+)
+
+// DataFormats is a list of supported formats of exported data.
+var DataFormats = []string{DataFormatCSV, DataFormatJSONL, DataFormatMsgpack}
+
+// ExportOpts describes options of a space data export.
+type ExportOpts struct {
+	// Space is a name or an identifier of the space.
+	Space string
+	// Format is a format of the exported data.
+	Format string
+	// Where is a filter in the "index:key" format. All tuples are exported
+	// if it is empty.
+	Where string
+	// BatchSize is a number of tuples selected with a single request.
+	// DefaultBatchSize is used if it is zero.
+	BatchSize int
+	// Progress is a writer for a progress bar. The progress is not printed
+	// if it is nil.
+	Progress io.Writer
+}
+
+// batchSize returns the number of tuples to select with a single request.
+func (opts ExportOpts) batchSize() int {
+	if opts.BatchSize <= 0 {
+		return DefaultBatchSize
+	}
+	return opts.BatchSize
+}
+
+// fieldName returns a name of the field by a zero-based number. A field
+// without a format is named by its number starting from 1.
+func fieldName(format []FieldFormat, fieldNo int) string {
+	if fieldNo < len(format) && format[fieldNo].Name != "" {
+		return format[fieldNo].Name
+	}
+	return fmt.Sprintf("field_%d", fieldNo+1)
+}
+
+// exportValue converts the tuple field value into a value that could be
+// encoded into JSON.
+func exportValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case []byte:
+		return string(typed)
+	case []interface{}:
+		converted := make([]interface{}, len(typed))
+		for i, v := range typed {
+			converted[i] = exportValue(v)
+		}
+		return converted
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(typed))
+		for k, v := range typed {
+			converted[fmt.Sprint(k)] = exportValue(v)
+		}
+		return converted
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(typed))
+		for k, v := range typed {
+			converted[k] = exportValue(v)
+		}
+		return converted
+	default:
+		return value
+	}
+}
+
+// csvCell returns a CSV representation of the tuple field value. Arrays and
+// maps are encoded into JSON, null is encoded as an empty string.
+func csvCell(value interface{}) (string, error) {
+	switch typed := exportValue(value).(type) {
+	case nil:
+		return "", nil
+	case string:
+		return typed, nil
+	case float32:
+		return strconv.FormatFloat(float64(typed), 'g', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(typed, 'g', -1, 64), nil
+	case []interface{}, map[string]interface{}:
+		encoded, err := json.Marshal(typed)
+		return string(encoded), err
+	default:
+		return fmt.Sprint(typed), nil
+	}
+}
+
+// tupleWriter writes tuples in a data format.
+type tupleWriter struct {
+	format      string
+	fieldFormat []FieldFormat
+	csvWriter   *csv.Writer
+	encoder     *msgpack.Encoder
+	writer      io.Writer
+	// header is the written CSV header.
+	header []string
+}
+
+// newTupleWriter creates a new tuple writer.
+func newTupleWriter(writer io.Writer, format string, fieldFormat []FieldFormat) *tupleWriter {
+	tupleWriter := &tupleWriter{
+		format:      format,
+		fieldFormat: fieldFormat,
+		writer:      writer,
+	}
+	switch format {
+	case DataFormatCSV:
+		tupleWriter.csvWriter = csv.NewWriter(writer)
+	case DataFormatMsgpack:
+		tupleWriter.encoder = msgpack.NewEncoder(writer)
+		tupleWriter.encoder.UseCompactInts(true)
+	}
+	return tupleWriter
+}
+
+// write writes the tuple.
+func (writer *tupleWriter) write(tuple []interface{}) error {
+	switch writer.format {
+	case DataFormatCSV:
+		if writer.header == nil {
+			// The header is written by the format, so tuples without
+			// the format have the same number of fields as the first one.
+			fieldsCount := len(writer.fieldFormat)
+			if len(tuple) > fieldsCount {
+				fieldsCount = len(tuple)
+			}
+			for i := 0; i < fieldsCount; i++ {
+				writer.header = append(writer.header, fieldName(writer.fieldFormat, i))
+			}
+			if err := writer.csvWriter.Write(writer.header); err != nil {
+				return err
+			}
+		}
+		if len(tuple) > len(writer.header) {
+			return fmt.Errorf("tuple has %d fields, but the CSV header has only %d,"+
+				" use %s or %s format", len(tuple), len(writer.header),
+				DataFormatJSONL, DataFormatMsgpack)
+		}
+		row := make([]string, len(writer.header))
+		for i, value := range tuple {
+			cell, err := csvCell(value)
+			if err != nil {
+				return err
+			}
+			// Cells of fields without a format or of types like scalar
+			// are decoded as JSON on import, an empty cell is null.
+			// A string is encoded into JSON if it would be decoded into
+			// another value.
+			str, isString := exportValue(value).(string)
+			if isString && isCSVJSONField(writer.fieldFormat, i) &&
+				(str == "" || decodeJSONValue(str) != interface{}(str)) {
+				encoded, err := json.Marshal(str)
+				if err != nil {
+					return err
+				}
+				cell = string(encoded)
+			}
+			row[i] = cell
+		}
+		return writer.csvWriter.Write(row)
+	case DataFormatJSONL:
+		object := make(map[string]interface{}, len(tuple))
+		for i, value := range tuple {
+			object[fieldName(writer.fieldFormat, i)] = exportValue(value)
+		}
+		encoded, err := json.Marshal(object)
+		if err != nil {
+			return err
+		}
+		_, err = writer.writer.Write(append(encoded, '\n'))
+		return err
+	case DataFormatMsgpack:
+		object := make(map[string]interface{}, len(tuple))
+		for i, value := range tuple {
+			object[fieldName(writer.fieldFormat, i)] = value
+		}
+		return writer.encoder.Encode(object)
+	default:
+		return fmt.Errorf("unsupported format: %s", writer.format)
+	}
+}
+
+// flush flushes buffered data.
+func (writer *tupleWriter) flush() error {
+	if writer.csvWriter != nil {
+		writer.csvWriter.Flush()
+		return writer.csvWriter.Error()
+	}
+	return nil
+}
+
+// findIndex returns the index of the space by a name or an identifier.
+func findIndex(schema SpaceSchema, index string) (IndexInfo, error) {
+	for _, info := range schema.Indexes {
+		if info.Name == index || strconv.FormatUint(uint64(info.ID), 10) == index {
+			return info, nil
+		}
+	}
+	return IndexInfo{}, fmt.Errorf("index %s does not exist in space %s", index, schema.Name)
+}
+
+// ParseWhere parses a filter in the "index:key" format. The key is decoded as
+// JSON if possible and is used as a string otherwise. A scalar is a key of
+// a single part. Values are converted to the types of the index parts.
+func ParseWhere(schema SpaceSchema, where string) (IndexInfo, []interface{}, error) {
+	colonIdx := strings.Index(where, ":")
+	if colonIdx == -1 {
+		return IndexInfo{}, nil, fmt.Errorf("invalid filter %q: expected index:key", where)
+	}
+
+	index, err := findIndex(schema, where[:colonIdx])
+	if err != nil {
+		return IndexInfo{}, nil, err
+	}
+
+	value := decodeJSONValue(where[colonIdx+1:])
+	key, ok := value.([]interface{})
+	if !ok {
+		key = []interface{}{value}
+	}
+	if len(key) > len(index.Parts) {
+		return IndexInfo{}, nil, fmt.Errorf("key %v has more parts than index %s",
+			key, index.Name)
+	}
+	for i, part := range key {
+		if key[i], err = convertFieldValue(part, index.Parts[i].Type); err != nil {
+			return IndexInfo{}, nil, fmt.Errorf("invalid key part %d: %s", i+1, err)
+		}
+	}
+	return index, key, nil
+}
+
+// primaryKey returns the primary key of the tuple.
+func primaryKey(pk IndexInfo, tuple []interface{}) ([]interface{}, error) {
+	key := make([]interface{}, 0, len(pk.Parts))
+	for _, part := range pk.Parts {
+		if part.Path != "" || part.Field < 1 || int(part.Field) > len(tuple) {
+			return nil, fmt.Errorf("unsupported primary key part: field %d%s",
+				part.Field, part.Path)
+		}
+		key = append(key, tuple[part.Field-1])
+	}
+	return key, nil
+}
+
+// tupleBatches selects tuples of the space in batches and calls the function
+// for each batch. All tuples are selected in the order of the primary key
+// if the index is nil, tuples matching the key in the index are selected
+// otherwise.
+func tupleBatches(selecter connector.Selecter, schema SpaceSchema, index *IndexInfo,
+	key []interface{}, batchSize int, do func(tuples []interface{}) error) error {
+	var iterator string
+	if index == nil {
+		if len(schema.Indexes) == 0 {
+			return fmt.Errorf("space %s has no primary index", schema.Name)
+		}
+		index = &schema.Indexes[0]
+		iterator = "ALL"
+	}
+
+	var offset uint32
+	for {
+		selectOpts := connector.SelectOpts{
+			Iterator: iterator,
+			Offset:   offset,
+			Limit:    uint32(batchSize),
+		}
+		tuples, err := selecter.Select(schema.ID, index.ID, key, selectOpts,
+			connector.RequestOpts{})
+		if err != nil {
+			return err
+		}
+		if len(tuples) == 0 {
+			return nil
+		}
+		if err := do(tuples); err != nil {
+			return err
+		}
+		if len(tuples) < batchSize {
+			return nil
+		}
+
+		if iterator == "" {
+			// An offset is used for a filter.
+			offset += uint32(len(tuples))
+			continue
+		}
+		// The next batch of all tuples starts after the last primary key.
+		last, _ := tuples[len(tuples)-1].([]interface{})
+		if key, err = primaryKey(*index, last); err != nil {
+			return err
+		}
+		iterator = "GT"
+	}
+}
+
+// Export writes tuples of the space into the writer in the format. It
+// returns the number of exported tuples.
+func Export(conn connector.Connector, writer io.Writer, opts ExportOpts) (int, error) {
+	schema, err := GetSpaceSchema(conn, opts.Space)
+	if err != nil {
+		return 0, err
+	}
+
+	var index *IndexInfo
+	var key []interface{}
+	total := int64(-1)
+	if opts.Where != "" {
+		where, whereKey, err := ParseWhere(schema, opts.Where)
+		if err != nil {
+			return 0, err
+		}
+		index, key = &where, whereKey
+	} else if opts.Progress != nil {
+		spaces, err := GetSpaces(conn, true)
+		if err != nil {
+			return 0, err
+		}
+		for _, space := range spaces {
+			if space.ID == schema.ID {
+				total = int64(space.Len)
+			}
+		}
+	}
+
+	tupleWriter := newTupleWriter(writer, opts.Format, schema.Format)
+	bar := newCounterProgressBar(opts.Progress, schema.Name, total)
+	defer bar.finish()
+
+	exported := 0
+	err = tupleBatches(conn, schema, index, key, opts.batchSize(),
+		func(tuples []interface{}) error {
+			for _, tuple := range tuples {
+				fields, ok := tuple.([]interface{})
+				if !ok {
+					return fmt.Errorf("unexpected tuple: %v", tuple)
+				}
+				if err := tupleWriter.write(fields); err != nil {
+					return fmt.Errorf("failed to write a tuple: %s", err)
+				}
+			}
+			exported += len(tuples)
+			bar.update(int64(exported))
+			return nil
+		})
+	if err != nil {
+		return exported, err
+	}
+	if err := tupleWriter.flush(); err != nil {
+		return exported, fmt.Errorf("failed to write tuples: %s", err)
+	}
+	return exported, nil
+}
+
+// ExportFromTarget connects to the target and exports tuples of the space.
+func ExportFromTarget(connectCtx ConnectCtx, connString string, writer io.Writer,
+	opts ExportOpts) (int, error) {
+	exported := 0
+	err := DoOnTarget(connectCtx, connString, func(conn connector.Connector) error {
+		var err error
+		exported, err = Export(conn, writer, opts)
+		return err
+	})
+	return exported, err
+}
+
+// decodeJSONValue decodes the value as JSON with integer numbers if possible
+// and returns it as a string otherwise.
+func decodeJSONValue(str string) interface{} {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(str)))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return str
+	}
+	return convertNumbers(value)
+}
+
+// convertNumbers converts JSON numbers into integers if possible and into
+// floats otherwise recursively.
+func convertNumbers(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case json.Number:
+		if i, err := strconv.ParseInt(typed.String(), 10, 64); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(typed.String(), 10, 64); err == nil {
+			return u
+		}
+		f, _ := typed.Float64()
+		return f
+	case []interface{}:
+		for i, v := range typed {
+			typed[i] = convertNumbers(v)
+		}
+		return typed
+	case map[string]interface{}:
+		for k, v := range typed {
+			typed[k] = convertNumbers(v)
+		}
+		return typed
+	default:
+		return value
+	}
+}
@@ -0,0 +1,330 @@
+package connect_test
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+
+	. "github.com/tarantool/tt/cli/connect"
+	"github.com/tarantool/tt/cli/connector"
+)
+
+// spaceConnectorMock stores tuples of the "test" space with an unsigned
+// primary key in the first field and a name in the second one.
+type spaceConnectorMock struct {
+	connector.Connector
+	schema  *schemaEvalerMock
+	tuples  map[uint64][]interface{}
+	selects int
+}
+
+func newSpaceConnectorMock(tuples ...[]interface{}) *spaceConnectorMock {
+	mock := &spaceConnectorMock{
+		schema: newSchemaEvalerMock(),
+		tuples: map[uint64][]interface{}{},
+	}
+	for _, tuple := range tuples {
+		mock.tuples[tuple[0].(uint64)] = tuple
+	}
+	return mock
+}
+
+func (mock *spaceConnectorMock) sortedTuples() [][]interface{} {
+	tuples := [][]interface{}{}
+	for _, tuple := range mock.tuples {
+		tuples = append(tuples, tuple)
+	}
+	sort.Slice(tuples, func(i, j int) bool {
+		return tuples[i][0].(uint64) < tuples[j][0].(uint64)
+	})
+	return tuples
+}
+
+func (mock *spaceConnectorMock) Eval(expr string, args []interface{},
+	opts connector.RequestOpts) ([]interface{}, error) {
+	if _, ok := args[0].(string); ok {
+		return mock.schema.Eval(expr, args, opts)
+	}
+
+	// An import batch.
+	result := ImportResult{}
+	added := map[uint64][]interface{}{}
+	for _, tuple := range args[1].([]interface{}) {
+		fields := tuple.([]interface{})
+		id := fields[0].(uint64)
+		_, exists := mock.tuples[id]
+		if _, ok := added[id]; ok {
+			exists = true
+		}
+		switch {
+		case !exists || args[2] == OnConflictReplace:
+			added[id] = fields
+			result.Inserted++
+		case args[2] == OnConflictSkip:
+			result.Skipped++
+		default:
+			return nil, fmt.Errorf("Duplicate key exists in unique index \"pk\"")
+		}
+	}
+	for id, tuple := range added {
+		mock.tuples[id] = tuple
+	}
+
+	encoded, err := msgpack.Marshal([]interface{}{result})
+	if err != nil {
+		return nil, err
+	}
+	return nil, msgpack.Unmarshal(encoded, opts.ResData)
+}
+
+func (mock *spaceConnectorMock) Select(space, index interface{}, key []interface{},
+	selectOpts connector.SelectOpts, opts connector.RequestOpts) ([]interface{}, error) {
+	mock.selects++
+	matched := []interface{}{}
+	for _, tuple := range mock.sortedTuples() {
+		switch selectOpts.Iterator {
+		case "ALL":
+		case "GT":
+			if tuple[0].(uint64) <= key[0].(uint64) {
+				continue
+			}
+		default:
+			if tuple[index.(uint32)] != key[0] {
+				continue
+			}
+		}
+		matched = append(matched, tuple)
+	}
+
+	if int(selectOpts.Offset) >= len(matched) {
+		return []interface{}{}, nil
+	}
+	matched = matched[selectOpts.Offset:]
+	if len(matched) > int(selectOpts.Limit) {
+		matched = matched[:selectOpts.Limit]
+	}
+	return matched, nil
+}
+
+var exportTuples = [][]interface{}{
+	{uint64(1), "one"},
+	{uint64(2), "two, \"quoted\""},
+	{uint64(3), nil},
+	{uint64(4), "four"},
+	{uint64(5), "two, \"quoted\""},
+}
+
+func TestExport(t *testing.T) {
+	cases := []struct {
+		format   string
+		expected string
+	}{
+		{DataFormatCSV, "id,name\n" +
+			"1,one\n" +
+			"2,\"two, \"\"quoted\"\"\"\n" +
+			"3,\n" +
+			"4,four\n" +
+			"5,\"two, \"\"quoted\"\"\"\n"},
+		{DataFormatJSONL, "{\"id\":1,\"name\":\"one\"}\n" +
+			"{\"id\":2,\"name\":\"two, \\\"quoted\\\"\"}\n" +
+			"{\"id\":3,\"name\":null}\n" +
+			"{\"id\":4,\"name\":\"four\"}\n" +
+			"{\"id\":5,\"name\":\"two, \\\"quoted\\\"\"}\n"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.format, func(t *testing.T) {
+			mock := newSpaceConnectorMock(exportTuples...)
+			var buf bytes.Buffer
+			exported, err := Export(mock, &buf, ExportOpts{Space: "test", Format: tc.format,
+				BatchSize: 2})
+			require.NoError(t, err)
+			assert.Equal(t, 5, exported)
+			assert.Equal(t, tc.expected, buf.String())
+			// The last batch is empty.
+			assert.Equal(t, 3, mock.selects)
+		})
+	}
+}
+
+func TestExport_where(t *testing.T) {
+	mock := newSpaceConnectorMock(exportTuples...)
+	var buf bytes.Buffer
+	exported, err := Export(mock, &buf, ExportOpts{Space: "test", Format: DataFormatJSONL,
+		Where: "name:two, \"quoted\"", BatchSize: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, exported)
+	assert.Equal(t, "{\"id\":2,\"name\":\"two, \\\"quoted\\\"\"}\n"+
+		"{\"id\":5,\"name\":\"two, \\\"quoted\\\"\"}\n", buf.String())
+
+	buf.Reset()
+	exported, err = Export(mock, &buf, ExportOpts{Space: "test", Format: DataFormatCSV,
+		Where: "pk:[4]"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, exported)
+	assert.Equal(t, "id,name\n4,four\n", buf.String())
+
+	_, err = Export(mock, &buf, ExportOpts{Space: "test", Format: DataFormatCSV,
+		Where: "unknown:1"})
+	assert.EqualError(t, err, "index unknown does not exist in space test")
+
+	_, err = Export(mock, &buf, ExportOpts{Space: "test", Format: DataFormatCSV,
+		Where: "pk"})
+	assert.EqualError(t, err, "invalid filter \"pk\": expected index:key")
+}
+
+func TestExportImport(t *testing.T) {
+	for _, format := range DataFormats {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			_, err := Export(newSpaceConnectorMock(exportTuples...), &buf,
+				ExportOpts{Space: "test", Format: format})
+			require.NoError(t, err)
+
+			mock := newSpaceConnectorMock()
+			result, err := Import(mock, &buf, ImportOpts{Space: "test", Format: format,
+				BatchSize: 2})
+			require.NoError(t, err)
+			assert.Equal(t, ImportResult{Inserted: 5}, result)
+
+			assert.Equal(t, exportTuples, mock.sortedTuples())
+		})
+	}
+}
+
+func TestExportImport_csvFieldsWithoutFormat(t *testing.T) {
+	tuples := [][]interface{}{
+		{uint64(1), "one", nil, "a", int64(-1)},
+		{uint64(2), "two", "", "12"},
+		{uint64(3), "three", "null"},
+		{uint64(4), "four"},
+	}
+
+	var buf bytes.Buffer
+	_, err := Export(newSpaceConnectorMock(tuples...), &buf,
+		ExportOpts{Space: "test", Format: DataFormatCSV})
+	require.NoError(t, err)
+	assert.Equal(t, "id,name,field_3,field_4,field_5\n"+
+		"1,one,,a,-1\n"+
+		"2,two,\"\"\"\"\"\",\"\"\"12\"\"\",\n"+
+		"3,three,\"\"\"null\"\"\",,\n"+
+		"4,four,,,\n", buf.String())
+
+	mock := newSpaceConnectorMock()
+	_, err = Import(mock, &buf, ImportOpts{Space: "test", Format: DataFormatCSV})
+	require.NoError(t, err)
+	assert.Equal(t, tuples, mock.sortedTuples())
+}
+
+func TestExportImport_csvScalarField(t *testing.T) {
+	withValueField := func(mock *spaceConnectorMock) *spaceConnectorMock {
+		schema := mock.schema.schemas["test"].(map[string]interface{})
+		schema["format"] = append(schema["format"].([]interface{}),
+			map[string]interface{}{"name": "value", "type": "scalar", "is_nullable": true})
+		return mock
+	}
+	tuples := [][]interface{}{
+		{uint64(1), "one", "42"},
+		{uint64(2), "two", "true"},
+		{uint64(3), "three", uint64(42)},
+		{uint64(4), "four", ""},
+		{uint64(5), "five", nil},
+		{uint64(6), "six", "text"},
+	}
+
+	var buf bytes.Buffer
+	_, err := Export(withValueField(newSpaceConnectorMock(tuples...)), &buf,
+		ExportOpts{Space: "test", Format: DataFormatCSV})
+	require.NoError(t, err)
+	assert.Equal(t, "id,name,value\n"+
+		"1,one,\"\"\"42\"\"\"\n"+
+		"2,two,\"\"\"true\"\"\"\n"+
+		"3,three,42\n"+
+		"4,four,\"\"\"\"\"\"\n"+
+		"5,five,\n"+
+		"6,six,text\n", buf.String())
+
+	mock := withValueField(newSpaceConnectorMock())
+	_, err = Import(mock, &buf, ImportOpts{Space: "test", Format: DataFormatCSV})
+	require.NoError(t, err)
+	assert.Equal(t, tuples, mock.sortedTuples())
+}
+
+func TestExport_extraFields(t *testing.T) {
+	tuples := [][]interface{}{
+		{uint64(1), "one"},
+		{uint64(2), "two", []interface{}{int64(-1), "a"}},
+	}
+
+	var buf bytes.Buffer
+	_, err := Export(newSpaceConnectorMock(tuples...), &buf,
+		ExportOpts{Space: "test", Format: DataFormatJSONL})
+	require.NoError(t, err)
+	assert.Equal(t, "{\"id\":1,\"name\":\"one\"}\n"+
+		"{\"field_3\":[-1,\"a\"],\"id\":2,\"name\":\"two\"}\n", buf.String())
+
+	mock := newSpaceConnectorMock()
+	_, err = Import(mock, &buf, ImportOpts{Space: "test", Format: DataFormatJSONL})
+	require.NoError(t, err)
+	assert.Equal(t, tuples, mock.sortedTuples())
+
+	// The CSV header is written by the first tuple.
+	_, err = Export(newSpaceConnectorMock(tuples...), &buf,
+		ExportOpts{Space: "test", Format: DataFormatCSV})
+	assert.EqualError(t, err, "failed to write a tuple: tuple has 3 fields,"+
+		" but the CSV header has only 2, use jsonl or msgpack format")
+}
+
+func TestImport_onConflict(t *testing.T) {
+	input := "{\"id\": 1, \"name\": \"new\"}\n\n{\"name\": \"two\", \"id\": 2.0}\n"
+
+	mock := newSpaceConnectorMock([]interface{}{uint64(1), "old"})
+	_, err := Import(mock, strings.NewReader(input), ImportOpts{Space: "test",
+		Format: DataFormatJSONL})
+	assert.EqualError(t, err, "failed to import records 1-2: "+
+		"Duplicate key exists in unique index \"pk\"")
+
+	result, err := Import(mock, strings.NewReader(input), ImportOpts{Space: "test",
+		Format: DataFormatJSONL, OnConflict: OnConflictSkip})
+	require.NoError(t, err)
+	assert.Equal(t, ImportResult{Inserted: 1, Skipped: 1}, result)
+	assert.Equal(t, [][]interface{}{{uint64(1), "old"}, {uint64(2), "two"}},
+		mock.sortedTuples())
+
+	result, err = Import(mock, strings.NewReader(input), ImportOpts{Space: "test",
+		Format: DataFormatJSONL, OnConflict: OnConflictReplace})
+	require.NoError(t, err)
+	assert.Equal(t, ImportResult{Inserted: 2}, result)
+	assert.Equal(t, [][]interface{}{{uint64(1), "new"}, {uint64(2), "two"}},
+		mock.sortedTuples())
+}
+
+func TestImport_errors(t *testing.T) {
+	cases := []struct {
+		format   string
+		input    string
+		expected string
+	}{
+		{DataFormatJSONL, "{\"id\": 1, \"unknown\": 2}\n", "record 1: unknown field \"unknown\""},
+		{DataFormatJSONL, "{\"id\": 1}\n[1, 2]\n",
+			"failed to read record 2: a JSON object is expected: [1, 2]"},
+		{DataFormatJSONL, "{\"id\": 1.5}\n",
+			"record 1: invalid value of field \"id\": 1.5 is not an integer"},
+		{DataFormatCSV, "id,name\nabc,one\n", "failed to read record 1: invalid value of" +
+			" field \"id\": strconv.ParseUint: parsing \"abc\": invalid syntax"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.expected, func(t *testing.T) {
+			_, err := Import(newSpaceConnectorMock(), strings.NewReader(tc.input),
+				ImportOpts{Space: "test", Format: tc.format})
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,352 @@
+package connect
+
+import (
+	"strings"
+
+	"github.com/c-bata/go-prompt"
+)
+
+// luaTokenKind is a kind of a Lua token for the highlighting.
+type luaTokenKind int
+
+const (
+	luaTokenOther luaTokenKind = iota
+	luaTokenKeyword
+	luaTokenString
+	luaTokenNumber
+	luaTokenComment
+	luaTokenBracket
+)
+
+// luaKeywords is a set of Lua keywords.
+var luaKeywords = map[string]bool{
+	"and": true, "break": true, "do": true, "else": true, "elseif": true,
+	"end": true, "false": true, "for": true, "function": true, "goto": true,
+	"if": true, "in": true, "local": true, "nil": true, "not": true, "or": true,
+	"repeat": true, "return": true, "then": true, "true": true, "until": true,
+	"while": true,
+}
+
+// luaBlockClosers maps Lua block openers and brackets to their closers.
+var luaBlockClosers = map[string]string{
+	"function": "end",
+	"if":       "end",
+	"do":       "end",
+	"while":    "end",
+	"for":      "end",
+	"repeat":   "until",
+	"(":        ")",
+	"[":        "]",
+	"{":        "}",
+}
+
+// luaToken is a token of a Lua statement. Start and end are byte offsets.
+type luaToken struct {
+	kind  luaTokenKind
+	text  string
+	start int
+	end   int
+}
+
+// isLuaWordByte returns true if the byte could be a part of a Lua name.
+func isLuaWordByte(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') ||
+		('0' <= c && c <= '9')
+}
+
+// longBracketLevel returns a level of a Lua long bracket "[==[" at the
+// position or -1 if there is no long bracket.
+func longBracketLevel(text string, pos int) int {
+	if pos >= len(text) || text[pos] != '[' {
+		return -1
+	}
+	level := 0
+	for pos+1+level < len(text) && text[pos+1+level] == '=' {
+		level++
+	}
+	if pos+1+level < len(text) && text[pos+1+level] == '[' {
+		return level
+	}
+	return -1
+}
+
+// longBracketEnd returns a position after the long bracket of the level
+// started at the position. It returns the length of the text if the bracket
+// is not closed.
+func longBracketEnd(text string, pos int, level int) int {
+	closer := "]" + strings.Repeat("=", level) + "]"
+	end := strings.Index(text[pos+level+2:], closer)
+	if end == -1 {
+		return len(text)
+	}
+	return pos + level + 2 + end + len(closer)
+}
+
+// tokenizeLua splits the Lua text into tokens. Whitespace is skipped,
+// unterminated strings and comments last until the end of the text.
+func tokenizeLua(text string) []luaToken {
+	tokens := []luaToken{}
+	add := func(kind luaTokenKind, start, end int) {
+		tokens = append(tokens, luaToken{kind, text[start:end], start, end})
+	}
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case strings.HasPrefix(text[i:], "--"):
+			end := len(text)
+			if level := longBracketLevel(text, i+2); level >= 0 {
+				end = longBracketEnd(text, i+2, level)
+			} else if newline := strings.IndexByte(text[i:], '\n'); newline != -1 {
+				end = i + newline
+			}
+			add(luaTokenComment, i, end)
+			i = end
+		case c == '\'' || c == '"':
+			end := i + 1
+			for end < len(text) && text[end] != c && text[end] != '\n' {
+				if text[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end < len(text) && text[end] == c {
+				end++
+			}
+			if end > len(text) {
+				end = len(text)
+			}
+			add(luaTokenString, i, end)
+			i = end
+		case strings.HasPrefix(text[i:], ".."):
+			// The concatenation or the vararg.
+			end := i + 2
+			if strings.HasPrefix(text[i:], "...") {
+				end++
+			}
+			add(luaTokenOther, i, end)
+			i = end
+		case longBracketLevel(text, i) >= 0:
+			end := longBracketEnd(text, i, longBracketLevel(text, i))
+			add(luaTokenString, i, end)
+			i = end
+		case '0' <= c && c <= '9' || c == '.' && i+1 < len(text) &&
+			'0' <= text[i+1] && text[i+1] <= '9':
+			end := i + 1
+			for end < len(text) && (isLuaWordByte(text[end]) ||
+				text[end] == '.' && !strings.HasPrefix(text[end:], "..") ||
+				(text[end] == '-' || text[end] == '+') &&
+					strings.ContainsRune("eEpP", rune(text[end-1]))) {
+				end++
+			}
+			add(luaTokenNumber, i, end)
+			i = end
+		case isLuaWordByte(c):
+			end := i + 1
+			for end < len(text) && isLuaWordByte(text[end]) {
+				end++
+			}
+			if luaKeywords[text[i:end]] {
+				add(luaTokenKeyword, i, end)
+			} else {
+				add(luaTokenOther, i, end)
+			}
+			i = end
+		case strings.ContainsRune("()[]{}", rune(c)):
+			add(luaTokenBracket, i, i+1)
+			i++
+		default:
+			add(luaTokenOther, i, i+1)
+			i++
+		}
+	}
+	return tokens
+}
+
+// matchLuaBlocks returns pairs of indexes of matching brackets and block
+// keywords, e.g. "function" and "end". Both directions are included.
+func matchLuaBlocks(tokens []luaToken) map[int]int {
+	type opener struct {
+		index int
+		// awaitsDo is true for a loop, "do" of the loop does not open
+		// a new block.
+		awaitsDo bool
+	}
+
+	pairs := map[int]int{}
+	stack := []opener{}
+	for i, token := range tokens {
+		if token.kind != luaTokenKeyword && token.kind != luaTokenBracket {
+			continue
+		}
+
+		if token.text == "do" && len(stack) > 0 && stack[len(stack)-1].awaitsDo {
+			stack[len(stack)-1].awaitsDo = false
+			continue
+		}
+		if _, ok := luaBlockClosers[token.text]; ok {
+			stack = append(stack, opener{
+				index:    i,
+				awaitsDo: token.text == "while" || token.text == "for",
+			})
+			continue
+		}
+
+		// Find the nearest opener closed by the token. Mismatched
+		// openers are dropped.
+		for j := len(stack) - 1; j >= 0; j-- {
+			if luaBlockClosers[tokens[stack[j].index].text] == token.text {
+				pairs[stack[j].index] = i
+				pairs[i] = stack[j].index
+				stack = stack[:j]
+				break
+			}
+		}
+	}
+	return pairs
+}
+
+// luaHighlight describes a highlighting of a Lua text.
+type luaHighlight struct {
+	text   string
+	tokens []luaToken
+	// matched is a set of indexes of highlighted matching tokens.
+	matched map[int]bool
+}
+
+// newLuaHighlight creates a highlighting of the text. A bracket or a block
+// keyword at the cursor or before it is highlighted with its pair.
+func newLuaHighlight(text string, cursor int) *luaHighlight {
+	highlight := &luaHighlight{
+		text:    text,
+		tokens:  tokenizeLua(text),
+		matched: map[int]bool{},
+	}
+
+	pairs := matchLuaBlocks(highlight.tokens)
+	// The token before the cursor has a priority, since it is just typed.
+	for _, pos := range []int{cursor - 1, cursor} {
+		for i, token := range highlight.tokens {
+			if token.start <= pos && pos < token.end {
+				if pair, ok := pairs[i]; ok {
+					highlight.matched[i] = true
+					highlight.matched[pair] = true
+					return highlight
+				}
+			}
+		}
+	}
+	return highlight
+}
+
+// luaTokenColors maps token kinds to colors.
+var luaTokenColors = map[luaTokenKind]prompt.Color{
+	luaTokenKeyword: prompt.Purple,
+	luaTokenString:  prompt.DarkGreen,
+	luaTokenNumber:  prompt.Brown,
+	luaTokenComment: prompt.DarkGray,
+}
+
+// write writes the part of the text in [start, end) with colors.
+func (highlight *luaHighlight) write(out prompt.ConsoleWriter, start, end int) {
+	pos := start
+	for i, token := range highlight.tokens {
+		if token.end <= pos || token.start >= end {
+			continue
+		}
+		if token.start > pos {
+			out.WriteStr(highlight.text[pos:token.start])
+			pos = token.start
+		}
+
+		tokenEnd := token.end
+		if tokenEnd > end {
+			tokenEnd = end
+		}
+		color, colored := luaTokenColors[token.kind]
+		switch {
+		case highlight.matched[i]:
+			out.SetColor(prompt.Black, prompt.Turquoise, true)
+		case colored:
+			out.SetColor(color, prompt.DefaultColor, token.kind == luaTokenKeyword)
+		}
+		out.WriteStr(highlight.text[pos:tokenEnd])
+		if highlight.matched[i] || colored {
+			out.SetColor(prompt.DefaultColor, prompt.DefaultColor, false)
+		}
+		pos = tokenEnd
+	}
+	if pos < end {
+		out.WriteStr(highlight.text[pos:end])
+	}
+}
+
+// highlightWriter highlights the input text of the console written by
+// the prompt. The input is written after the prefix in one or several
+// parts, other writes are passed as is.
+type highlightWriter struct {
+	prompt.ConsoleWriter
+	// console is the console to get the current input and the prefix from.
+	console *Console
+	// highlight is the highlighting of the input being written. It is nil
+	// if the input is not being written.
+	highlight *luaHighlight
+	// offset is an offset of the next input part.
+	offset int
+	// cursor is true if the next input part is the cursor, it is written
+	// without colors to keep it visible.
+	cursor bool
+}
+
+// SetDisplayAttributes sets display attributes. The prompt uses them only to
+// draw the cursor in a multiline input.
+func (writer *highlightWriter) SetDisplayAttributes(fg, bg prompt.Color,
+	attrs ...prompt.DisplayAttribute) {
+	writer.cursor = writer.highlight != nil
+	writer.ConsoleWriter.SetDisplayAttributes(fg, bg, attrs...)
+}
+
+// WriteStr writes the string. Parts of the input are highlighted.
+func (writer *highlightWriter) WriteStr(data string) {
+	if writer.highlight != nil {
+		rest := writer.highlight.text[writer.offset:]
+		switch {
+		case data == "":
+		case strings.HasPrefix(rest, data):
+			if writer.cursor {
+				writer.ConsoleWriter.WriteStr(data)
+			} else {
+				writer.highlight.write(writer.ConsoleWriter, writer.offset,
+					writer.offset+len(data))
+			}
+			writer.offset += len(data)
+			writer.cursor = false
+			return
+		case data == rest+"\n":
+			// The whole input is written on the line break.
+			writer.highlight.write(writer.ConsoleWriter, writer.offset,
+				len(writer.highlight.text))
+			writer.ConsoleWriter.WriteStr("\n")
+			writer.highlight = nil
+			return
+		case data == " \n" && strings.HasPrefix(rest, "\n"):
+			// The cursor on the line break.
+			writer.offset++
+		default:
+			writer.highlight = nil
+		}
+		writer.cursor = false
+		writer.ConsoleWriter.WriteStr(data)
+		return
+	}
+
+	writer.ConsoleWriter.WriteStr(data)
+	if prefix, _ := writer.console.livePrefixFunc(); data == prefix &&
+		writer.console.highlightEnabled() {
+		writer.highlight = newLuaHighlight(writer.console.buffer.Text,
+			len(writer.console.buffer.TextBeforeCursor()))
+		writer.offset = 0
+	}
+}
@@ -0,0 +1,169 @@
+package connect
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/c-bata/go-prompt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenizeLua(t *testing.T) {
+	text := "local s = 'a\\'b' .. [[x]] -- c\n" +
+		"return 0x1F + 1.5e-3 + 1..2 --[=[ d\n]=] x"
+	expected := []luaToken{
+		{luaTokenKeyword, "local", 0, 5},
+		{luaTokenOther, "s", 6, 7},
+		{luaTokenOther, "=", 8, 9},
+		{luaTokenString, "'a\\'b'", 10, 16},
+		{luaTokenOther, "..", 17, 19},
+		{luaTokenString, "[[x]]", 20, 25},
+		{luaTokenComment, "-- c", 26, 30},
+		{luaTokenKeyword, "return", 31, 37},
+		{luaTokenNumber, "0x1F", 38, 42},
+		{luaTokenOther, "+", 43, 44},
+		{luaTokenNumber, "1.5e-3", 45, 51},
+		{luaTokenOther, "+", 52, 53},
+		{luaTokenNumber, "1", 54, 55},
+		{luaTokenOther, "..", 55, 57},
+		{luaTokenNumber, "2", 57, 58},
+		{luaTokenComment, "--[=[ d\n]=]", 59, 70},
+		{luaTokenOther, "x", 71, 72},
+	}
+	assert.Equal(t, expected, tokenizeLua(text))
+}
+
+func TestTokenizeLua_unterminated(t *testing.T) {
+	assert.Equal(t, []luaToken{{luaTokenString, "\"abc", 0, 4}}, tokenizeLua("\"abc"))
+	assert.Equal(t, []luaToken{{luaTokenString, "[==[a]]", 0, 7}}, tokenizeLua("[==[a]]"))
+	assert.Equal(t, []luaToken{{luaTokenString, "'\\", 0, 2}}, tokenizeLua("'\\"))
+	assert.Equal(t, []luaToken{{luaTokenOther, "...", 0, 3}}, tokenizeLua("..."))
+}
+
+func TestMatchLuaBlocks(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected map[string]string
+	}{
+		{"function f() end", map[string]string{"function": "end", "(": ")"}},
+		{"while x do end", map[string]string{"while": "end"}},
+		{"for i = 1, 2 do end", map[string]string{"for": "end"}},
+		{"do end", map[string]string{"do": "end"}},
+		{"repeat x() until y", map[string]string{"repeat": "until", "(": ")"}},
+		{"t = {[1] = 'end'}", map[string]string{"{": "}", "[": "]"}},
+		{"if x then", map[string]string{}},
+		{"f(] end", map[string]string{}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.text, func(t *testing.T) {
+			tokens := tokenizeLua(tc.text)
+			actual := map[string]string{}
+			for i, j := range matchLuaBlocks(tokens) {
+				assert.Equal(t, i, matchLuaBlocks(tokens)[j])
+				if i < j {
+					actual[tokens[i].text] = tokens[j].text
+				}
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestNewLuaHighlight(t *testing.T) {
+	text := "if f(x) then end"
+	cases := []struct {
+		cursor   int
+		expected []string
+	}{
+		{0, []string{"if", "end"}},
+		{2, []string{"if", "end"}},
+		{3, []string{}},
+		{5, []string{"(", ")"}},
+		{7, []string{"(", ")"}},
+		{len(text), []string{"if", "end"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprint(tc.cursor), func(t *testing.T) {
+			highlight := newLuaHighlight(text, tc.cursor)
+			actual := []string{}
+			for i, token := range highlight.tokens {
+				if highlight.matched[i] {
+					actual = append(actual, token.text)
+				}
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+// recordWriter records written strings and colors.
+type recordWriter struct {
+	prompt.ConsoleWriter
+	out strings.Builder
+}
+
+func (writer *recordWriter) WriteStr(data string) {
+	writer.out.WriteString(data)
+}
+
+func (writer *recordWriter) SetColor(fg, bg prompt.Color, bold bool) {
+	if fg == prompt.DefaultColor && bg == prompt.DefaultColor {
+		writer.out.WriteString("</>")
+	} else {
+		writer.out.WriteString(fmt.Sprintf("<%d,%d,%t>", fg, bg, bold))
+	}
+}
+
+func (writer *recordWriter) SetDisplayAttributes(_, _ prompt.Color,
+	_ ...prompt.DisplayAttribute) {
+	writer.out.WriteString("<cursor>")
+}
+
+func TestHighlightWriter(t *testing.T) {
+	console := &Console{title: "app", language: LuaLanguage, interactive: true}
+	setPrefix(console)
+	buf := prompt.NewBuffer()
+	buf.InsertText("do\nreturn 1 end", false, true)
+	buf.CursorLeft(3)
+	console.buffer = *buf.Document()
+
+	out := &recordWriter{}
+	writer := &highlightWriter{ConsoleWriter: out, console: console}
+	writer.WriteStr("app> ")
+	writer.WriteStr("do\nreturn 1 ")
+	writer.SetDisplayAttributes(prompt.DefaultColor, prompt.DefaultColor)
+	writer.WriteStr("e")
+	writer.WriteStr("nd")
+	writer.WriteStr("suggestion")
+
+	keyword := fmt.Sprintf("<%d,%d,true>", prompt.Purple, prompt.DefaultColor)
+	matched := fmt.Sprintf("<%d,%d,true>", prompt.Black, prompt.Turquoise)
+	number := fmt.Sprintf("<%d,%d,false>", prompt.Brown, prompt.DefaultColor)
+	expected := "app> " +
+		matched + "do</>\n" + keyword + "return</> " + number + "1</>" +
+		" <cursor>e" + matched + "nd</>suggestion"
+	assert.Equal(t, expected, out.out.String())
+}
+
+func TestHighlightWriter_disabled(t *testing.T) {
+	console := &Console{title: "app", language: SQLLanguage, interactive: true}
+	setPrefix(console)
+	buf := prompt.NewBuffer()
+	buf.InsertText("select 1", false, true)
+	console.buffer = *buf.Document()
+
+	out := &recordWriter{}
+	writer := &highlightWriter{ConsoleWriter: out, console: console}
+	writer.WriteStr("app> ")
+	writer.WriteStr("select 1\n")
+	assert.Equal(t, "app> select 1\n", out.out.String())
+}
+
+func TestGetEraseLinesSeq(t *testing.T) {
+	assert.Equal(t, "\x1b[1A\r\x1b[J", getEraseLinesSeq("app> x", 0))
+	assert.Equal(t, "\x1b[3A\r\x1b[J", getEraseLinesSeq("app> x\n\ny", 80))
+	assert.Equal(t, "\x1b[4A\r\x1b[J", getEraseLinesSeq("app> 0123456789\nx", 5))
+}
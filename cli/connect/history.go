@@ -0,0 +1,311 @@
+package connect
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tarantool/tt/cli/util"
+)
+
+// historyHeaderRe matches a header line of a history record. A header
+// contains the record timestamp. All lines after the header till the next
+// one belong to the same (multiline) command. The format is compatible with
+// readline history timestamps.
+var historyHeaderRe = regexp.MustCompile(`^#(\d+)$`)
+
+// historyTargetRe matches symbols to replace in a connection target to get
+// a history file name.
+var historyTargetRe = regexp.MustCompile(`[^\w.-]+`)
+
+// commandHistory stores the console commands history in a file.
+type commandHistory struct {
+	// filepath is a path to the history file.
+	filepath string
+	// maxCommands is a maximum number of commands to keep.
+	maxCommands int
+	// commands is a list of the loaded and added commands, the last one is
+	// the newest.
+	commands []string
+	// records is a number of records in the file, including duplicates.
+	records int
+}
+
+// newCommandHistory creates a new history object and loads commands from
+// the file.
+func newCommandHistory(filepath string, maxCommands int) (*commandHistory, error) {
+	history := &commandHistory{
+		filepath:    filepath,
+		maxCommands: maxCommands,
+		commands:    []string{},
+	}
+
+	if err := history.load(); err != nil {
+		return history, err
+	}
+	return history, nil
+}
+
+// getHistoryFilePath returns a path to the history file. If target is not
+// empty, a separate history file for the connection target is used.
+func getHistoryFilePath(target string) (string, error) {
+	homeDir, err := util.GetHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %s", err)
+	}
+
+	if target == "" {
+		return filepath.Join(homeDir, HistoryFileName), nil
+	}
+
+	name := strings.Trim(historyTargetRe.ReplaceAllString(target, "_"), "_")
+	return filepath.Join(homeDir, HistoryDirName, name), nil
+}
+
+// parseHistory parses the history file content. Lines without a header
+// (written by an old version or by tarantool) are separate commands.
+func parseHistory(data []byte) ([]string, int) {
+	commands := []string{}
+	records := 0
+
+	var current []string
+	inRecord := false
+	flush := func() {
+		if inRecord && len(current) > 0 {
+			commands = append(commands, strings.Join(current, "\n"))
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if historyHeaderRe.MatchString(line) {
+			flush()
+			inRecord = true
+			records++
+			continue
+		}
+
+		if inRecord {
+			current = append(current, line)
+		} else if strings.TrimSpace(line) != "" {
+			commands = append(commands, line)
+			records++
+		}
+	}
+	flush()
+
+	// Remove the trailing empty line of the last record.
+	for i, command := range commands {
+		commands[i] = strings.TrimRight(command, "\n")
+	}
+
+	return commands, records
+}
+
+// load loads commands from the history file.
+func (history *commandHistory) load() error {
+	data, err := ioutil.ReadFile(history.filepath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read history from file: %s", err)
+	}
+
+	var commands []string
+	commands, history.records = parseHistory(data)
+	for _, command := range commands {
+		history.add(command)
+	}
+	return nil
+}
+
+// importLines adds commands from a line-oriented history file and writes
+// them to the history file. Each non-empty line is a separate command.
+func (history *commandHistory) importLines(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read history from file: %s", err)
+	}
+
+	imported := false
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) != "" && history.add(line) {
+			imported = true
+		}
+	}
+	if !imported {
+		return nil
+	}
+	return history.rewrite()
+}
+
+// add adds a command to the in-memory list. A previous duplicate of the
+// command is removed.
+func (history *commandHistory) add(command string) bool {
+	if command == "" {
+		return false
+	}
+
+	last := len(history.commands) - 1
+	if last >= 0 && history.commands[last] == command {
+		return false
+	}
+
+	for i, cmd := range history.commands {
+		if cmd == command {
+			history.commands = append(history.commands[:i], history.commands[i+1:]...)
+			break
+		}
+	}
+
+	history.commands = append(history.commands, command)
+	if len(history.commands) > history.maxCommands {
+		history.commands = history.commands[len(history.commands)-history.maxCommands:]
+	}
+	return true
+}
+
+// formatRecord formats a history record.
+func formatRecord(command string, timestamp time.Time) string {
+	return fmt.Sprintf("#%d\n%s\n", timestamp.Unix(), command)
+}
+
+// appendCommand adds a command to the history and appends it to the file.
+// Consecutive duplicates are skipped.
+func (history *commandHistory) appendCommand(command string) error {
+	if !history.add(command) {
+		return nil
+	}
+
+	if history.records >= 2*history.maxCommands {
+		return history.rewrite()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(history.filepath), 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %s", err)
+	}
+
+	// The whole record is written with a single write call to keep appends
+	// from concurrent consoles atomic.
+	// see https://unix.stackexchange.com/questions/346062/concurrent-writing-to-a-log-file-from-many-processes
+	file, err := os.OpenFile(history.filepath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history file for append: %s", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(formatRecord(command, time.Now())); err != nil {
+		return fmt.Errorf("failed to append to history file: %s", err)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync history file: %s", err)
+	}
+	history.records++
+
+	return nil
+}
+
+// rewrite replaces the history file with the in-memory commands to drop
+// duplicates and old commands.
+func (history *commandHistory) rewrite() error {
+	if err := os.MkdirAll(filepath.Dir(history.filepath), 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %s", err)
+	}
+
+	var buf bytes.Buffer
+	now := time.Now()
+	for _, command := range history.commands {
+		buf.WriteString(formatRecord(command, now))
+	}
+
+	tmpFile, err := ioutil.TempFile(filepath.Dir(history.filepath),
+		filepath.Base(history.filepath)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary history file: %s", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(buf.Bytes()); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to write history file: %s", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to write history file: %s", err)
+	}
+	if err := os.Chmod(tmpFile.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to set history file permissions: %s", err)
+	}
+	if err := os.Rename(tmpFile.Name(), history.filepath); err != nil {
+		return fmt.Errorf("failed to replace history file: %s", err)
+	}
+	history.records = len(history.commands)
+
+	return nil
+}
+
+// search returns numbered commands containing the pattern. All commands
+// are returned for an empty pattern.
+func (history *commandHistory) search(pattern string) []historyEntry {
+	entries := []historyEntry{}
+	for i, command := range history.commands {
+		if strings.Contains(command, pattern) {
+			entries = append(entries, historyEntry{i + 1, command})
+		}
+	}
+	return entries
+}
+
+// historySearch is a state of the reverse search in the history.
+type historySearch struct {
+	// pattern is the searched text.
+	pattern string
+	// index is an index of the current match in the commands.
+	index int
+	// match is the current match. The search is restarted if the prompt
+	// text is changed.
+	match string
+	// active is true if the search is started.
+	active bool
+}
+
+// next returns the closest command before the current match that contains
+// the pattern. A new search of the text is started if the text is not
+// the current match.
+func (search *historySearch) next(commands []string, text string) (string, bool) {
+	if !search.active || text != search.match {
+		*search = historySearch{pattern: text, index: len(commands), active: true}
+	}
+	for i := search.index - 1; i >= 0; i-- {
+		if strings.Contains(commands[i], search.pattern) {
+			search.index = i
+			search.match = commands[i]
+			return commands[i], true
+		}
+	}
+	return "", false
+}
+
+// historyEntry is a numbered command from the history.
+type historyEntry struct {
+	number  int
+	command string
+}
+
+// String returns a string representation of the entry. Lines of a
+// multiline command are aligned.
+func (entry historyEntry) String() string {
+	number := strconv.Itoa(entry.number)
+	indent := strings.Repeat(" ", len(number)+2)
+	return fmt.Sprintf("%s  %s", number,
+		strings.ReplaceAll(entry.command, "\n", "\n"+indent))
+}
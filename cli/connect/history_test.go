@@ -0,0 +1,167 @@
+package connect
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/c-bata/go-prompt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHistory(t *testing.T) {
+	data := "old command 1\n" +
+		"\n" +
+		"old command 2\n" +
+		"#1600000000\n" +
+		"box.cfg{}\n" +
+		"#1600000001\n" +
+		"for i = 1, 10 do\n" +
+		"    print(i)\n" +
+		"end\n" +
+		"#1600000002\n" +
+		"return 1\n"
+
+	commands, records := parseHistory([]byte(data))
+	assert.Equal(t, []string{
+		"old command 1",
+		"old command 2",
+		"box.cfg{}",
+		"for i = 1, 10 do\n    print(i)\nend",
+		"return 1",
+	}, commands)
+	assert.Equal(t, 5, records)
+}
+
+func TestCommandHistory_appendCommand(t *testing.T) {
+	historyFile := filepath.Join(t.TempDir(), "subdir", "history")
+
+	history, err := newCommandHistory(historyFile, 100)
+	require.NoError(t, err)
+	assert.Equal(t, []string{}, history.commands)
+
+	for _, command := range []string{
+		"return 1",
+		"return 1",
+		"local a = 1\nreturn a",
+		"return 2",
+		"return 1",
+	} {
+		require.NoError(t, history.appendCommand(command))
+	}
+	expected := []string{"local a = 1\nreturn a", "return 2", "return 1"}
+	assert.Equal(t, expected, history.commands)
+
+	// Load the history file again.
+	loaded, err := newCommandHistory(historyFile, 100)
+	require.NoError(t, err)
+	assert.Equal(t, expected, loaded.commands)
+	assert.Equal(t, 4, loaded.records)
+}
+
+func TestCommandHistory_maxCommands(t *testing.T) {
+	historyFile := filepath.Join(t.TempDir(), "history")
+
+	history, err := newCommandHistory(historyFile, 3)
+	require.NoError(t, err)
+	for _, command := range []string{"1", "2", "3", "4", "5", "6", "7"} {
+		require.NoError(t, history.appendCommand(command))
+	}
+	assert.Equal(t, []string{"5", "6", "7"}, history.commands)
+
+	// The file is rewritten when it contains too many records.
+	data, err := ioutil.ReadFile(historyFile)
+	require.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(string(data), "#"))
+
+	loaded, err := newCommandHistory(historyFile, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"5", "6", "7"}, loaded.commands)
+}
+
+func TestCommandHistory_search(t *testing.T) {
+	history := &commandHistory{maxCommands: 10}
+	history.add("box.info")
+	history.add("box.space.test:select()")
+	history.add("return 1")
+
+	entries := history.search("box")
+	require.Len(t, entries, 2)
+	assert.Equal(t, "1  box.info", entries[0].String())
+	assert.Equal(t, "2  box.space.test:select()", entries[1].String())
+
+	assert.Len(t, history.search(""), 3)
+	assert.Len(t, history.search("unknown"), 0)
+
+	entry := historyEntry{12, "for i = 1, 2 do\nend"}
+	assert.Equal(t, "12  for i = 1, 2 do\n    end", entry.String())
+}
+
+func TestConsole_searchHistoryBackward(t *testing.T) {
+	history := &commandHistory{maxCommands: 10}
+	history.add("box.info")
+	history.add("box.space.test:select()")
+	history.add("for i = 1, 2 do\n  box.cfg{}\nend")
+	history.add("return 1")
+	console := &Console{history: history}
+
+	buf := prompt.NewBuffer()
+	buf.InsertText("box", false, true)
+	search := func(expected string) {
+		console.searchHistoryBackward(buf)
+		assert.Equal(t, expected, buf.Text())
+		assert.Equal(t, "", buf.Document().TextAfterCursor())
+	}
+	// Each press finds the next match, the last match is kept at the end
+	// of the history.
+	search("for i = 1, 2 do\n  box.cfg{}\nend")
+	search("box.space.test:select()")
+	search("box.info")
+	search("box.info")
+
+	// A changed text starts a new search.
+	buf.DeleteBeforeCursor(len(buf.Text()))
+	buf.InsertText("return", false, true)
+	search("return 1")
+	buf.DeleteBeforeCursor(len(buf.Text()))
+	buf.InsertText("unknown", false, true)
+	search("unknown")
+}
+
+func TestCommandHistory_importLines(t *testing.T) {
+	dir := t.TempDir()
+	tarantoolHistory := filepath.Join(dir, TarantoolHistoryFileName)
+	data := "box.cfg{}\n\nfor i = 1, 2 do\nend\nbox.cfg{}\n"
+	require.NoError(t, ioutil.WriteFile(tarantoolHistory, []byte(data), 0644))
+
+	historyFile := filepath.Join(dir, HistoryFileName)
+	history, err := newCommandHistory(historyFile, 100)
+	require.NoError(t, err)
+	require.NoError(t, history.importLines(tarantoolHistory))
+	expected := []string{"for i = 1, 2 do", "end", "box.cfg{}"}
+	assert.Equal(t, expected, history.commands)
+
+	// The tarantool history file is not changed.
+	content, err := ioutil.ReadFile(tarantoolHistory)
+	require.NoError(t, err)
+	assert.Equal(t, data, string(content))
+
+	loaded, err := newCommandHistory(historyFile, 100)
+	require.NoError(t, err)
+	assert.Equal(t, expected, loaded.commands)
+
+	require.NoError(t, history.importLines(filepath.Join(dir, "not_exists")))
+}
+
+func TestGetHistoryFilePath(t *testing.T) {
+	path, err := getHistoryFilePath("")
+	require.NoError(t, err)
+	assert.Equal(t, HistoryFileName, filepath.Base(path))
+
+	path, err = getHistoryFilePath("/var/run/app/inst.control")
+	require.NoError(t, err)
+	assert.Equal(t, "var_run_app_inst.control", filepath.Base(path))
+	assert.Equal(t, HistoryDirName, filepath.Base(filepath.Dir(path)))
+}
@@ -0,0 +1,381 @@
+package connect
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/tarantool/tt/cli/connector"
+)
+
+const (
+	// OnConflictReplace replaces an existing tuple with the same primary key.
+	OnConflictReplace = "replace"
+	// OnConflictSkip skips a tuple if a tuple with the same primary key exists.
+	OnConflictSkip = "skip"
+	// OnConflictError stops the import if a tuple with the same primary key
+	// exists.
+	OnConflictError = "error"
+)
+
+// OnConflictPolicies is a list of supported import conflict policies.
+var OnConflictPolicies = []string{OnConflictReplace, OnConflictSkip, OnConflictError}
+
+// ImportOpts describes options of a space data import.
+type ImportOpts struct {
+	// Space is a name or an identifier of the space.
+	Space string
+	// Format is a format of the imported data.
+	Format string
+	// OnConflict is a policy for tuples with existing primary keys.
+	OnConflict string
+	// BatchSize is a number of tuples inserted in a single transaction.
+	// DefaultBatchSize is used if it is zero.
+	BatchSize int
+	// Progress is a writer for a progress bar. The progress is not printed
+	// if it is nil.
+	Progress io.Writer
+}
+
+// ImportResult describes a result of an import.
+type ImportResult struct {
+	// Inserted is a number of inserted or replaced tuples.
+	Inserted int `msgpack:"inserted"`
+	// Skipped is a number of tuples skipped due to a conflict.
+	Skipped int `msgpack:"skipped"`
+}
+
+// toInteger returns the value as an int64 or an uint64 if the value is
+// an integer.
+func toInteger(value interface{}) (int64, uint64, bool, bool) {
+	switch typed := value.(type) {
+	case int:
+		return int64(typed), 0, false, true
+	case int8:
+		return int64(typed), 0, false, true
+	case int16:
+		return int64(typed), 0, false, true
+	case int32:
+		return int64(typed), 0, false, true
+	case int64:
+		return typed, 0, false, true
+	case uint:
+		return 0, uint64(typed), true, true
+	case uint8:
+		return 0, uint64(typed), true, true
+	case uint16:
+		return 0, uint64(typed), true, true
+	case uint32:
+		return 0, uint64(typed), true, true
+	case uint64:
+		return 0, typed, true, true
+	}
+	return 0, 0, false, false
+}
+
+// convertFieldValue converts a number to the type of the field, so it is
+// encoded with a msgpack type accepted by the field. Non-negative integers
+// are converted into unsigned ones, integral floats are converted into
+// integers for integer fields.
+func convertFieldValue(value interface{}, fieldType string) (interface{}, error) {
+	if i, u, isUnsigned, ok := toInteger(value); ok {
+		switch {
+		case fieldType == "double" && isUnsigned:
+			return float64(u), nil
+		case fieldType == "double":
+			return float64(i), nil
+		case isUnsigned:
+			return u, nil
+		case i >= 0:
+			return uint64(i), nil
+		default:
+			return i, nil
+		}
+	}
+
+	var f float64
+	switch typed := value.(type) {
+	case float32:
+		f = float64(typed)
+	case float64:
+		f = typed
+	default:
+		return value, nil
+	}
+	if fieldType != "unsigned" && fieldType != "integer" {
+		return f, nil
+	}
+	if f != math.Trunc(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("%v is not an integer", f)
+	}
+	if f >= 0 {
+		return uint64(f), nil
+	}
+	return int64(f), nil
+}
+
+// isCSVJSONField checks that CSV cells of the field are decoded as JSON on
+// import: the field has no format or its type has no CSV representation.
+func isCSVJSONField(fieldFormat []FieldFormat, i int) bool {
+	if i >= len(fieldFormat) {
+		return true
+	}
+	switch fieldFormat[i].Type {
+	case "string", "varbinary", "unsigned", "integer", "double", "boolean":
+		return false
+	}
+	return true
+}
+
+// convertCSVValue converts a CSV cell into a value of the field type. An
+// empty cell is null unless the field is a non-nullable string.
+func convertCSVValue(cell string, field FieldFormat) (interface{}, error) {
+	if cell == "" {
+		if !field.IsNullable && (field.Type == "string" || field.Type == "varbinary") {
+			return "", nil
+		}
+		return nil, nil
+	}
+
+	switch field.Type {
+	case "string", "varbinary":
+		return cell, nil
+	case "unsigned":
+		return strconv.ParseUint(cell, 10, 64)
+	case "integer":
+		return convertFieldValue(decodeJSONValue(cell), field.Type)
+	case "double":
+		return strconv.ParseFloat(cell, 64)
+	case "boolean":
+		return strconv.ParseBool(cell)
+	default:
+		return decodeJSONValue(cell), nil
+	}
+}
+
+// tupleReader reads objects keyed by field names in a data format.
+type tupleReader struct {
+	format      string
+	fieldFormat map[string]FieldFormat
+	csvReader   *csv.Reader
+	header      []string
+	lineReader  *bufio.Reader
+	decoder     *msgpack.Decoder
+}
+
+// newTupleReader creates a new tuple reader.
+func newTupleReader(reader io.Reader, format string, fieldFormat []FieldFormat) *tupleReader {
+	tupleReader := &tupleReader{
+		format:      format,
+		fieldFormat: make(map[string]FieldFormat, len(fieldFormat)),
+	}
+	for _, field := range fieldFormat {
+		tupleReader.fieldFormat[field.Name] = field
+	}
+
+	switch format {
+	case DataFormatCSV:
+		tupleReader.csvReader = csv.NewReader(reader)
+	case DataFormatJSONL:
+		tupleReader.lineReader = bufio.NewReader(reader)
+	case DataFormatMsgpack:
+		tupleReader.decoder = msgpack.NewDecoder(reader)
+		tupleReader.decoder.UseLooseInterfaceDecoding(true)
+	}
+	return tupleReader
+}
+
+// read reads the next object. It returns io.EOF if there are no more objects.
+func (reader *tupleReader) read() (map[string]interface{}, error) {
+	switch reader.format {
+	case DataFormatCSV:
+		if reader.header == nil {
+			header, err := reader.csvReader.Read()
+			if err != nil {
+				return nil, err
+			}
+			reader.header = header
+		}
+		record, err := reader.csvReader.Read()
+		if err != nil {
+			return nil, err
+		}
+		object := make(map[string]interface{}, len(record))
+		for i, cell := range record {
+			field, ok := reader.fieldFormat[reader.header[i]]
+			if !ok {
+				// An empty cell is null or the padding of a shorter tuple.
+				// The field is omitted, so toTuple sets inner fields to
+				// null and the trailing ones are trimmed.
+				if cell != "" {
+					object[reader.header[i]] = decodeJSONValue(cell)
+				}
+				continue
+			}
+			if object[field.Name], err = convertCSVValue(cell, field); err != nil {
+				return nil, fmt.Errorf("invalid value of field %q: %s", field.Name, err)
+			}
+		}
+		return object, nil
+	case DataFormatJSONL:
+		for {
+			line, err := reader.lineReader.ReadString('\n')
+			if strings.TrimSpace(line) == "" {
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+			value := decodeJSONValue(line)
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("a JSON object is expected: %s", strings.TrimSpace(line))
+			}
+			return object, nil
+		}
+	case DataFormatMsgpack:
+		value, err := reader.decoder.DecodeInterfaceLoose()
+		if err != nil {
+			return nil, err
+		}
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("a msgpack map is expected: %v", value)
+		}
+		return object, nil
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", reader.format)
+	}
+}
+
+// toTuple converts an object keyed by field names into a tuple according to
+// the space format. A field without a format is named by its number.
+func toTuple(object map[string]interface{}, format []FieldFormat) ([]interface{}, error) {
+	positions := make(map[string]int, len(format))
+	for i, field := range format {
+		positions[field.Name] = i
+	}
+
+	tuple := []interface{}{}
+	for name, value := range object {
+		pos, ok := positions[name]
+		if !ok {
+			fieldNo, err := strconv.Atoi(strings.TrimPrefix(name, "field_"))
+			if !strings.HasPrefix(name, "field_") || err != nil || fieldNo < 1 {
+				return nil, fmt.Errorf("unknown field %q", name)
+			}
+			pos = fieldNo - 1
+		}
+		for len(tuple) <= pos {
+			tuple = append(tuple, nil)
+		}
+
+		fieldType := ""
+		if pos < len(format) {
+			fieldType = format[pos].Type
+		}
+		var err error
+		if tuple[pos], err = convertFieldValue(value, fieldType); err != nil {
+			return nil, fmt.Errorf("invalid value of field %q: %s", name, err)
+		}
+	}
+	return tuple, nil
+}
+
+// importBatch inserts the tuples in a single transaction.
+func importBatch(evaler connector.Evaler, spaceID uint32, tuples []interface{},
+	onConflict string) (ImportResult, error) {
+	var response []ImportResult
+	_, err := evaler.Eval(importFuncBody, []interface{}{spaceID, tuples, onConflict},
+		connector.RequestOpts{ResData: &response})
+	if err != nil {
+		return ImportResult{}, err
+	}
+	if len(response) != 1 {
+		return ImportResult{}, fmt.Errorf("unexpected response: %v", response)
+	}
+	return response[0], nil
+}
+
+// Import reads tuples from the reader in the format and inserts them into
+// the space in batches. Each batch is inserted in a separate transaction.
+func Import(evaler connector.Evaler, reader io.Reader, opts ImportOpts) (ImportResult, error) {
+	var result ImportResult
+
+	onConflict := opts.OnConflict
+	if onConflict == "" {
+		onConflict = OnConflictError
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	schema, err := GetSpaceSchema(evaler, opts.Space)
+	if err != nil {
+		return result, err
+	}
+
+	tupleReader := newTupleReader(reader, opts.Format, schema.Format)
+	bar := newCounterProgressBar(opts.Progress, schema.Name, -1)
+	defer bar.finish()
+
+	records := 0
+	batch := make([]interface{}, 0, batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		batchResult, err := importBatch(evaler, schema.ID, batch, onConflict)
+		if err != nil {
+			return fmt.Errorf("failed to import records %d-%d: %s",
+				records-len(batch)+1, records, err)
+		}
+		result.Inserted += batchResult.Inserted
+		result.Skipped += batchResult.Skipped
+		batch = batch[:0]
+		bar.update(int64(records))
+		return nil
+	}
+
+	for {
+		object, err := tupleReader.read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("failed to read record %d: %s", records+1, err)
+		}
+		records++
+
+		tuple, err := toTuple(object, schema.Format)
+		if err != nil {
+			return result, fmt.Errorf("record %d: %s", records, err)
+		}
+		batch = append(batch, tuple)
+		if len(batch) == batchSize {
+			if err := flush(); err != nil {
+				return result, err
+			}
+		}
+	}
+	return result, flush()
+}
+
+// ImportToTarget connects to the target and imports tuples into the space.
+func ImportToTarget(connectCtx ConnectCtx, connString string, reader io.Reader,
+	opts ImportOpts) (ImportResult, error) {
+	var result ImportResult
+	err := DoOnTarget(connectCtx, connString, func(conn connector.Connector) error {
+		var err error
+		result, err = Import(conn, reader, opts)
+		return err
+	})
+	return result, err
+}
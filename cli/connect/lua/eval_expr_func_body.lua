@@ -0,0 +1,34 @@
+local expr, args = ...
+
+local function pack(...)
+    local res = {}
+    for i = 1, select('#', ...) do
+        local value = select(i, ...)
+        if value == nil then
+            value = box.NULL
+        end
+        res[i] = value
+    end
+    return res
+end
+
+-- Try to evaluate the expression as an expression first, as the console does.
+local func, err = load('return ' .. expr, '=eval')
+if func == nil then
+    func, err = load(expr, '=eval')
+end
+if func == nil then
+    return {ok = false, error = tostring(err), traceback = ''}
+end
+
+local traceback = ''
+local res = pack(xpcall(func, function(err)
+    traceback = debug.traceback('', 2)
+    return err
+end, args))
+
+if not res[1] then
+    return {ok = false, error = tostring(res[2]), traceback = traceback}
+end
+table.remove(res, 1)
+return {ok = true, results = res}
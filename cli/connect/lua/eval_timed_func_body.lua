@@ -0,0 +1,4 @@
+local clock = require('clock')
+local start = clock.monotonic()
+local result = require('console').eval(...)
+return result, clock.monotonic() - start
@@ -0,0 +1,72 @@
+local op, path, arg1, arg2 = ...
+local fio = require('fio')
+
+local function open(flags, mode)
+    local fh, err = fio.open(path, flags, mode)
+    if fh == nil then
+        error(string.format('failed to open %s: %s', path, err), 0)
+    end
+    return fh
+end
+
+if op == 'stat' then
+    local stat = fio.stat(path)
+    if stat == nil then
+        return {exists = false}
+    end
+    return {exists = true, is_dir = stat:is_dir(), size = stat.size}
+elseif op == 'read' then
+    local fh = open({'O_RDONLY'})
+    local data, err = fh:pread(arg2, arg1)
+    fh:close()
+    if data == nil then
+        error(string.format('failed to read %s: %s', path, err), 0)
+    end
+    return data
+elseif op == 'write' then
+    local fh = open({'O_WRONLY', 'O_CREAT'}, tonumber('644', 8))
+    local ok, err = true, nil
+    if arg1 == 0 then
+        ok, err = fh:truncate(0)
+    end
+    if ok and #arg2 > 0 then
+        ok, err = fh:pwrite(arg2, arg1)
+    end
+    fh:close()
+    if not ok then
+        error(string.format('failed to write %s: %s', path, err), 0)
+    end
+    return true
+elseif op == 'sha256' then
+    local ctx = require('crypto').digest.sha256:new()
+    local fh = open({'O_RDONLY'})
+    local offset = 0
+    while arg1 == nil or offset < arg1 do
+        local size = 1048576
+        if arg1 ~= nil and arg1 - offset < size then
+            size = arg1 - offset
+        end
+        local data, err = fh:pread(size, offset)
+        if data == nil then
+            fh:close()
+            error(string.format('failed to read %s: %s', path, err), 0)
+        end
+        if #data == 0 then
+            break
+        end
+        ctx:update(data)
+        offset = offset + #data
+    end
+    fh:close()
+    return string.hex(ctx:result())
+elseif op == 'rename' then
+    local ok, err = fio.rename(path, arg1)
+    if not ok then
+        error(string.format('failed to rename %s: %s', path, err), 0)
+    end
+    return true
+elseif op == 'remove' then
+    fio.unlink(path)
+    return true
+end
+error(string.format('unknown file operation: %s', op), 0)
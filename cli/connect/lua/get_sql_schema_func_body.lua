@@ -0,0 +1,11 @@
+local schema = {}
+for _, space in box.space._vspace:pairs() do
+    local columns = {}
+    for _, field in ipairs(space[7] or {}) do
+        if field.name ~= nil then
+            table.insert(columns, field.name)
+        end
+    end
+    table.insert(schema, {name = space[3], columns = columns})
+end
+return schema
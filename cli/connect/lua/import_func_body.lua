@@ -0,0 +1,34 @@
+local space_id, tuples, on_conflict = ...
+local space = box.space[space_id]
+if space == nil then
+    error(string.format('space %s does not exist', space_id), 0)
+end
+
+local inserted, skipped = 0, 0
+box.begin()
+local ok, err = pcall(function()
+    for _, tuple in ipairs(tuples) do
+        if on_conflict == 'replace' then
+            space:replace(tuple)
+            inserted = inserted + 1
+        elseif on_conflict == 'skip' then
+            local ok, err = pcall(space.insert, space, tuple)
+            if ok then
+                inserted = inserted + 1
+            elseif box.error.TUPLE_FOUND ~= nil and err.code == box.error.TUPLE_FOUND then
+                skipped = skipped + 1
+            else
+                error(err)
+            end
+        else
+            space:insert(tuple)
+            inserted = inserted + 1
+        end
+    end
+end)
+if not ok then
+    box.rollback()
+    error(err)
+end
+box.commit()
+return {inserted = inserted, skipped = skipped}
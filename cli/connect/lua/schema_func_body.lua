@@ -0,0 +1,62 @@
+local op, space_name = ...
+
+if op == 'spaces' then
+    local spaces = {}
+    for _, tuple in box.space._space:pairs() do
+        local space = box.space[tuple[1]]
+        if space ~= nil then
+            local ok, len = pcall(space.len, space)
+            table.insert(spaces, {
+                id = space.id,
+                name = space.name,
+                engine = space.engine,
+                len = ok and len or 0,
+                bsize = space:bsize(),
+            })
+        end
+    end
+    return spaces
+elseif op == 'space' then
+    local space = box.space[space_name]
+    if space == nil then
+        error(string.format('space %s does not exist', space_name), 0)
+    end
+    local format = setmetatable({}, {__serialize = 'array'})
+    for _, field in ipairs(space:format()) do
+        table.insert(format, {
+            name = field.name,
+            type = field.type,
+            is_nullable = field.is_nullable == true,
+        })
+    end
+    local indexes = setmetatable({}, {__serialize = 'array'})
+    for id, index in pairs(space.index) do
+        if type(id) == 'number' then
+            local parts = {}
+            for _, part in ipairs(index.parts) do
+                table.insert(parts, {
+                    field = part.fieldno,
+                    type = part.type,
+                    is_nullable = part.is_nullable == true,
+                    collation = part.collation ~= nil and tostring(part.collation) or nil,
+                    path = part.path,
+                })
+            end
+            table.insert(indexes, {
+                id = index.id,
+                name = index.name,
+                type = index.type,
+                unique = index.unique == true,
+                parts = parts,
+            })
+        end
+    end
+    return {
+        id = space.id,
+        name = space.name,
+        engine = space.engine,
+        format = format,
+        indexes = indexes,
+    }
+end
+error(string.format('unknown schema operation: %s', op), 0)
@@ -0,0 +1,40 @@
+local op, name, opts = ...
+
+if op == 'list' then
+    local users = {}
+    for _, tuple in box.space._user:pairs() do
+        local user_name, user_type = tuple[3], tuple[4]
+        local privileges = setmetatable({}, {__serialize = 'array'})
+        for _, priv in ipairs(box.schema[user_type].info(user_name)) do
+            table.insert(privileges, {
+                privileges = priv[1],
+                object_type = priv[2],
+                object_name = priv[3],
+            })
+        end
+        table.insert(users, {
+            name = user_name,
+            type = user_type,
+            privileges = privileges,
+        })
+    end
+    return users
+elseif op == 'create' then
+    box.schema.user.create(name, {
+        password = opts.password,
+        if_not_exists = opts.if_not_exists,
+    })
+elseif op == 'passwd' then
+    box.schema.user.passwd(name, opts.password)
+elseif op == 'grant' then
+    box.schema.user.grant(name, opts.privileges, opts.object_type, opts.object_name,
+        {if_not_exists = true})
+elseif op == 'revoke' then
+    box.schema.user.revoke(name, opts.privileges, opts.object_type, opts.object_name,
+        {if_exists = true})
+elseif op == 'drop' then
+    box.schema.user.drop(name, {if_exists = opts.if_exists})
+else
+    error(string.format('unknown user operation: %s', op), 0)
+end
+return true
@@ -0,0 +1,110 @@
+package connect
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// progressBarWidth is a width of the progress bar without labels.
+const progressBarWidth = 30
+
+// progressBar prints a progress of a transfer in a single line.
+type progressBar struct {
+	// writer is a writer for the progress bar, nothing is printed if
+	// it is nil.
+	writer io.Writer
+	// name is a name of the transferred object.
+	name string
+	// total is a total amount to transfer. Only the transferred amount
+	// is printed if it is negative (unknown).
+	total int64
+	// formatAmount returns a string representation of an amount.
+	formatAmount func(int64) string
+	// percent is the last printed percent.
+	percent int
+	// current is the last printed amount if the total is unknown.
+	current int64
+}
+
+// newProgressBar creates a new progress bar for a transfer of total bytes.
+func newProgressBar(writer io.Writer, name string, total int64) *progressBar {
+	return &progressBar{
+		writer:       writer,
+		name:         name,
+		total:        total,
+		formatAmount: formatSize,
+		percent:      -1,
+		current:      -1,
+	}
+}
+
+// newCounterProgressBar creates a new progress bar for a transfer of total
+// records. The total is negative if it is unknown.
+func newCounterProgressBar(writer io.Writer, name string, total int64) *progressBar {
+	bar := newProgressBar(writer, name, total)
+	bar.formatAmount = func(amount int64) string {
+		return fmt.Sprint(amount)
+	}
+	return bar
+}
+
+// formatSize returns a human-readable size.
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%dB", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// update prints the progress if it is changed.
+func (bar *progressBar) update(current int64) {
+	if bar.writer == nil {
+		return
+	}
+
+	if bar.total < 0 {
+		if current != bar.current {
+			bar.current = current
+			// The percent is set to mark the line as started.
+			bar.percent = 0
+			fmt.Fprintf(bar.writer, "\r%s %s", bar.name, bar.formatAmount(current))
+		}
+		return
+	}
+
+	percent := 100
+	if bar.total > 0 {
+		percent = int(current * 100 / bar.total)
+	}
+	// The total could be inaccurate, e.g. a space size is changed during
+	// an export.
+	if percent > 100 {
+		percent = 100
+	}
+	if percent == bar.percent {
+		return
+	}
+	bar.percent = percent
+
+	filled := percent * progressBarWidth / 100
+	fmt.Fprintf(bar.writer, "\r%s [%s%s] %3d%% %s/%s", bar.name,
+		strings.Repeat("=", filled), strings.Repeat(" ", progressBarWidth-filled),
+		percent, bar.formatAmount(current), bar.formatAmount(bar.total))
+}
+
+// finish completes the progress line.
+func (bar *progressBar) finish() {
+	if bar.writer == nil || bar.percent == -1 {
+		return
+	}
+	fmt.Fprintln(bar.writer)
+	bar.percent = -1
+	bar.current = -1
+}
@@ -0,0 +1,207 @@
+package connect
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tarantool/tt/cli/connector"
+)
+
+const (
+	// SQLFormatTable prints SQL results as a table.
+	SQLFormatTable = "table"
+)
+
+// SQLFormats is a list of supported formats of SQL results.
+var SQLFormats = []string{SQLFormatTable, EvalFormatJSON, EvalFormatYAML}
+
+// ParseRequestArgs parses positional arguments of a request. A value is
+// decoded as JSON if possible and is used as a string otherwise.
+func ParseRequestArgs(args []string) []interface{} {
+	parsed := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		parsed = append(parsed, parseArgValue(arg))
+	}
+	return parsed
+}
+
+// CallOnTarget connects to the target and calls the function.
+func CallOnTarget(connectCtx ConnectCtx, connString string, fnName string,
+	args []interface{}) ([]interface{}, error) {
+	connOpts := getConnOpts(connString, connectCtx)
+	conn, err := connector.Connect(connOpts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to establish connection: %s", err)
+	}
+	defer conn.Close()
+
+	results, err := conn.Call(fnName, args, connector.RequestOpts{})
+	if err != nil {
+		return nil, err
+	}
+	if results == nil {
+		results = []interface{}{}
+	}
+	return results, nil
+}
+
+// ExecuteOnTarget connects to the target and executes the SQL statement.
+func ExecuteOnTarget(connectCtx ConnectCtx, connString string, sql string,
+	binds []interface{}) (connector.SQLResult, error) {
+	connOpts := getConnOpts(connString, connectCtx)
+	conn, err := connector.Connect(connOpts)
+	if err != nil {
+		return connector.SQLResult{}, fmt.Errorf("unable to establish connection: %s", err)
+	}
+	defer conn.Close()
+
+	return conn.Execute(sql, binds, connector.RequestOpts{})
+}
+
+// FormatSQLResult encodes the result of an SQL statement in the format.
+// Rows are encoded as objects keyed by column names in JSON and YAML
+// formats.
+func FormatSQLResult(result connector.SQLResult, format string) ([]byte, error) {
+	if format == SQLFormatTable {
+		return formatSQLTable(result), nil
+	}
+
+	if len(result.Columns) == 0 {
+		info := map[string]interface{}{"row_count": result.RowCount}
+		if len(result.AutoincrementIDs) > 0 {
+			info["autoincrement_ids"] = result.AutoincrementIDs
+		}
+		return FormatEvalResults(info, format)
+	}
+
+	rows := make([]interface{}, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		values, _ := row.([]interface{})
+		object := make(map[string]interface{}, len(result.Columns))
+		for i, column := range result.Columns {
+			if i < len(values) {
+				object[column] = values[i]
+			} else {
+				object[column] = nil
+			}
+		}
+		rows = append(rows, object)
+	}
+	return FormatEvalResults(rows, format)
+}
+
+// formatSQLTable formats the result of an SQL statement as a table.
+func formatSQLTable(result connector.SQLResult) []byte {
+	var buf bytes.Buffer
+	if len(result.Columns) == 0 {
+		fmt.Fprintf(&buf, "Rows affected: %d\n", result.RowCount)
+		if len(result.AutoincrementIDs) > 0 {
+			ids := make([]string, 0, len(result.AutoincrementIDs))
+			for _, id := range result.AutoincrementIDs {
+				ids = append(ids, fmt.Sprint(id))
+			}
+			fmt.Fprintf(&buf, "Autoincrement IDs: %s\n", strings.Join(ids, ", "))
+		}
+		return buf.Bytes()
+	}
+
+	rows := make([][]string, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		values, _ := row.([]interface{})
+		cells := make([]string, len(result.Columns))
+		for i := range cells {
+			if i >= len(values) || values[i] == nil {
+				cells[i] = "NULL"
+			} else {
+				cells[i] = fmt.Sprint(values[i])
+			}
+		}
+		rows = append(rows, cells)
+	}
+
+	buf.Write(formatTable(result.Columns, rows))
+	fmt.Fprintf(&buf, "(%d rows)\n", len(result.Rows))
+	return buf.Bytes()
+}
+
+// formatTable formats rows as a table with the header. Cells of a column
+// are left-aligned.
+func formatTable(header []string, rows [][]string) []byte {
+	var buf bytes.Buffer
+	table := append([][]string{header, make([]string, len(header))}, rows...)
+
+	widths := make([]int, len(header))
+	for _, cells := range table {
+		for i, cell := range cells {
+			if width := utf8.RuneCountInString(cell); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+	for i, width := range widths {
+		table[1][i] = strings.Repeat("-", width)
+	}
+
+	for _, cells := range table {
+		line := make([]string, len(cells))
+		for i, cell := range cells {
+			line[i] = cell + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+		}
+		buf.WriteString(strings.TrimRight(strings.Join(line, "  "), " ") + "\n")
+	}
+	return buf.Bytes()
+}
+
+// FormatQueryPlan formats the result of an EXPLAIN QUERY PLAN statement as
+// a tree. Steps of subqueries are grouped by a select identifier.
+func FormatQueryPlan(result connector.SQLResult) []byte {
+	selectIDColumn, detailColumn := -1, len(result.Columns)-1
+	for i, column := range result.Columns {
+		switch strings.ToLower(column) {
+		case "selectid":
+			selectIDColumn = i
+		case "detail":
+			detailColumn = i
+		}
+	}
+
+	var selectIDs []string
+	steps := map[string][]string{}
+	for _, row := range result.Rows {
+		values, _ := row.([]interface{})
+		if detailColumn < 0 || detailColumn >= len(values) {
+			continue
+		}
+		selectID := "0"
+		if selectIDColumn >= 0 && selectIDColumn < len(values) {
+			selectID = fmt.Sprint(values[selectIDColumn])
+		}
+		if _, ok := steps[selectID]; !ok {
+			selectIDs = append(selectIDs, selectID)
+		}
+		steps[selectID] = append(steps[selectID], fmt.Sprint(values[detailColumn]))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("QUERY PLAN\n")
+	writeNode := func(indent string, last bool, text string) string {
+		branch, childIndent := "|--", "|  "
+		if last {
+			branch, childIndent = "`--", "   "
+		}
+		buf.WriteString(indent + branch + text + "\n")
+		return indent + childIndent
+	}
+	for i, selectID := range selectIDs {
+		indent := ""
+		if len(selectIDs) > 1 {
+			indent = writeNode("", i == len(selectIDs)-1, "SELECT "+selectID)
+		}
+		for j, step := range steps[selectID] {
+			writeNode(indent, j == len(steps[selectID])-1, step)
+		}
+	}
+	return buf.Bytes()
+}
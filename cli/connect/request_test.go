@@ -0,0 +1,100 @@
+package connect_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/tarantool/tt/cli/connect"
+	"github.com/tarantool/tt/cli/connector"
+)
+
+func TestParseRequestArgs(t *testing.T) {
+	assert.Equal(t, []interface{}{}, ParseRequestArgs([]string{}))
+	assert.Equal(t, []interface{}{
+		float64(1),
+		"str",
+		[]interface{}{true, nil},
+		map[string]interface{}{"key": "value"},
+		"",
+	}, ParseRequestArgs([]string{"1", "str", "[true, null]", `{"key": "value"}`, ""}))
+}
+
+func TestFormatSQLResult(t *testing.T) {
+	result := connector.SQLResult{
+		Columns: []string{"id", "name"},
+		Rows: []interface{}{
+			[]interface{}{uint64(1), "one"},
+			[]interface{}{uint64(100), nil},
+		},
+	}
+
+	cases := []struct {
+		format   string
+		expected string
+	}{
+		{SQLFormatTable, "id   name\n---  ----\n1    one\n100  NULL\n(2 rows)\n"},
+		{EvalFormatJSON, "[\n  {\n    \"id\": 1,\n    \"name\": \"one\"\n  },\n" +
+			"  {\n    \"id\": 100,\n    \"name\": null\n  }\n]"},
+		{EvalFormatYAML, "---\n- id: 1\n  name: one\n- id: 100\n  name: null\n...\n"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.format, func(t *testing.T) {
+			output, err := FormatSQLResult(result, tc.format)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(output))
+		})
+	}
+}
+
+func TestFormatSQLResult_rowCount(t *testing.T) {
+	result := connector.SQLResult{
+		Columns:          []string{},
+		RowCount:         2,
+		AutoincrementIDs: []uint64{5, 6},
+	}
+
+	cases := []struct {
+		format   string
+		expected string
+	}{
+		{SQLFormatTable, "Rows affected: 2\nAutoincrement IDs: 5, 6\n"},
+		{EvalFormatJSON, "{\n  \"autoincrement_ids\": [\n    5,\n    6\n  ],\n" +
+			"  \"row_count\": 2\n}"},
+		{EvalFormatYAML, "---\nautoincrement_ids:\n- 5\n- 6\nrow_count: 2\n...\n"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.format, func(t *testing.T) {
+			output, err := FormatSQLResult(result, tc.format)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(output))
+		})
+	}
+}
+
+func TestFormatQueryPlan(t *testing.T) {
+	result := connector.SQLResult{
+		Columns: []string{"selectid", "order", "from", "detail"},
+		Rows: []interface{}{
+			[]interface{}{uint64(0), uint64(0), uint64(0), "SCAN TABLE T (~1048576 rows)"},
+			[]interface{}{uint64(0), uint64(0), uint64(0),
+				"EXECUTE SCALAR SUBQUERY 1"},
+			[]interface{}{uint64(1), uint64(0), uint64(0),
+				"SEARCH TABLE S USING PRIMARY KEY (ID=?) (~1 row)"},
+		},
+	}
+	assert.Equal(t, "QUERY PLAN\n"+
+		"|--SELECT 0\n"+
+		"|  |--SCAN TABLE T (~1048576 rows)\n"+
+		"|  `--EXECUTE SCALAR SUBQUERY 1\n"+
+		"`--SELECT 1\n"+
+		"   `--SEARCH TABLE S USING PRIMARY KEY (ID=?) (~1 row)\n",
+		string(FormatQueryPlan(result)))
+
+	result.Rows = result.Rows[:1]
+	assert.Equal(t, "QUERY PLAN\n`--SCAN TABLE T (~1048576 rows)\n",
+		string(FormatQueryPlan(result)))
+}
@@ -0,0 +1,271 @@
+package connect
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/tarantool/tt/cli/connector"
+)
+
+// systemSpaceIDMax is a maximum identifier of a system space.
+const systemSpaceIDMax = 511
+
+// SchemaFormats is a list of supported formats of schema information.
+var SchemaFormats = []string{SQLFormatTable, EvalFormatJSON, EvalFormatYAML}
+
+// SpaceInfo describes a space and its size.
+type SpaceInfo struct {
+	// ID is an identifier of the space.
+	ID uint32 `msgpack:"id" json:"id" yaml:"id"`
+	// Name is a name of the space.
+	Name string `msgpack:"name" json:"name" yaml:"name"`
+	// Engine is a storage engine of the space.
+	Engine string `msgpack:"engine" json:"engine" yaml:"engine"`
+	// Len is a number of tuples in the space.
+	Len uint64 `msgpack:"len" json:"len" yaml:"len"`
+	// BSize is a number of bytes of the space data.
+	BSize uint64 `msgpack:"bsize" json:"bsize" yaml:"bsize"`
+}
+
+// IsSystem returns true if the space is a system one.
+func (space SpaceInfo) IsSystem() bool {
+	return space.ID <= systemSpaceIDMax
+}
+
+// FieldFormat describes a field of a space format.
+type FieldFormat struct {
+	// Name is a name of the field.
+	Name string `msgpack:"name" json:"name" yaml:"name"`
+	// Type is a type of the field.
+	Type string `msgpack:"type" json:"type" yaml:"type"`
+	// IsNullable is true if the field could contain null.
+	IsNullable bool `msgpack:"is_nullable" json:"is_nullable" yaml:"is_nullable"`
+}
+
+// IndexPart describes a part of an index key.
+type IndexPart struct {
+	// Field is a number of the indexed field starting from 1.
+	Field uint32 `msgpack:"field" json:"field" yaml:"field"`
+	// Type is a type of the part.
+	Type string `msgpack:"type" json:"type" yaml:"type"`
+	// IsNullable is true if the part could contain null.
+	IsNullable bool `msgpack:"is_nullable" json:"is_nullable" yaml:"is_nullable"`
+	// Collation is a collation of the part.
+	Collation string `msgpack:"collation" json:"collation,omitempty" yaml:"collation,omitempty"`
+	// Path is a JSON path of the part inside the field.
+	Path string `msgpack:"path" json:"path,omitempty" yaml:"path,omitempty"`
+}
+
+// IndexInfo describes an index of a space.
+type IndexInfo struct {
+	// ID is an identifier of the index.
+	ID uint32 `msgpack:"id" json:"id" yaml:"id"`
+	// Name is a name of the index.
+	Name string `msgpack:"name" json:"name" yaml:"name"`
+	// Type is a type of the index: TREE, HASH, BITSET or RTREE.
+	Type string `msgpack:"type" json:"type" yaml:"type"`
+	// Unique is true if the index is unique.
+	Unique bool `msgpack:"unique" json:"unique" yaml:"unique"`
+	// Parts are parts of the index key.
+	Parts []IndexPart `msgpack:"parts" json:"parts" yaml:"parts"`
+}
+
+// SpaceSchema describes a schema of a space.
+type SpaceSchema struct {
+	// ID is an identifier of the space.
+	ID uint32 `msgpack:"id" json:"id" yaml:"id"`
+	// Name is a name of the space.
+	Name string `msgpack:"name" json:"name" yaml:"name"`
+	// Engine is a storage engine of the space.
+	Engine string `msgpack:"engine" json:"engine" yaml:"engine"`
+	// Format is a format of the space.
+	Format []FieldFormat `msgpack:"format" json:"format" yaml:"format"`
+	// Indexes are indexes of the space ordered by identifiers.
+	Indexes []IndexInfo `msgpack:"indexes" json:"indexes" yaml:"indexes"`
+}
+
+// SpaceDump is a schema of a space in a schema dump. It does not contain
+// the space identifier: identifiers are assigned per instance.
+type SpaceDump struct {
+	// Engine is a storage engine of the space.
+	Engine string `json:"engine" yaml:"engine"`
+	// Format is a format of the space.
+	Format []FieldFormat `json:"format" yaml:"format"`
+	// Indexes are indexes of the space ordered by identifiers.
+	Indexes []IndexInfo `json:"indexes" yaml:"indexes"`
+}
+
+// SchemaDump is a schema of all spaces of an instance.
+type SchemaDump struct {
+	// Spaces are schemas of the spaces keyed by names.
+	Spaces map[string]SpaceDump `json:"spaces" yaml:"spaces"`
+}
+
+// spaceArg returns a space identifier if the space is specified as a number
+// and the space name otherwise.
+func spaceArg(space string) interface{} {
+	if id, err := strconv.ParseUint(space, 10, 32); err == nil {
+		return id
+	}
+	return space
+}
+
+// GetSpaces returns spaces of the instance ordered by identifiers. System
+// spaces are skipped if showSystem is false.
+func GetSpaces(evaler connector.Evaler, showSystem bool) ([]SpaceInfo, error) {
+	var response [][]SpaceInfo
+	_, err := evaler.Eval(schemaFuncBody, []interface{}{"spaces"},
+		connector.RequestOpts{ResData: &response})
+	if err != nil {
+		return nil, err
+	}
+	if len(response) != 1 {
+		return nil, fmt.Errorf("unexpected response: %v", response)
+	}
+
+	spaces := []SpaceInfo{}
+	for _, space := range response[0] {
+		if showSystem || !space.IsSystem() {
+			spaces = append(spaces, space)
+		}
+	}
+	sort.Slice(spaces, func(i, j int) bool {
+		return spaces[i].ID < spaces[j].ID
+	})
+	return spaces, nil
+}
+
+// GetSpaceSchema returns a schema of the space specified by a name or
+// an identifier.
+func GetSpaceSchema(evaler connector.Evaler, space string) (SpaceSchema, error) {
+	var response []SpaceSchema
+	_, err := evaler.Eval(schemaFuncBody, []interface{}{"space", spaceArg(space)},
+		connector.RequestOpts{ResData: &response})
+	if err != nil {
+		return SpaceSchema{}, err
+	}
+	if len(response) != 1 {
+		return SpaceSchema{}, fmt.Errorf("unexpected response: %v", response)
+	}
+
+	schema := response[0]
+	sort.Slice(schema.Indexes, func(i, j int) bool {
+		return schema.Indexes[i].ID < schema.Indexes[j].ID
+	})
+	return schema, nil
+}
+
+// DumpSchema returns schemas of all spaces of the instance keyed by names,
+// so dumps of different instances could be compared. System spaces are
+// skipped if showSystem is false.
+func DumpSchema(evaler connector.Evaler, showSystem bool) (SchemaDump, error) {
+	spaces, err := GetSpaces(evaler, showSystem)
+	if err != nil {
+		return SchemaDump{}, err
+	}
+
+	dump := SchemaDump{Spaces: map[string]SpaceDump{}}
+	for _, space := range spaces {
+		schema, err := GetSpaceSchema(evaler, strconv.FormatUint(uint64(space.ID), 10))
+		if err != nil {
+			return SchemaDump{}, fmt.Errorf("failed to get schema of space %s: %s",
+				space.Name, err)
+		}
+		dump.Spaces[schema.Name] = SpaceDump{
+			Engine:  schema.Engine,
+			Format:  schema.Format,
+			Indexes: schema.Indexes,
+		}
+	}
+	return dump, nil
+}
+
+// formatSchemaValue encodes the value in JSON or YAML format.
+func formatSchemaValue(value interface{}, format string) ([]byte, error) {
+	switch format {
+	case EvalFormatJSON:
+		return json.MarshalIndent(value, "", "  ")
+	case EvalFormatYAML:
+		return yaml.Marshal(value)
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// FormatSpaces encodes the spaces list in the format.
+func FormatSpaces(spaces []SpaceInfo, format string) ([]byte, error) {
+	if format != SQLFormatTable {
+		return formatSchemaValue(spaces, format)
+	}
+
+	rows := make([][]string, 0, len(spaces))
+	for _, space := range spaces {
+		rows = append(rows, []string{space.Name, fmt.Sprint(space.ID), space.Engine,
+			fmt.Sprint(space.Len), fmt.Sprint(space.BSize)})
+	}
+	return formatTable([]string{"NAME", "ID", "ENGINE", "LEN", "BSIZE"}, rows), nil
+}
+
+// formatIndexPart returns a string representation of an index part. A field
+// number is replaced by a field name if the space format contains it.
+func formatIndexPart(part IndexPart, format []FieldFormat) string {
+	field := fmt.Sprint(part.Field)
+	if part.Field >= 1 && int(part.Field) <= len(format) {
+		field = format[part.Field-1].Name
+	}
+	if part.Path != "" {
+		field += part.Path
+	}
+
+	description := []string{field, part.Type}
+	if part.IsNullable {
+		description = append(description, "nullable")
+	}
+	if part.Collation != "" {
+		description = append(description, "collation="+part.Collation)
+	}
+	return strings.Join(description, " ")
+}
+
+// FormatIndexes encodes indexes of the space in the format.
+func FormatIndexes(schema SpaceSchema, format string) ([]byte, error) {
+	if format != SQLFormatTable {
+		return formatSchemaValue(schema.Indexes, format)
+	}
+
+	rows := make([][]string, 0, len(schema.Indexes))
+	for _, index := range schema.Indexes {
+		parts := make([]string, 0, len(index.Parts))
+		for _, part := range index.Parts {
+			parts = append(parts, formatIndexPart(part, schema.Format))
+		}
+		rows = append(rows, []string{index.Name, fmt.Sprint(index.ID), index.Type,
+			strconv.FormatBool(index.Unique), strings.Join(parts, ", ")})
+	}
+	return formatTable([]string{"NAME", "ID", "TYPE", "UNIQUE", "PARTS"}, rows), nil
+}
+
+// FormatSpaceFormat encodes a format of the space in the format.
+func FormatSpaceFormat(schema SpaceSchema, format string) ([]byte, error) {
+	if format != SQLFormatTable {
+		return formatSchemaValue(schema.Format, format)
+	}
+
+	rows := make([][]string, 0, len(schema.Format))
+	for i, field := range schema.Format {
+		rows = append(rows, []string{fmt.Sprint(i + 1), field.Name, field.Type,
+			strconv.FormatBool(field.IsNullable)})
+	}
+	return formatTable([]string{"#", "NAME", "TYPE", "NULLABLE"}, rows), nil
+}
+
+// FormatSchemaDump encodes the schema dump into YAML. Spaces are ordered by
+// names.
+func FormatSchemaDump(dump SchemaDump) ([]byte, error) {
+	return yaml.Marshal(dump)
+}
@@ -0,0 +1,181 @@
+package connect_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+
+	. "github.com/tarantool/tt/cli/connect"
+	"github.com/tarantool/tt/cli/connector"
+)
+
+// schemaEvalerMock returns schema information of predefined spaces.
+type schemaEvalerMock struct {
+	spaces  []interface{}
+	schemas map[interface{}]interface{}
+}
+
+func (evaler *schemaEvalerMock) Eval(expr string, args []interface{},
+	opts connector.RequestOpts) ([]interface{}, error) {
+	var result interface{}
+	switch args[0] {
+	case "spaces":
+		result = evaler.spaces
+	case "space":
+		schema, ok := evaler.schemas[args[1]]
+		if !ok {
+			return nil, fmt.Errorf("space %v does not exist", args[1])
+		}
+		result = schema
+	}
+
+	encoded, err := msgpack.Marshal([]interface{}{result})
+	if err != nil {
+		return nil, err
+	}
+	return nil, msgpack.Unmarshal(encoded, opts.ResData)
+}
+
+func newSchemaEvalerMock() *schemaEvalerMock {
+	testSchema := map[string]interface{}{
+		"id": 512, "name": "test", "engine": "memtx",
+		"format": []interface{}{
+			map[string]interface{}{"name": "id", "type": "unsigned"},
+			map[string]interface{}{"name": "name", "type": "string", "is_nullable": true},
+		},
+		"indexes": []interface{}{
+			map[string]interface{}{"id": 1, "name": "name", "type": "TREE", "unique": false,
+				"parts": []interface{}{map[string]interface{}{
+					"field": 2, "type": "string", "is_nullable": true,
+					"collation": "unicode_ci"}}},
+			map[string]interface{}{"id": 0, "name": "pk", "type": "TREE", "unique": true,
+				"parts": []interface{}{map[string]interface{}{"field": 1, "type": "unsigned"}}},
+		},
+	}
+	accountsSchema := map[string]interface{}{
+		"id": 513, "name": "accounts", "engine": "vinyl",
+		"format":  []interface{}{},
+		"indexes": []interface{}{},
+	}
+
+	return &schemaEvalerMock{
+		spaces: []interface{}{
+			map[string]interface{}{"id": 513, "name": "accounts", "engine": "vinyl",
+				"len": 0, "bsize": 0},
+			map[string]interface{}{"id": 512, "name": "test", "engine": "memtx",
+				"len": 2, "bsize": 36},
+			map[string]interface{}{"id": 280, "name": "_space", "engine": "memtx",
+				"len": 20, "bsize": 4096},
+		},
+		schemas: map[interface{}]interface{}{
+			"test":      testSchema,
+			uint64(512): testSchema,
+			uint64(513): accountsSchema,
+		},
+	}
+}
+
+func TestGetSpaces(t *testing.T) {
+	evaler := newSchemaEvalerMock()
+
+	spaces, err := GetSpaces(evaler, false)
+	require.NoError(t, err)
+	assert.Equal(t, []SpaceInfo{
+		{ID: 512, Name: "test", Engine: "memtx", Len: 2, BSize: 36},
+		{ID: 513, Name: "accounts", Engine: "vinyl"},
+	}, spaces)
+
+	spaces, err = GetSpaces(evaler, true)
+	require.NoError(t, err)
+	require.Len(t, spaces, 3)
+	assert.Equal(t, "_space", spaces[0].Name)
+
+	output, err := FormatSpaces(spaces[1:], SQLFormatTable)
+	require.NoError(t, err)
+	assert.Equal(t, "NAME      ID   ENGINE  LEN  BSIZE\n"+
+		"--------  ---  ------  ---  -----\n"+
+		"test      512  memtx   2    36\n"+
+		"accounts  513  vinyl   0    0\n", string(output))
+}
+
+func TestGetSpaceSchema(t *testing.T) {
+	evaler := newSchemaEvalerMock()
+
+	schema, err := GetSpaceSchema(evaler, "test")
+	require.NoError(t, err)
+	require.Len(t, schema.Indexes, 2)
+	assert.Equal(t, "pk", schema.Indexes[0].Name)
+	assert.Equal(t, "name", schema.Indexes[1].Name)
+
+	byID, err := GetSpaceSchema(evaler, "512")
+	require.NoError(t, err)
+	assert.Equal(t, schema, byID)
+
+	_, err = GetSpaceSchema(evaler, "unknown")
+	assert.EqualError(t, err, "space unknown does not exist")
+
+	output, err := FormatIndexes(schema, SQLFormatTable)
+	require.NoError(t, err)
+	assert.Equal(t, "NAME  ID  TYPE  UNIQUE  PARTS\n"+
+		"----  --  ----  ------  -----------------------------------------\n"+
+		"pk    0   TREE  true    id unsigned\n"+
+		"name  1   TREE  false   name string nullable collation=unicode_ci\n",
+		string(output))
+
+	output, err = FormatSpaceFormat(schema, SQLFormatTable)
+	require.NoError(t, err)
+	assert.Equal(t, "#  NAME  TYPE      NULLABLE\n"+
+		"-  ----  --------  --------\n"+
+		"1  id    unsigned  false\n"+
+		"2  name  string    true\n", string(output))
+
+	output, err = FormatSpaceFormat(schema, EvalFormatJSON)
+	require.NoError(t, err)
+	assert.Equal(t, "[\n  {\n    \"name\": \"id\",\n    \"type\": \"unsigned\",\n"+
+		"    \"is_nullable\": false\n  },\n  {\n    \"name\": \"name\",\n"+
+		"    \"type\": \"string\",\n    \"is_nullable\": true\n  }\n]", string(output))
+}
+
+func TestDumpSchema(t *testing.T) {
+	dump, err := DumpSchema(newSchemaEvalerMock(), false)
+	require.NoError(t, err)
+
+	output, err := FormatSchemaDump(dump)
+	require.NoError(t, err)
+	assert.Equal(t, `spaces:
+  accounts:
+    engine: vinyl
+    format: []
+    indexes: []
+  test:
+    engine: memtx
+    format:
+    - name: id
+      type: unsigned
+      is_nullable: false
+    - name: name
+      type: string
+      is_nullable: true
+    indexes:
+    - id: 0
+      name: pk
+      type: TREE
+      unique: true
+      parts:
+      - field: 1
+        type: unsigned
+        is_nullable: false
+    - id: 1
+      name: name
+      type: TREE
+      unique: false
+      parts:
+      - field: 2
+        type: string
+        is_nullable: true
+        collation: unicode_ci
+`, string(output))
+}
@@ -0,0 +1,213 @@
+package connect
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/adam-hanna/arrayOperations"
+	"github.com/apex/log"
+	"github.com/c-bata/go-prompt"
+
+	"github.com/tarantool/tt/cli/connector"
+)
+
+// sqlTable describes a table (space) for the SQL auto-completion.
+type sqlTable struct {
+	// Name is a name of the table.
+	Name string `msgpack:"name"`
+	// Columns is a list of the table column names from the space format.
+	Columns []string `msgpack:"columns"`
+}
+
+// sqlSchema describes tables known to the SQL auto-completion.
+type sqlSchema struct {
+	tables []sqlTable
+}
+
+// sqlKeywords is a list of SQL keywords to suggest.
+var sqlKeywords = []string{
+	"ALL", "ALTER", "AND", "AS", "ASC", "AUTOINCREMENT", "BEGIN", "BETWEEN", "BY",
+	"CASE", "CAST", "CHECK", "COLLATE", "COMMIT", "CONSTRAINT", "COUNT", "CREATE",
+	"CROSS", "DEFAULT", "DELETE", "DESC", "DISTINCT", "DROP", "ELSE", "END",
+	"ENGINE", "EXCEPT", "EXISTS", "EXPLAIN", "FALSE", "FOREIGN", "FROM", "FULL",
+	"GROUP", "HAVING", "IF", "IN", "INDEX", "INNER", "INSERT", "INTERSECT", "INTO",
+	"IS", "JOIN", "KEY", "LEFT", "LIKE", "LIMIT", "NOT", "NULL", "OFFSET", "ON",
+	"OR", "ORDER", "OUTER", "PLAN", "PRAGMA", "PRIMARY", "QUERY", "REFERENCES",
+	"RELEASE", "RENAME", "REPLACE", "RIGHT", "ROLLBACK", "SAVEPOINT", "SELECT",
+	"SET", "START", "TABLE", "THEN", "TO", "TRANSACTION", "TRIGGER", "TRUE",
+	"TRUNCATE", "UNION", "UNIQUE", "UPDATE", "USING", "VALUES", "VIEW", "WHEN",
+	"WHERE", "WITH",
+}
+
+// sqlTableKeywords is a set of keywords followed by a table name.
+var sqlTableKeywords = map[string]bool{
+	"FROM":     true,
+	"JOIN":     true,
+	"INTO":     true,
+	"UPDATE":   true,
+	"TABLE":    true,
+	"EXISTS":   true,
+	"TRUNCATE": true,
+}
+
+// sqlSchemaReadTimeout is a timeout for fetching the schema.
+const sqlSchemaReadTimeout = 3 * time.Second
+
+// loadSQLSchema fetches spaces and their formats from the instance.
+func loadSQLSchema(evaler connector.Evaler) (*sqlSchema, error) {
+	var tables [][]sqlTable
+	opts := connector.RequestOpts{
+		ReadTimeout: sqlSchemaReadTimeout,
+		ResData:     &tables,
+	}
+	if _, err := evaler.Eval(getSQLSchemaFuncBody, []interface{}{}, opts); err != nil {
+		return nil, err
+	}
+
+	schema := &sqlSchema{}
+	if len(tables) > 0 {
+		schema.tables = tables[0]
+	}
+	return schema, nil
+}
+
+// getSQLSchema returns the cached schema. The schema is fetched once per
+// session or after the \refresh command.
+func getSQLSchema(console *Console) *sqlSchema {
+	if console.sqlSchema != nil {
+		return console.sqlSchema
+	}
+
+	schema, err := loadSQLSchema(console.conn)
+	if err != nil {
+		log.Debugf("Failed to load SQL schema: %s", err)
+		// Do not retry on every key press, use only keywords.
+		schema = &sqlSchema{}
+	}
+	console.sqlSchema = schema
+	return schema
+}
+
+// sqlTokens splits a SQL statement into upper-cased words.
+func sqlTokens(text string) []string {
+	return strings.FieldsFunc(strings.ToUpper(text), func(r rune) bool {
+		return strings.ContainsRune(tarantoolWordSeparators, r)
+	})
+}
+
+// tableNames returns names of the tables. System tables are skipped if
+// showSystem is false.
+func (schema *sqlSchema) tableNames(showSystem bool) []string {
+	names := []string{}
+	for _, table := range schema.tables {
+		if showSystem || !strings.HasPrefix(table.Name, "_") {
+			names = append(names, table.Name)
+		}
+	}
+	return names
+}
+
+// usedTables returns tables mentioned in the statement.
+func (schema *sqlSchema) usedTables(tokens []string) []sqlTable {
+	used := []sqlTable{}
+	for i, token := range tokens {
+		if i == 0 || !sqlTableKeywords[tokens[i-1]] {
+			continue
+		}
+		for _, table := range schema.tables {
+			if strings.ToUpper(table.Name) == token {
+				used = append(used, table)
+			}
+		}
+	}
+	return used
+}
+
+// appendMatched appends candidates with the case-insensitive prefix to the
+// list.
+func appendMatched(list []string, prefix string, candidates ...string) []string {
+	upperPrefix := strings.ToUpper(prefix)
+	for _, candidate := range candidates {
+		if strings.HasPrefix(strings.ToUpper(candidate), upperPrefix) &&
+			len(candidate) > len(prefix) {
+			list = append(list, candidate)
+		}
+	}
+	return list
+}
+
+// getSQLSuggestions returns suggestions for the last word of the text
+// before the cursor according to the statement context: table names after
+// FROM/JOIN/INTO/UPDATE/TABLE, column names of the mentioned tables in other
+// places and SQL keywords.
+func getSQLSuggestions(schema *sqlSchema, text string, lastWordStart int) []string {
+	lastWord := text[lastWordStart:]
+	tokens := sqlTokens(text[:lastWordStart])
+	suggestions := []string{}
+
+	// A column of a specified table: "table.col".
+	if dot := strings.LastIndex(lastWord, "."); dot >= 0 {
+		tableName := strings.ToUpper(lastWord[:dot])
+		for _, table := range schema.tables {
+			if strings.ToUpper(table.Name) != tableName {
+				continue
+			}
+			for _, column := range table.Columns {
+				suggestions = appendMatched(suggestions, lastWord,
+					lastWord[:dot+1]+column)
+			}
+		}
+		return suggestions
+	}
+
+	if lastWord == "" {
+		return suggestions
+	}
+
+	showSystem := strings.HasPrefix(lastWord, "_")
+	switch {
+	case len(tokens) == 0:
+		// The beginning of a statement.
+		suggestions = appendMatched(suggestions, lastWord, sqlKeywords...)
+	case sqlTableKeywords[tokens[len(tokens)-1]]:
+		suggestions = appendMatched(suggestions, lastWord,
+			schema.tableNames(showSystem)...)
+	default:
+		columnsTables := schema.usedTables(tokens)
+		if len(columnsTables) == 0 {
+			// The table is not specified yet, e.g. "SELECT a".
+			columnsTables = schema.tables
+		}
+		for _, table := range columnsTables {
+			if !showSystem && strings.HasPrefix(table.Name, "_") {
+				continue
+			}
+			suggestions = appendMatched(suggestions, lastWord, table.Columns...)
+		}
+		suggestions = appendMatched(suggestions, lastWord,
+			schema.tableNames(showSystem)...)
+		suggestions = appendMatched(suggestions, lastWord, sqlKeywords...)
+	}
+
+	suggestions = arrayOperations.DifferenceString(suggestions)
+	sort.Strings(suggestions)
+	return suggestions
+}
+
+// getSQLCompletion returns prompt suggestions for the SQL language.
+func getSQLCompletion(console *Console, in prompt.Document,
+	lastWordStart int) []prompt.Suggest {
+	texts := getSQLSuggestions(getSQLSchema(console), in.TextBeforeCursor(), lastWordStart)
+	if len(texts) == 0 {
+		return nil
+	}
+
+	suggestions := make([]prompt.Suggest, len(texts))
+	for i, text := range texts {
+		suggestions[i] = prompt.Suggest{
+			Text: text,
+		}
+	}
+	return suggestions
+}
@@ -0,0 +1,53 @@
+package connect
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testSQLSchema = &sqlSchema{
+	tables: []sqlTable{
+		{Name: "_space", Columns: []string{"id", "owner", "name"}},
+		{Name: "USERS", Columns: []string{"ID", "NAME", "EMAIL"}},
+		{Name: "ORDERS", Columns: []string{"ID", "USER_ID", "AMOUNT"}},
+		{Name: "events", Columns: []string{}},
+	},
+}
+
+func TestGetSQLSuggestions(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected []string
+	}{
+		{"", []string{}},
+		{"sel", []string{"SELECT"}},
+		{"SELECT * FROM ", []string{}},
+		{"SELECT * FROM u", []string{"USERS"}},
+		{"select * from o", []string{"ORDERS"}},
+		{"SELECT * FROM e", []string{"events"}},
+		{"SELECT * FROM _s", []string{"_space"}},
+		{"INSERT INTO U", []string{"USERS"}},
+		{"SELECT * FROM USERS WHERE E", []string{"ELSE", "EMAIL", "END", "ENGINE",
+			"EXCEPT", "EXISTS", "EXPLAIN", "events"}},
+		{"SELECT * FROM ORDERS WHERE US", []string{"USERS", "USER_ID", "USING"}},
+		{"SELECT AM", []string{"AMOUNT"}},
+		{"SELECT USERS.", []string{"USERS.ID", "USERS.NAME", "USERS.EMAIL"}},
+		{"SELECT users.n", []string{"users.NAME"}},
+		{"SELECT UNKNOWN.", []string{}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.text, func(t *testing.T) {
+			lastWordStart := strings.LastIndexAny(tc.text, tarantoolWordSeparators) + 1
+			assert.Equal(t, tc.expected,
+				getSQLSuggestions(testSQLSchema, tc.text, lastWordStart))
+		})
+	}
+}
+
+func TestGetSQLSuggestions_emptySchema(t *testing.T) {
+	assert.Equal(t, []string{"SELECT"}, getSQLSuggestions(&sqlSchema{}, "SELE", 0))
+	assert.Equal(t, []string{}, getSQLSuggestions(&sqlSchema{}, "SELECT * FROM T", 14))
+}
@@ -0,0 +1,141 @@
+package connect
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sqlReadOnlyKeywords is a set of first keywords of SQL statements allowed in
+// the read-only mode. The statements do not modify data or the schema.
+var sqlReadOnlyKeywords = map[string]bool{
+	"SELECT":    true,
+	"VALUES":    true,
+	"WITH":      true,
+	"EXPLAIN":   true,
+	"PRAGMA":    true,
+	"SET":       true,
+	"START":     true,
+	"BEGIN":     true,
+	"COMMIT":    true,
+	"ROLLBACK":  true,
+	"SAVEPOINT": true,
+	"RELEASE":   true,
+}
+
+// sqlWriteKeywords is a set of keywords that modify data in a statement
+// started with WITH.
+var sqlWriteKeywords = map[string]bool{
+	"INSERT":  true,
+	"UPDATE":  true,
+	"DELETE":  true,
+	"REPLACE": true,
+}
+
+// skipSQLLiteral returns a position of the last byte of a string literal,
+// a quoted identifier or a comment started at the position. It returns false
+// if there is no literal or comment at the position.
+func skipSQLLiteral(text string, pos int) (int, bool) {
+	switch {
+	case text[pos] == '\'' || text[pos] == '"':
+		end := strings.IndexByte(text[pos+1:], text[pos])
+		if end == -1 {
+			return len(text) - 1, true
+		}
+		return pos + end + 1, true
+	case strings.HasPrefix(text[pos:], "--"):
+		end := strings.IndexByte(text[pos:], '\n')
+		if end == -1 {
+			return len(text) - 1, true
+		}
+		return pos + end, true
+	case strings.HasPrefix(text[pos:], "/*"):
+		end := strings.Index(text[pos+2:], "*/")
+		if end == -1 {
+			return len(text) - 1, true
+		}
+		return pos + end + 3, true
+	}
+	return pos, false
+}
+
+// isSQLWordByte returns true if the byte could be a part of an SQL keyword
+// or an unquoted identifier.
+func isSQLWordByte(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') ||
+		('0' <= c && c <= '9')
+}
+
+// splitSQLStatements splits the text into statements separated with
+// semicolons. Semicolons inside string literals, quoted identifiers and
+// comments are skipped. Empty statements are omitted.
+func splitSQLStatements(text string) []string {
+	statements := []string{}
+	start := 0
+	for i := 0; i <= len(text); i++ {
+		if i < len(text) {
+			var skipped bool
+			if i, skipped = skipSQLLiteral(text, i); skipped || text[i] != ';' {
+				continue
+			}
+		}
+		if statement := strings.TrimSpace(text[start:i]); statement != "" {
+			statements = append(statements, statement)
+		}
+		start = i + 1
+	}
+	return statements
+}
+
+// sqlWords returns upper-cased words of the statement outside string
+// literals, quoted identifiers, comments and parentheses.
+func sqlWords(statement string) []string {
+	words := []string{}
+	depth := 0
+	for i := 0; i < len(statement); i++ {
+		var skipped bool
+		if i, skipped = skipSQLLiteral(statement, i); skipped {
+			continue
+		}
+
+		switch c := statement[i]; {
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case isSQLWordByte(c):
+			start := i
+			for i+1 < len(statement) && isSQLWordByte(statement[i+1]) {
+				i++
+			}
+			if depth == 0 {
+				words = append(words, strings.ToUpper(statement[start:i+1]))
+			}
+		}
+	}
+	return words
+}
+
+// checkReadOnlySQL returns an error if the text contains an SQL statement
+// that could modify data or the schema.
+func checkReadOnlySQL(text string) error {
+	for _, statement := range splitSQLStatements(text) {
+		words := sqlWords(statement)
+		if len(words) == 0 {
+			continue
+		}
+
+		keyword := words[0]
+		if !sqlReadOnlyKeywords[keyword] {
+			return fmt.Errorf("%s statements are not allowed in read-only mode", keyword)
+		}
+		if keyword == "WITH" {
+			for _, word := range words[1:] {
+				if sqlWriteKeywords[word] {
+					return fmt.Errorf("%s statements are not allowed in read-only mode",
+						word)
+				}
+			}
+		}
+	}
+	return nil
+}
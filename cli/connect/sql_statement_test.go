@@ -0,0 +1,70 @@
+package connect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitSQLStatements(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected []string
+	}{
+		{"", []string{}},
+		{" ; ;", []string{}},
+		{"SELECT 1", []string{"SELECT 1"}},
+		{"SELECT 1; SELECT 2;", []string{"SELECT 1", "SELECT 2"}},
+		{"SELECT ';'; SELECT \"a;b\"", []string{"SELECT ';'", "SELECT \"a;b\""}},
+		{"SELECT 1 -- a; b\n; SELECT 2", []string{"SELECT 1 -- a; b", "SELECT 2"}},
+		{"SELECT /* ; */ 1; SELECT 'unterminated;",
+			[]string{"SELECT /* ; */ 1", "SELECT 'unterminated;"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.text, func(t *testing.T) {
+			assert.Equal(t, tc.expected, splitSQLStatements(tc.text))
+		})
+	}
+}
+
+func TestCheckReadOnlySQL(t *testing.T) {
+	allowed := []string{
+		"",
+		"select * from t",
+		"  /* comment */ SELECT * FROM t WHERE name = 'DELETE'",
+		"-- INSERT\nVALUES (1, 2)",
+		"EXPLAIN QUERY PLAN INSERT INTO t VALUES (1)",
+		"WITH x AS (SELECT 1) SELECT * FROM x",
+		"PRAGMA table_info(t)",
+		"SET SESSION \"sql_seq_scan\" = true",
+		"START TRANSACTION; SELECT 1; COMMIT",
+		"SELECT \"update\" FROM t",
+	}
+	for _, text := range allowed {
+		t.Run(text, func(t *testing.T) {
+			assert.NoError(t, checkReadOnlySQL(text))
+		})
+	}
+
+	rejected := []struct {
+		text     string
+		expected string
+	}{
+		{"insert into t values (1)", "INSERT"},
+		{"SELECT 1; DELETE FROM t", "DELETE"},
+		{"UPDATE t SET a = 1", "UPDATE"},
+		{"REPLACE INTO t VALUES (1)", "REPLACE"},
+		{"CREATE TABLE t (id INT PRIMARY KEY)", "CREATE"},
+		{"DROP TABLE t", "DROP"},
+		{"ALTER TABLE t RENAME TO s", "ALTER"},
+		{"TRUNCATE TABLE t", "TRUNCATE"},
+		{"WITH x AS (SELECT 1) INSERT INTO t SELECT * FROM x", "INSERT"},
+	}
+	for _, tc := range rejected {
+		t.Run(tc.text, func(t *testing.T) {
+			assert.EqualError(t, checkReadOnlySQL(tc.text),
+				tc.expected+" statements are not allowed in read-only mode")
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package connect
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/tarantool/tt/cli/connector"
+)
+
+// UserFormats is a list of supported formats of a users list.
+var UserFormats = []string{SQLFormatTable, EvalFormatJSON}
+
+// UserPrivilege describes privileges granted on an object.
+type UserPrivilege struct {
+	// Privileges is a comma-separated list of privileges, e.g. "read,write".
+	Privileges string `msgpack:"privileges" json:"privileges"`
+	// ObjectType is a type of the object, e.g. "space" or "universe".
+	ObjectType string `msgpack:"object_type" json:"object_type"`
+	// ObjectName is a name of the object. It is empty for "universe".
+	ObjectName string `msgpack:"object_name" json:"object_name"`
+}
+
+// UserInfo describes a user or a role.
+type UserInfo struct {
+	// Name is a name of the user.
+	Name string `msgpack:"name" json:"name"`
+	// Type is "user" or "role".
+	Type string `msgpack:"type" json:"type"`
+	// Privileges are privileges granted to the user.
+	Privileges []UserPrivilege `msgpack:"privileges" json:"privileges"`
+}
+
+// GrantOpts describes privileges to grant or revoke.
+type GrantOpts struct {
+	// Privileges is a comma-separated list of privileges, e.g. "read,write".
+	Privileges string
+	// ObjectType is a type of the object, e.g. "space", "universe" or "role".
+	ObjectType string
+	// ObjectName is a name of the object. It is ignored for "universe".
+	ObjectName string
+}
+
+// luaOpts returns options for the user management function.
+func (opts GrantOpts) luaOpts() map[string]interface{} {
+	luaOpts := map[string]interface{}{
+		"privileges":  opts.Privileges,
+		"object_type": opts.ObjectType,
+	}
+	if opts.ObjectName != "" {
+		luaOpts["object_name"] = opts.ObjectName
+	}
+	return luaOpts
+}
+
+// userOp performs the user management operation on the instance.
+func userOp(evaler connector.Evaler, resData interface{}, op, name string,
+	opts map[string]interface{}) error {
+	if opts == nil {
+		opts = map[string]interface{}{}
+	}
+	_, err := evaler.Eval(userFuncBody, []interface{}{op, name, opts},
+		connector.RequestOpts{ResData: resData})
+	return err
+}
+
+// ListUsers returns all users and roles with their privileges.
+func ListUsers(evaler connector.Evaler) ([]UserInfo, error) {
+	var response [][]UserInfo
+	if err := userOp(evaler, &response, "list", "", nil); err != nil {
+		return nil, err
+	}
+	if len(response) != 1 {
+		return nil, fmt.Errorf("unexpected response: %v", response)
+	}
+	return response[0], nil
+}
+
+// CreateUser creates a user. The user is created without a password if the
+// password is empty.
+func CreateUser(evaler connector.Evaler, name, password string, ifNotExists bool) error {
+	opts := map[string]interface{}{"if_not_exists": ifNotExists}
+	if password != "" {
+		opts["password"] = password
+	}
+	return userOp(evaler, nil, "create", name, opts)
+}
+
+// ChangeUserPassword sets a new password for the user.
+func ChangeUserPassword(evaler connector.Evaler, name, password string) error {
+	return userOp(evaler, nil, "passwd", name, map[string]interface{}{"password": password})
+}
+
+// GrantUser grants privileges to the user. Privileges that are already
+// granted are skipped.
+func GrantUser(evaler connector.Evaler, name string, opts GrantOpts) error {
+	return userOp(evaler, nil, "grant", name, opts.luaOpts())
+}
+
+// RevokeUser revokes privileges from the user. Privileges that are not
+// granted are skipped.
+func RevokeUser(evaler connector.Evaler, name string, opts GrantOpts) error {
+	return userOp(evaler, nil, "revoke", name, opts.luaOpts())
+}
+
+// DropUser drops the user.
+func DropUser(evaler connector.Evaler, name string, ifExists bool) error {
+	return userOp(evaler, nil, "drop", name, map[string]interface{}{"if_exists": ifExists})
+}
+
+// DoOnTarget connects to the target and calls the function with
+// the connection.
+func DoOnTarget(connectCtx ConnectCtx, connString string,
+	do func(conn connector.Connector) error) error {
+	connOpts := getConnOpts(connString, connectCtx)
+	conn, err := connector.Connect(connOpts)
+	if err != nil {
+		return fmt.Errorf("unable to establish connection: %s", err)
+	}
+	defer conn.Close()
+
+	return do(conn)
+}
+
+// FormatUsers encodes the users list in the format. The table contains
+// a row per an object with granted privileges.
+func FormatUsers(users []UserInfo, format string) ([]byte, error) {
+	switch format {
+	case SQLFormatTable:
+		rows := [][]string{}
+		for _, user := range users {
+			if len(user.Privileges) == 0 {
+				rows = append(rows, []string{user.Name, user.Type, "", ""})
+			}
+			for _, priv := range user.Privileges {
+				object := priv.ObjectType
+				if priv.ObjectName != "" {
+					object += " " + priv.ObjectName
+				}
+				rows = append(rows, []string{user.Name, user.Type, priv.Privileges, object})
+			}
+		}
+		return formatTable([]string{"NAME", "TYPE", "PRIVILEGES", "OBJECT"}, rows), nil
+	case EvalFormatJSON:
+		encoded := make([]UserInfo, 0, len(users))
+		for _, user := range users {
+			if user.Privileges == nil {
+				user.Privileges = []UserPrivilege{}
+			}
+			encoded = append(encoded, user)
+		}
+		return json.MarshalIndent(encoded, "", "  ")
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
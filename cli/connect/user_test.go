@@ -0,0 +1,80 @@
+package connect_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/tarantool/tt/cli/connect"
+)
+
+func TestUserOperations(t *testing.T) {
+	cases := []struct {
+		name     string
+		do       func(evaler *evalerMock) error
+		expected []interface{}
+	}{
+		{"create", func(evaler *evalerMock) error {
+			return CreateUser(evaler, "alice", "secret", false)
+		}, []interface{}{"create", "alice", map[string]interface{}{
+			"password": "secret", "if_not_exists": false}}},
+		{"create without password", func(evaler *evalerMock) error {
+			return CreateUser(evaler, "alice", "", true)
+		}, []interface{}{"create", "alice", map[string]interface{}{"if_not_exists": true}}},
+		{"passwd", func(evaler *evalerMock) error {
+			return ChangeUserPassword(evaler, "alice", "secret")
+		}, []interface{}{"passwd", "alice", map[string]interface{}{"password": "secret"}}},
+		{"grant", func(evaler *evalerMock) error {
+			return GrantUser(evaler, "alice", GrantOpts{"read,write", "space", "test"})
+		}, []interface{}{"grant", "alice", map[string]interface{}{
+			"privileges": "read,write", "object_type": "space", "object_name": "test"}}},
+		{"revoke universe", func(evaler *evalerMock) error {
+			return RevokeUser(evaler, "alice", GrantOpts{Privileges: "execute",
+				ObjectType: "universe"})
+		}, []interface{}{"revoke", "alice", map[string]interface{}{
+			"privileges": "execute", "object_type": "universe"}}},
+		{"drop", func(evaler *evalerMock) error {
+			return DropUser(evaler, "alice", true)
+		}, []interface{}{"drop", "alice", map[string]interface{}{"if_exists": true}}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			evaler := &evalerMock{}
+			require.NoError(t, tc.do(evaler))
+			assert.NotEmpty(t, evaler.expr)
+			assert.Equal(t, tc.expected, evaler.args)
+		})
+	}
+}
+
+func TestFormatUsers(t *testing.T) {
+	users := []UserInfo{
+		{Name: "admin", Type: "user", Privileges: []UserPrivilege{
+			{Privileges: "read,write,execute", ObjectType: "universe"},
+		}},
+		{Name: "alice", Type: "user", Privileges: []UserPrivilege{
+			{Privileges: "execute", ObjectType: "role", ObjectName: "public"},
+			{Privileges: "read", ObjectType: "space", ObjectName: "test"},
+		}},
+		{Name: "empty", Type: "role"},
+	}
+
+	output, err := FormatUsers(users, SQLFormatTable)
+	require.NoError(t, err)
+	assert.Equal(t, "NAME   TYPE  PRIVILEGES          OBJECT\n"+
+		"-----  ----  ------------------  -----------\n"+
+		"admin  user  read,write,execute  universe\n"+
+		"alice  user  execute             role public\n"+
+		"alice  user  read                space test\n"+
+		"empty  role\n", string(output))
+
+	output, err = FormatUsers(users[2:], EvalFormatJSON)
+	require.NoError(t, err)
+	assert.Equal(t, "[\n  {\n    \"name\": \"empty\",\n    \"type\": \"role\",\n"+
+		"    \"privileges\": []\n  }\n]", string(output))
+
+	_, err = FormatUsers(users, "yaml")
+	assert.EqualError(t, err, "unsupported format: yaml")
+}
@@ -0,0 +1,77 @@
+package connect
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/tarantool/tt/cli/connector"
+)
+
+// watchTimestampFormat is a format of a watch event timestamp.
+const watchTimestampFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// WatchFormats is a list of supported formats of watch events.
+var WatchFormats = []string{EvalFormatYAML, EvalFormatJSON}
+
+// FormatWatchEvent encodes a value of the watched key in the format. A YAML
+// document is prefixed with a comment with the timestamp and the key, a JSON
+// event is encoded as a single line object.
+func FormatWatchEvent(key string, value interface{}, timestamp time.Time,
+	format string) ([]byte, error) {
+	switch format {
+	case EvalFormatYAML:
+		encoded, err := FormatEvalResults(value, EvalFormatYAML)
+		if err != nil {
+			return nil, err
+		}
+		header := fmt.Sprintf("# %s %s\n", timestamp.Format(watchTimestampFormat), key)
+		return append([]byte(header), encoded...), nil
+	case EvalFormatJSON:
+		return json.Marshal(map[string]interface{}{
+			"timestamp": timestamp.Format(watchTimestampFormat),
+			"key":       key,
+			"value":     normalizeValue(value),
+		})
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// watch watches the key via the connector and prints each value into
+// the writer until the stop channel is closed.
+func watch(watcher connector.Watcher, key, format string, writer io.Writer,
+	stop <-chan struct{}) error {
+	var formatErr error
+	err := watcher.Watch(key, func(value interface{}) {
+		output, err := FormatWatchEvent(key, value, time.Now(), format)
+		if err != nil {
+			formatErr = err
+			return
+		}
+		fmt.Fprintln(writer, strings.TrimRight(string(output), "\n"))
+	}, stop)
+	if err != nil {
+		return err
+	}
+	if formatErr != nil {
+		return fmt.Errorf("failed to encode a value: %s", formatErr)
+	}
+	return nil
+}
+
+// WatchOnTarget connects to the target and prints each value of the key
+// until the stop channel is closed.
+func WatchOnTarget(connectCtx ConnectCtx, connString, key, format string,
+	writer io.Writer, stop <-chan struct{}) error {
+	connOpts := getConnOpts(connString, connectCtx)
+	conn, err := connector.Connect(connOpts)
+	if err != nil {
+		return fmt.Errorf("unable to establish connection: %s", err)
+	}
+	defer conn.Close()
+
+	return watch(conn, key, format, writer, stop)
+}
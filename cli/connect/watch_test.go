@@ -0,0 +1,36 @@
+package connect_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/tarantool/tt/cli/connect"
+)
+
+func TestFormatWatchEvent(t *testing.T) {
+	timestamp := time.Date(2022, 10, 1, 12, 30, 15, 123000000, time.UTC)
+	value := map[interface{}]interface{}{"is_ro": false}
+
+	cases := []struct {
+		format   string
+		expected string
+	}{
+		{EvalFormatYAML, "# 2022-10-01T12:30:15.123Z box.status\n---\nis_ro: false\n...\n"},
+		{EvalFormatJSON, `{"key":"box.status","timestamp":"2022-10-01T12:30:15.123Z",` +
+			`"value":{"is_ro":false}}`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.format, func(t *testing.T) {
+			output, err := FormatWatchEvent("box.status", value, timestamp, tc.format)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(output))
+		})
+	}
+
+	_, err := FormatWatchEvent("box.status", value, timestamp, "xml")
+	assert.EqualError(t, err, "unsupported format: xml")
+}
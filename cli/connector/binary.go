@@ -3,7 +3,9 @@ package connector
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math"
+	"sync"
 	"time"
 
 	"github.com/tarantool/go-tarantool"
@@ -13,6 +15,13 @@ import (
 // and receives data via IPROTO.
 type BinaryConnector struct {
 	conn tarantool.Connector
+	// connOpts are options of the connection. They are used to open
+	// a separate connection for watchers. It is nil if the connector is
+	// created from an existing connection.
+	connOpts *ConnectOpts
+	// ids caches identifiers of spaces and indexes resolved by names.
+	ids      map[string][2]uint32
+	idsMutex sync.Mutex
 }
 
 // NewBinaryConnector creates a new BinaryConnector object. The object will
@@ -23,23 +32,217 @@ func NewBinaryConnector(conn tarantool.Connector) *BinaryConnector {
 	}
 }
 
+// iterators maps iterator names to the binary protocol iterator types.
+var iterators = map[string]uint32{
+	"":                 tarantool.IterEq,
+	"EQ":               tarantool.IterEq,
+	"REQ":              tarantool.IterReq,
+	"ALL":              tarantool.IterAll,
+	"LT":               tarantool.IterLt,
+	"LE":               tarantool.IterLe,
+	"GE":               tarantool.IterGe,
+	"GT":               tarantool.IterGt,
+	"BITS_ALL_SET":     tarantool.IterBitsAllSet,
+	"BITS_ANY_SET":     tarantool.IterBitsAnySet,
+	"BITS_ALL_NOT_SET": tarantool.IterBitsAllNotSet,
+}
+
+// requestContext returns a context with the request read timeout. The
+// context is nil if the timeout is not set.
+func requestContext(opts RequestOpts) (context.Context, context.CancelFunc) {
+	if opts.ReadTimeout == 0 {
+		return nil, func() {}
+	}
+	return context.WithTimeout(context.Background(), opts.ReadTimeout)
+}
+
 // Eval sends an eval request.
 func (conn *BinaryConnector) Eval(expr string, args []interface{},
 	opts RequestOpts) ([]interface{}, error) {
-	// Create a request.
-	evalReq := tarantool.NewEvalRequest(expr).Args(args)
-	if opts.ReadTimeout != 0 {
-		ctx := context.Background()
-		ctx, cancel := context.WithTimeout(ctx, opts.ReadTimeout)
-		defer cancel()
+	ctx, cancel := requestContext(opts)
+	defer cancel()
+
+	evalReq := tarantool.NewEvalRequest(expr).Args(args).Context(ctx)
+	return conn.doData(evalReq, opts)
+}
+
+// Call sends a call request.
+func (conn *BinaryConnector) Call(fnName string, args []interface{},
+	opts RequestOpts) ([]interface{}, error) {
+	ctx, cancel := requestContext(opts)
+	defer cancel()
+
+	if args == nil {
+		args = []interface{}{}
+	}
+	callReq := tarantool.NewCall17Request(fnName).Args(args).Context(ctx)
+	return conn.doData(callReq, opts)
+}
 
-		evalReq = evalReq.Context(ctx)
+// Select sends a select request. The space and the index names are resolved
+// into identifiers with an eval request once per a connection.
+func (conn *BinaryConnector) Select(space, index interface{}, key []interface{},
+	selectOpts SelectOpts, opts RequestOpts) ([]interface{}, error) {
+	iterator, ok := iterators[selectOpts.Iterator]
+	if !ok {
+		return nil, fmt.Errorf("unknown iterator: %s", selectOpts.Iterator)
 	}
 
+	spaceID, indexID, err := conn.resolveSpaceIndex(space, index, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := requestContext(opts)
+	defer cancel()
+
+	limit := selectOpts.Limit
+	if limit == 0 {
+		limit = math.MaxUint32
+	}
+	if key == nil {
+		key = []interface{}{}
+	}
+	selectReq := tarantool.NewSelectRequest(spaceID).
+		Index(indexID).
+		Iterator(iterator).
+		Offset(selectOpts.Offset).
+		Limit(limit).
+		Key(key).
+		Context(ctx)
+	return conn.doData(selectReq, opts)
+}
+
+// Execute sends an execute request.
+func (conn *BinaryConnector) Execute(sql string, binds []interface{},
+	opts RequestOpts) (SQLResult, error) {
+	ctx, cancel := requestContext(opts)
+	defer cancel()
+
+	if binds == nil {
+		binds = []interface{}{}
+	}
+	executeReq := tarantool.NewExecuteRequest(sql).Args(binds).Context(ctx)
+	response, err := conn.do(executeReq, RequestOpts{ReadTimeout: opts.ReadTimeout})
+	if err != nil {
+		return SQLResult{}, err
+	}
+
+	result := SQLResult{
+		Columns:          []string{},
+		Rows:             response.Data,
+		RowCount:         response.SQLInfo.AffectedCount,
+		AutoincrementIDs: response.SQLInfo.InfoAutoincrementIds,
+	}
+	for _, column := range response.MetaData {
+		result.Columns = append(result.Columns, column.FieldName)
+	}
+	if result.Rows == nil {
+		result.Rows = []interface{}{}
+	}
+	return result, nil
+}
+
+// Ping sends a ping request.
+func (conn *BinaryConnector) Ping(opts RequestOpts) error {
+	ctx, cancel := requestContext(opts)
+	defer cancel()
+
+	_, err := conn.do(tarantool.NewPingRequest().Context(ctx), RequestOpts{})
+	return err
+}
+
+// Watch watches the key with an eval request that pushes the values. The
+// request is sent via a separate connection that is closed on stop. If
+// the connector is created from an existing connection, the connection
+// itself is used and closed.
+func (conn *BinaryConnector) Watch(key string, callback WatchCallback,
+	stop <-chan struct{}) error {
+	if conn.connOpts == nil {
+		return watchEval(conn, conn.Close, key, callback, stop)
+	}
+
+	watchConn, err := Connect(*conn.connOpts)
+	if err != nil {
+		return fmt.Errorf("failed to watch: %w", err)
+	}
+	defer watchConn.Close()
+	return watchEval(watchConn, watchConn.Close, key, callback, stop)
+}
+
+// resolveSpaceIndex returns identifiers of the space and the index. The
+// connection is created without a schema, so names are resolved with an
+// eval request and cached.
+func (conn *BinaryConnector) resolveSpaceIndex(space, index interface{},
+	opts RequestOpts) (uint32, uint32, error) {
+	spaceID, spaceIsID := toUint32(space)
+	indexID, indexIsID := toUint32(index)
+	if spaceIsID && indexIsID {
+		return spaceID, indexID, nil
+	}
+
+	cacheKey := fmt.Sprintf("%v/%v", space, index)
+	conn.idsMutex.Lock()
+	ids, ok := conn.ids[cacheKey]
+	conn.idsMutex.Unlock()
+	if ok {
+		return ids[0], ids[1], nil
+	}
+
+	resolved := []uint32{}
+	if _, err := conn.Eval(resolveSpaceIndexFuncBody, []interface{}{space, index},
+		RequestOpts{ReadTimeout: opts.ReadTimeout, ResData: &resolved}); err != nil {
+		return 0, 0, err
+	}
+	if len(resolved) != 2 {
+		return 0, 0, fmt.Errorf("unexpected response: %v", resolved)
+	}
+
+	conn.idsMutex.Lock()
+	if conn.ids == nil {
+		conn.ids = map[string][2]uint32{}
+	}
+	conn.ids[cacheKey] = [2]uint32{resolved[0], resolved[1]}
+	conn.idsMutex.Unlock()
+
+	return resolved[0], resolved[1], nil
+}
+
+// toUint32 converts an integer identifier to uint32.
+func toUint32(value interface{}) (uint32, bool) {
+	switch typed := value.(type) {
+	case int:
+		return uint32(typed), typed >= 0
+	case int64:
+		return uint32(typed), typed >= 0
+	case uint:
+		return uint32(typed), true
+	case uint32:
+		return typed, true
+	case uint64:
+		return uint32(typed), true
+	default:
+		return 0, false
+	}
+}
+
+// doData executes the request and returns the response data.
+func (conn *BinaryConnector) doData(req tarantool.Request,
+	opts RequestOpts) ([]interface{}, error) {
+	response, err := conn.do(req, opts)
+	if err != nil || response == nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+// do executes the request. The response is nil if opts.ResData is set.
+func (conn *BinaryConnector) do(req tarantool.Request,
+	opts RequestOpts) (*tarantool.Response, error) {
 	// Execute the request.
 	var err error
 	var response *tarantool.Response
-	future := conn.conn.Do(evalReq)
+	future := conn.conn.Do(req)
 	if opts.PushCallback != nil {
 		var timeout time.Duration
 		if opts.ReadTimeout != 0 {
@@ -59,7 +262,7 @@ func (conn *BinaryConnector) Eval(expr string, args []interface{},
 		}
 	}
 
-	// Get responseonse.
+	// Get response.
 	if opts.ResData != nil {
 		err = future.GetTyped(opts.ResData)
 	} else {
@@ -70,11 +273,7 @@ func (conn *BinaryConnector) Eval(expr string, args []interface{},
 		return nil, replaceContextDone(err)
 	}
 
-	if response == nil {
-		return nil, nil
-	}
-
-	return response.Data, nil
+	return response, nil
 }
 
 // Close closes the tarantool.Connector created from.
@@ -1,20 +1,32 @@
 package connector
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"path/filepath"
 	"runtime"
+	"syscall"
 	"time"
 
+	"github.com/apex/log"
 	"github.com/tarantool/go-tarantool"
 )
 
 const (
-	greetingOperationTimeout = 3 * time.Second
-	maxSocketPathLinux       = 108
-	maxSocketPathMac         = 106
+	// DefaultConnectTimeout is a default timeout for establishing
+	// a connection.
+	DefaultConnectTimeout = 3 * time.Second
+	// DefaultRetryBackoff is a default delay before the first connection
+	// retry.
+	DefaultRetryBackoff = 500 * time.Millisecond
+	// MaxRetryBackoff is a maximum delay between connection retries.
+	MaxRetryBackoff = 5 * time.Second
+
+	maxSocketPathLinux = 108
+	maxSocketPathMac   = 106
 )
 
 // RequestOpts describes the parameters of a request to be executed.
@@ -33,15 +45,96 @@ type Evaler interface {
 	Eval(expr string, args []interface{}, opts RequestOpts) ([]interface{}, error)
 }
 
+// Caller is an interface that wraps Call method.
+type Caller interface {
+	// Call calls a stored function with the arguments.
+	Call(fnName string, args []interface{}, opts RequestOpts) ([]interface{}, error)
+}
+
+// SelectOpts describes options of a select request.
+type SelectOpts struct {
+	// Iterator is an iterator type: "EQ", "REQ", "ALL", "LT", "LE", "GE",
+	// "GT", "BITS_ALL_SET", "BITS_ANY_SET" or "BITS_ALL_NOT_SET". "EQ" is
+	// used if it is empty.
+	Iterator string
+	// Offset is a number of tuples to skip.
+	Offset uint32
+	// Limit is a maximum number of tuples to return. The number is not
+	// limited if it is zero.
+	Limit uint32
+}
+
+// Selecter is an interface that wraps Select method.
+type Selecter interface {
+	// Select selects tuples from the space by the index key. The space and
+	// the index could be specified by a name or an identifier.
+	Select(space, index interface{}, key []interface{}, selectOpts SelectOpts,
+		opts RequestOpts) ([]interface{}, error)
+}
+
+// SQLResult describes a result of an SQL statement execution.
+type SQLResult struct {
+	// Columns is a list of names of the result columns.
+	Columns []string
+	// Rows is a list of the result rows.
+	Rows []interface{}
+	// RowCount is a number of rows changed by the statement.
+	RowCount uint64
+	// AutoincrementIDs is a list of autogenerated identifiers.
+	AutoincrementIDs []uint64
+}
+
+// SQLExecuter is an interface that wraps Execute method.
+type SQLExecuter interface {
+	// Execute executes the SQL statement with the bind values.
+	Execute(sql string, binds []interface{}, opts RequestOpts) (SQLResult, error)
+}
+
+// Pinger is an interface that wraps Ping method.
+type Pinger interface {
+	// Ping checks the connection.
+	Ping(opts RequestOpts) error
+}
+
 // Connector is an interface that wraps all method required for a
 // connector.
 type Connector interface {
 	Evaler
+	Caller
+	Selecter
+	SQLExecuter
+	Pinger
+	Watcher
 	Close() error
 }
 
-// Connect connects to the tarantool instance according to options.
+// Connect connects to the tarantool instance according to options. A failed
+// connection attempt is retried opts.RetryCount times with an exponential
+// backoff.
 func Connect(opts ConnectOpts) (Connector, error) {
+	backoff := opts.RetryBackoff
+	if backoff == 0 {
+		backoff = DefaultRetryBackoff
+	}
+
+	for retry := 0; ; retry++ {
+		conn, err := connect(opts)
+		if err == nil || retry >= opts.RetryCount {
+			return conn, err
+		}
+
+		log.Debugf("Connection attempt failed: %s. Retry in %s", err, backoff)
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > MaxRetryBackoff {
+			backoff = MaxRetryBackoff
+		}
+	}
+}
+
+// connect makes a single attempt to connect to the tarantool instance.
+func connect(opts ConnectOpts) (Connector, error) {
+	origOpts := opts
+
 	// It became common that address is longer than 108 symbols(sun_path limit).
 	// To reduce length of address we use relative path
 	// with chdir into a directory of socket.
@@ -65,18 +158,24 @@ func Connect(opts ConnectOpts) (Connector, error) {
 		}
 		defer os.Chdir(workDir)
 	}
+	connectTimeout := opts.ConnectTimeout
+	if connectTimeout == 0 {
+		connectTimeout = DefaultConnectTimeout
+	}
+
 	// Connect to specified address.
-	greetingConn, err := net.Dial(opts.Network, opts.Address)
+	greetingConn, err := net.DialTimeout(opts.Network, opts.Address, connectTimeout)
 	if err != nil {
 		return nil, fmt.Errorf("failed to dial: %s", err)
 	}
 
 	// Set a deadline for the greeting.
-	greetingConn.SetReadDeadline(time.Now().Add(greetingOperationTimeout))
+	greetingConn.SetReadDeadline(time.Now().Add(connectTimeout))
 
 	// Detect protocol.
 	protocol, err := GetProtocol(greetingConn)
 	if err != nil {
+		greetingConn.Close()
 		return nil, fmt.Errorf("failed to get protocol: %s", err)
 	}
 
@@ -87,21 +186,82 @@ func Connect(opts ConnectOpts) (Connector, error) {
 	// Initialize connection.
 	switch protocol {
 	case TextProtocol:
-		return NewTextConnector(greetingConn), nil
+		conn := NewTextConnector(greetingConn)
+		conn.requestTimeout = opts.RequestTimeout
+		return conn, nil
 	case BinaryProtocol:
 		greetingConn.Close()
 
 		addr := fmt.Sprintf("%s://%s", opts.Network, opts.Address)
-		conn, err := tarantool.Connect(addr, tarantool.Opts{
+		// The connection is pinged with the request timeout period if
+		// the timeout is set.
+		conn, err := connectBinary(addr, tarantool.Opts{
 			User:       opts.Username,
 			Pass:       opts.Password,
+			Timeout:    opts.RequestTimeout,
 			SkipSchema: true, // We don't need a schema for eval requests.
-		})
+		}, opts.ConnectTimeout)
 		if err != nil {
 			return nil, err
 		}
-		return NewBinaryConnector(conn), nil
+		binaryConn := NewBinaryConnector(conn)
+		binaryConn.connOpts = &origOpts
+		return binaryConn, nil
 	default:
+		greetingConn.Close()
 		return nil, fmt.Errorf("unsupported protocol: %s", protocol)
 	}
 }
+
+// connectBinary connects to the binary protocol address. go-tarantool does
+// not limit the time of the greeting and the authentication, so the
+// connection is dropped if it is not established in the connect timeout.
+func connectBinary(addr string, opts tarantool.Opts,
+	connectTimeout time.Duration) (*tarantool.Connection, error) {
+	if connectTimeout == 0 {
+		connectTimeout = DefaultConnectTimeout
+	}
+
+	type result struct {
+		conn *tarantool.Connection
+		err  error
+	}
+	results := make(chan result, 1)
+	go func() {
+		conn, err := tarantool.Connect(addr, opts)
+		results <- result{conn, err}
+	}()
+
+	timer := time.NewTimer(connectTimeout)
+	defer timer.Stop()
+	select {
+	case res := <-results:
+		return res.conn, res.err
+	case <-timer.C:
+		// Close the connection if it is established later.
+		go func() {
+			if res := <-results; res.err == nil {
+				res.conn.Close()
+			}
+		}()
+		return nil, fmt.Errorf("failed to connect: timed out after %s", connectTimeout)
+	}
+}
+
+// IsConnectionError returns true if the error means that the connection is
+// lost or can't be used anymore.
+func IsConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var clientErr tarantool.ClientError
+	if errors.As(err, &clientErr) {
+		return clientErr.Code == tarantool.ErrConnectionNotReady ||
+			clientErr.Code == tarantool.ErrConnectionClosed
+	}
+
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.ErrClosedPipe) || errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET)
+}
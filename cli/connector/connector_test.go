@@ -0,0 +1,119 @@
+package connector_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/go-tarantool"
+
+	. "github.com/tarantool/tt/cli/connector"
+)
+
+func TestConnect_retry(t *testing.T) {
+	opts := ConnectOpts{
+		Network:      "unix",
+		Address:      t.TempDir() + "/not_exist.sock",
+		RetryCount:   2,
+		RetryBackoff: 20 * time.Millisecond,
+	}
+
+	start := time.Now()
+	_, err := Connect(opts)
+	assert.ErrorContains(t, err, "failed to dial")
+	// Two retries with 20ms and 40ms delays.
+	assert.GreaterOrEqual(t, time.Since(start), 60*time.Millisecond)
+}
+
+func TestConnect_greetingTimeout(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	// The server accepts connections but does not send a greeting.
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	start := time.Now()
+	_, err = Connect(ConnectOpts{
+		Network:        "tcp",
+		Address:        listener.Addr().String(),
+		ConnectTimeout: 100 * time.Millisecond,
+	})
+	assert.ErrorContains(t, err, "failed to get protocol")
+	assert.Less(t, time.Since(start), DefaultConnectTimeout)
+}
+
+// binaryGreeting is a greeting of a binary protocol connection.
+var binaryGreeting = fmt.Sprintf("%-63s\n%-63s\n", "Tarantool 2.10.0 (Binary) "+
+	"8fb65242-878b-4dc6-a07b-444ae3decc18", "bWVzc2FnZQ==")
+
+// startBinaryServer starts a server that sends the binary protocol greeting
+// only to the first connection, the next connections hang.
+func startBinaryServer(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for i := 0; ; i++ {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			if i == 0 {
+				conn.Write([]byte(binaryGreeting))
+			}
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestConnect_binaryTimeout(t *testing.T) {
+	start := time.Now()
+	_, err := Connect(ConnectOpts{
+		Network:        "tcp",
+		Address:        startBinaryServer(t),
+		ConnectTimeout: 200 * time.Millisecond,
+	})
+	assert.EqualError(t, err, "failed to connect: timed out after 200ms")
+	assert.Less(t, time.Since(start), DefaultConnectTimeout)
+}
+
+func TestIsConnectionError(t *testing.T) {
+	cases := []struct {
+		err      error
+		expected bool
+	}{
+		{nil, false},
+		{errors.New("some error"), false},
+		{io.EOF, true},
+		{fmt.Errorf("wrapped: %w", io.ErrUnexpectedEOF), true},
+		{net.ErrClosed, true},
+		{&net.OpError{Op: "write", Err: syscall.EPIPE}, true},
+		{&net.OpError{Op: "read", Err: syscall.ECONNRESET}, true},
+		{tarantool.ClientError{Code: tarantool.ErrConnectionClosed}, true},
+		{tarantool.ClientError{Code: tarantool.ErrConnectionNotReady}, true},
+		{tarantool.ClientError{Code: tarantool.ErrTimeouted}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprint(tc.err), func(t *testing.T) {
+			assert.Equal(t, tc.expected, IsConnectionError(tc.err))
+		})
+	}
+}
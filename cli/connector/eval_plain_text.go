@@ -53,6 +53,75 @@ func callPlainTextConn(conn net.Conn, funcName string, args []interface{},
 	return evalPlainTextConn(conn, evalFunc, args, opts)
 }
 
+// selectPlainTextConn selects tuples from the space via an eval request.
+func selectPlainTextConn(conn net.Conn, space, index interface{}, key []interface{},
+	selectOpts SelectOpts, opts EvalPlainTextOpts) ([]interface{}, error) {
+	if key == nil {
+		key = []interface{}{}
+	}
+	luaOpts := map[string]interface{}{
+		"offset": selectOpts.Offset,
+	}
+	if selectOpts.Iterator != "" {
+		luaOpts["iterator"] = selectOpts.Iterator
+	}
+	if selectOpts.Limit != 0 {
+		luaOpts["limit"] = selectOpts.Limit
+	}
+
+	var tuples [][]interface{}
+	opts.ResData = &tuples
+	args := []interface{}{space, index, key, luaOpts}
+	if _, err := evalPlainTextConn(conn, selectFuncBody, args, opts); err != nil {
+		return nil, err
+	}
+
+	// All tuples are returned as the first value of the function.
+	if len(tuples) == 0 {
+		return []interface{}{}, nil
+	}
+	return tuples[0], nil
+}
+
+// plainTextSQLResult is a result of the SQL execution function.
+type plainTextSQLResult struct {
+	Columns          []string      `msgpack:"columns"`
+	Rows             []interface{} `msgpack:"rows"`
+	RowCount         uint64        `msgpack:"row_count"`
+	AutoincrementIDs []uint64      `msgpack:"autoincrement_ids"`
+}
+
+// executePlainTextConn executes the SQL statement via an eval request.
+func executePlainTextConn(conn net.Conn, sql string, binds []interface{},
+	opts EvalPlainTextOpts) (SQLResult, error) {
+	if binds == nil {
+		binds = []interface{}{}
+	}
+
+	var results []plainTextSQLResult
+	opts.ResData = &results
+	args := []interface{}{sql, binds}
+	if _, err := evalPlainTextConn(conn, executeFuncBody, args, opts); err != nil {
+		return SQLResult{}, err
+	}
+	if len(results) != 1 {
+		return SQLResult{}, fmt.Errorf("expected one result, found %d", len(results))
+	}
+
+	return SQLResult{
+		Columns:          results[0].Columns,
+		Rows:             results[0].Rows,
+		RowCount:         results[0].RowCount,
+		AutoincrementIDs: results[0].AutoincrementIDs,
+	}, nil
+}
+
+// pingPlainTextConn checks the connection via an empty eval request.
+func pingPlainTextConn(conn net.Conn, opts EvalPlainTextOpts) error {
+	_, err := evalPlainTextConn(conn, "return", []interface{}{}, opts)
+	return err
+}
+
 // evalPlainTextConnYAML calls function on Tarantool instance
 // Function should return `interface{}`, `string` (res, err)
 // to be correctly processed.
@@ -69,7 +138,7 @@ func evalPlainTextConn(conn net.Conn, funcBody string, args []interface{},
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to check returned data: %s", err)
+		return nil, fmt.Errorf("failed to check returned data: %w", err)
 	}
 
 	data, err := processEvalTarantoolRes(resBytes, opts.ResData)
@@ -107,7 +176,7 @@ func formatAndSendEvalFunc(conn net.Conn, funcBody string, args []interface{},
 
 	// write to socket
 	if err := writeToPlainTextConn(conn, evalFuncFormatted); err != nil {
-		return fmt.Errorf("failed to send eval function to socket: %s", err)
+		return fmt.Errorf("failed to send eval function to socket: %w", err)
 	}
 
 	return nil
@@ -116,11 +185,11 @@ func formatAndSendEvalFunc(conn net.Conn, funcBody string, args []interface{},
 func writeToPlainTextConn(conn net.Conn, data string) error {
 	writer := bufio.NewWriter(conn)
 	if _, err := writer.WriteString(data); err != nil {
-		return fmt.Errorf("failed to send to socket: %s", err)
+		return fmt.Errorf("failed to send to socket: %w", err)
 	}
 
 	if err := writer.Flush(); err != nil {
-		return fmt.Errorf("failed to flush: %s", err)
+		return fmt.Errorf("failed to flush: %w", err)
 	}
 
 	return nil
@@ -180,7 +249,7 @@ func readFromPlainTextConn(conn net.Conn, opts EvalPlainTextOpts) ([]byte, error
 		}
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to read from instance socket: %s", err)
+			return nil, fmt.Errorf("failed to read from instance socket: %w", err)
 		}
 
 		dataPortion := string(dataPortionBytes)
@@ -233,7 +302,7 @@ func readDataPortionFromPlainTextConn(conn net.Conn, buffer *bytes.Buffer,
 
 		if buffer.Len() == 0 {
 			if n, err := conn.Read(tmp); err != nil && err != io.EOF {
-				return nil, fmt.Errorf("failed to read: %s", err)
+				return nil, fmt.Errorf("failed to read: %w", err)
 			} else if n == 0 || err == io.EOF {
 				return nil, io.EOF
 			} else {
@@ -219,3 +219,93 @@ func TestMain(m *testing.M) {
 	code := runTestMain(m)
 	os.Exit(code)
 }
+
+func TestConnector_Call(t *testing.T) {
+	connects := createTestConnects(t)
+	for _, c := range connects {
+		defer c.connect.Close()
+	}
+
+	for _, c := range connects {
+		t.Run(c.protocol.String(), func(t *testing.T) {
+			ret, err := c.connect.Call("test_sum", []interface{}{1, 2}, RequestOpts{})
+			assert.NoError(t, err)
+			assert.Len(t, ret, 2)
+			assert.EqualValues(t, 3, ret[0])
+			assert.Equal(t, "sum", ret[1])
+
+			_, err = c.connect.Call("unknown_func", []interface{}{}, RequestOpts{})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestConnector_Select(t *testing.T) {
+	connects := createTestConnects(t)
+	for _, c := range connects {
+		defer c.connect.Close()
+	}
+
+	for _, c := range connects {
+		t.Run(c.protocol.String(), func(t *testing.T) {
+			ret, err := c.connect.Select("test", "pk", []interface{}{2},
+				SelectOpts{}, RequestOpts{})
+			assert.NoError(t, err)
+			assert.Len(t, ret, 1)
+			assert.Equal(t, "two", ret[0].([]interface{})[1])
+
+			ret, err = c.connect.Select("test", "name", []interface{}{"t"},
+				SelectOpts{Iterator: "GE", Limit: 5}, RequestOpts{})
+			assert.NoError(t, err)
+			assert.Len(t, ret, 2)
+
+			ret, err = c.connect.Select("test", 0, nil,
+				SelectOpts{Iterator: "ALL", Offset: 1, Limit: 1}, RequestOpts{})
+			assert.NoError(t, err)
+			assert.Len(t, ret, 1)
+			assert.Equal(t, "two", ret[0].([]interface{})[1])
+
+			_, err = c.connect.Select("unknown", "pk", nil, SelectOpts{}, RequestOpts{})
+			assert.ErrorContains(t, err, "space unknown does not exist")
+		})
+	}
+}
+
+func TestConnector_Execute(t *testing.T) {
+	connects := createTestConnects(t)
+	for _, c := range connects {
+		defer c.connect.Close()
+	}
+
+	for _, c := range connects {
+		t.Run(c.protocol.String(), func(t *testing.T) {
+			ret, err := c.connect.Execute(`SELECT "id", "name" FROM "test" WHERE "id" > ?`,
+				[]interface{}{1}, RequestOpts{})
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"id", "name"}, ret.Columns)
+			assert.Len(t, ret.Rows, 2)
+
+			ret, err = c.connect.Execute(`UPDATE "test" SET "name" = 'two' WHERE "id" = 2`,
+				[]interface{}{}, RequestOpts{})
+			assert.NoError(t, err)
+			assert.Equal(t, []string{}, ret.Columns)
+			assert.EqualValues(t, 1, ret.RowCount)
+
+			_, err = c.connect.Execute("SELECT * FROM unknown", nil, RequestOpts{})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestConnector_Ping(t *testing.T) {
+	connects := createTestConnects(t)
+	for _, c := range connects {
+		defer c.connect.Close()
+	}
+
+	for _, c := range connects {
+		t.Run(c.protocol.String(), func(t *testing.T) {
+			assert.NoError(t, c.connect.Ping(RequestOpts{}))
+		})
+	}
+}
@@ -0,0 +1,15 @@
+local sql, binds = ...
+local res, err = box.execute(sql, binds)
+if err ~= nil then
+    error(tostring(err), 0)
+end
+local columns = {}
+for _, column in ipairs(res.metadata or {}) do
+    table.insert(columns, column.name)
+end
+return {
+    columns = columns,
+    rows = res.rows or {},
+    row_count = res.row_count or 0,
+    autoincrement_ids = res.autoincrement_ids or {},
+}
@@ -0,0 +1,10 @@
+local space_id, index_id, key, opts = ...
+local space = box.space[space_id]
+if space == nil then
+    error(string.format("space %s does not exist", space_id), 0)
+end
+local index = space.index[index_id]
+if index == nil then
+    error(string.format("index %s does not exist in space %s", index_id, space.name), 0)
+end
+return index:select(key, opts)
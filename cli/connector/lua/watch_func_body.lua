@@ -0,0 +1,29 @@
+local key = ...
+if box.watch == nil then
+    error('box.watch is not supported by the instance, Tarantool 2.10+ is required', 0)
+end
+local fiber = require('fiber')
+local sid = box.session.id()
+local cond = fiber.cond()
+local values = {}
+local watcher = box.watch(key, function(_, value)
+    table.insert(values, {value})
+    cond:signal()
+end)
+local ok, err = pcall(function()
+    while box.session.exists(sid) do
+        if #values == 0 then
+            cond:wait(1)
+        else
+            local value = table.remove(values, 1)[1]
+            local pushed, push_err = box.session.push(value)
+            if not pushed then
+                error(push_err)
+            end
+        end
+    end
+end)
+watcher:unregister()
+if not ok then
+    error(err)
+end
@@ -2,6 +2,7 @@ package connector
 
 import (
 	"strings"
+	"time"
 )
 
 const (
@@ -20,6 +21,18 @@ type ConnectOpts struct {
 	Username string
 	// Password of the user.
 	Password string
+	// ConnectTimeout is a timeout for establishing a connection including
+	// the greeting. DefaultConnectTimeout is used if it is zero.
+	ConnectTimeout time.Duration
+	// RequestTimeout is a timeout for a request if the request does not
+	// specify its own one. Requests do not time out if it is zero.
+	RequestTimeout time.Duration
+	// RetryCount is a number of connection retries after a failed attempt.
+	RetryCount int
+	// RetryBackoff is a delay before the first retry. The delay is doubled
+	// for each next retry up to MaxRetryBackoff. DefaultRetryBackoff is
+	// used if it is zero.
+	RetryBackoff time.Duration
 }
 
 // MakeConnectOpts creates a new connection options object according to the
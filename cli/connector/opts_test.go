@@ -17,135 +17,168 @@ func TestMakeConnectOpts(t *testing.T) {
 	}{
 		{"", "", "",
 			ConnectOpts{
-				"tcp", "", "", "",
+				Network: "tcp", Address: "",
+				Username: "", Password: "",
 			}},
 		{"localhost:3013", "", "",
 			ConnectOpts{
-				"tcp", "localhost:3013", "", "",
+				Network: "tcp", Address: "localhost:3013",
+				Username: "", Password: "",
 			}},
 		{"tcp://localhost:3013", "", "",
 			ConnectOpts{
-				"tcp", "localhost:3013", "", "",
+				Network: "tcp", Address: "localhost:3013",
+				Username: "", Password: "",
 			}},
 		{"tcp:localhost", "", "",
 			ConnectOpts{
-				"tcp", "localhost", "", "",
+				Network: "tcp", Address: "localhost",
+				Username: "", Password: "",
 			}},
 		{"./path/to/socket", "", "",
 			ConnectOpts{
-				"unix", "./path/to/socket", "", "",
+				Network: "unix", Address: "./path/to/socket",
+				Username: "", Password: "",
 			}},
 		{"/path/to/socket", "", "",
 			ConnectOpts{
-				"unix", "/path/to/socket", "", "",
+				Network: "unix", Address: "/path/to/socket",
+				Username: "", Password: "",
 			}},
 		{"unix:///path/to/socket", "", "",
 			ConnectOpts{
-				"unix", "/path/to/socket", "", "",
+				Network: "unix", Address: "/path/to/socket",
+				Username: "", Password: "",
 			}},
 		{"unix:/path/to/socket", "", "",
 			ConnectOpts{
-				"unix", "/path/to/socket", "", "",
+				Network: "unix", Address: "/path/to/socket",
+				Username: "", Password: "",
 			}},
 		{"unix/:/path/to/socket", "", "",
 			ConnectOpts{
-				"unix", "/path/to/socket", "", "",
+				Network: "unix", Address: "/path/to/socket",
+				Username: "", Password: "",
 			}},
 		{"localhost:3013", "username", "password",
 			ConnectOpts{
-				"tcp", "localhost:3013", "username", "password",
+				Network: "tcp", Address: "localhost:3013",
+				Username: "username", Password: "password",
 			}},
 		{"tcp://localhost:3013", "username", "password",
 			ConnectOpts{
-				"tcp", "localhost:3013", "username", "password",
+				Network: "tcp", Address: "localhost:3013",
+				Username: "username", Password: "password",
 			}},
 		{"tcp:localhost", "username", "password",
 			ConnectOpts{
-				"tcp", "localhost", "username", "password",
+				Network: "tcp", Address: "localhost",
+				Username: "username", Password: "password",
 			}},
 		{"./path/to/socket", "username", "password",
 			ConnectOpts{
-				"unix", "./path/to/socket", "username", "password",
+				Network: "unix", Address: "./path/to/socket",
+				Username: "username", Password: "password",
 			}},
 		{"/path/to/socket", "username", "password",
 			ConnectOpts{
-				"unix", "/path/to/socket", "username", "password",
+				Network: "unix", Address: "/path/to/socket",
+				Username: "username", Password: "password",
 			}},
 		{"unix:///path/to/socket", "username", "password",
 			ConnectOpts{
-				"unix", "/path/to/socket", "username", "password",
+				Network: "unix", Address: "/path/to/socket",
+				Username: "username", Password: "password",
 			}},
 		{"unix:/path/to/socket", "username", "password",
 			ConnectOpts{
-				"unix", "/path/to/socket", "username", "password",
+				Network: "unix", Address: "/path/to/socket",
+				Username: "username", Password: "password",
 			}},
 		{"unix/:/path/to/socket", "username", "password",
 			ConnectOpts{
-				"unix", "/path/to/socket", "username", "password",
+				Network: "unix", Address: "/path/to/socket",
+				Username: "username", Password: "password",
 			}},
 		{"username:password@localhost:3013", "", "",
 			ConnectOpts{
-				"tcp", "localhost:3013", "username", "password",
+				Network: "tcp", Address: "localhost:3013",
+				Username: "username", Password: "password",
 			}},
 		{"username:password@tcp://localhost:3013", "", "",
 			ConnectOpts{
-				"tcp", "localhost:3013", "username", "password",
+				Network: "tcp", Address: "localhost:3013",
+				Username: "username", Password: "password",
 			}},
 		{"username:password@tcp:localhost", "", "",
 			ConnectOpts{
-				"tcp", "localhost", "username", "password",
+				Network: "tcp", Address: "localhost",
+				Username: "username", Password: "password",
 			}},
 		{"username:password@./path/to/socket", "", "",
 			ConnectOpts{
-				"unix", "./path/to/socket", "username", "password",
+				Network: "unix", Address: "./path/to/socket",
+				Username: "username", Password: "password",
 			}},
 		{"username:password@/path/to/socket", "", "",
 			ConnectOpts{
-				"unix", "/path/to/socket", "username", "password",
+				Network: "unix", Address: "/path/to/socket",
+				Username: "username", Password: "password",
 			}},
 		{"username:password@unix:///path/to/socket", "", "",
 			ConnectOpts{
-				"unix", "/path/to/socket", "username", "password",
+				Network: "unix", Address: "/path/to/socket",
+				Username: "username", Password: "password",
 			}},
 		{"username:password@unix:/path/to/socket", "", "",
 			ConnectOpts{
-				"unix", "/path/to/socket", "username", "password",
+				Network: "unix", Address: "/path/to/socket",
+				Username: "username", Password: "password",
 			}},
 		{"username:password@unix/:/path/to/socket", "", "",
 			ConnectOpts{
-				"unix", "/path/to/socket", "username", "password",
+				Network: "unix", Address: "/path/to/socket",
+				Username: "username", Password: "password",
 			}},
 		{"struser:strpass@localhost:3013", "username", "password",
 			ConnectOpts{
-				"tcp", "localhost:3013", "username", "password",
+				Network: "tcp", Address: "localhost:3013",
+				Username: "username", Password: "password",
 			}},
 		{"struser:strpass@tcp://localhost:3013", "username", "password",
 			ConnectOpts{
-				"tcp", "localhost:3013", "username", "password",
+				Network: "tcp", Address: "localhost:3013",
+				Username: "username", Password: "password",
 			}},
 		{"struser:strpass@tcp:localhost", "username", "password",
 			ConnectOpts{
-				"tcp", "localhost", "username", "password",
+				Network: "tcp", Address: "localhost",
+				Username: "username", Password: "password",
 			}},
 		{"struser:strpass@./path/to/socket", "username", "password",
 			ConnectOpts{
-				"unix", "./path/to/socket", "username", "password",
+				Network: "unix", Address: "./path/to/socket",
+				Username: "username", Password: "password",
 			}},
 		{"struser:strpass@/path/to/socket", "username", "password",
 			ConnectOpts{
-				"unix", "/path/to/socket", "username", "password",
+				Network: "unix", Address: "/path/to/socket",
+				Username: "username", Password: "password",
 			}},
 		{"struser:strpass@unix:///path/to/socket", "username", "password",
 			ConnectOpts{
-				"unix", "/path/to/socket", "username", "password",
+				Network: "unix", Address: "/path/to/socket",
+				Username: "username", Password: "password",
 			}},
 		{"struser:strpass@unix:/path/to/socket", "username", "password",
 			ConnectOpts{
-				"unix", "/path/to/socket", "username", "password",
+				Network: "unix", Address: "/path/to/socket",
+				Username: "username", Password: "password",
 			}},
 		{"struser:strpass@unix/:/path/to/socket", "username", "password",
 			ConnectOpts{
-				"unix", "/path/to/socket", "username", "password",
+				Network: "unix", Address: "/path/to/socket",
+				Username: "username", Password: "password",
 			}},
 	}
 
@@ -7,9 +7,22 @@ box.cfg{
 
 box.once("init", function()
     box.schema.user.create('test', {password = 'password'})
-    box.schema.user.grant('test', 'execute', 'universe')
+    box.schema.user.grant('test', 'read,write,execute', 'universe')
+
+    local space = box.schema.space.create('test', {
+        format = {{'id', 'unsigned'}, {'name', 'string'}},
+    })
+    space:create_index('pk')
+    space:create_index('name', {parts = {'name'}})
+    space:insert({1, 'one'})
+    space:insert({2, 'two'})
+    space:insert({3, 'three'})
 end)
 
+function test_sum(a, b)
+    return a + b, 'sum'
+end
+
 require("console").listen("unix/:./console.control")
 -- Set listen only when every other thing is configured.
 box.cfg{
@@ -2,12 +2,15 @@ package connector
 
 import (
 	"net"
+	"time"
 )
 
 // TextConnector implements Connector interface for a connection that sends
 // and receives data as a plain text.
 type TextConnector struct {
 	conn net.Conn
+	// requestTimeout is used for requests without a read timeout.
+	requestTimeout time.Duration
 }
 
 // NewTextConnector creates a new TextConnector object. The object will close
@@ -18,15 +21,54 @@ func NewTextConnector(conn net.Conn) *TextConnector {
 	}
 }
 
-// Eval sends an eval request.
-func (conn *TextConnector) Eval(expr string, args []interface{},
-	opts RequestOpts) ([]interface{}, error) {
+// evalOpts returns options of a plain text eval request.
+func (conn *TextConnector) evalOpts(opts RequestOpts) EvalPlainTextOpts {
 	evalOpts := EvalPlainTextOpts{
 		PushCallback: opts.PushCallback,
 		ReadTimeout:  opts.ReadTimeout,
 		ResData:      opts.ResData,
 	}
-	return evalPlainTextConn(conn.conn, expr, args, evalOpts)
+	if evalOpts.ReadTimeout == 0 {
+		evalOpts.ReadTimeout = conn.requestTimeout
+	}
+	return evalOpts
+}
+
+// Eval sends an eval request.
+func (conn *TextConnector) Eval(expr string, args []interface{},
+	opts RequestOpts) ([]interface{}, error) {
+	return evalPlainTextConn(conn.conn, expr, args, conn.evalOpts(opts))
+}
+
+// Call calls a function via an eval request.
+func (conn *TextConnector) Call(fnName string, args []interface{},
+	opts RequestOpts) ([]interface{}, error) {
+	return callPlainTextConn(conn.conn, fnName, args, conn.evalOpts(opts))
+}
+
+// Select selects tuples via an eval request.
+func (conn *TextConnector) Select(space, index interface{}, key []interface{},
+	selectOpts SelectOpts, opts RequestOpts) ([]interface{}, error) {
+	return selectPlainTextConn(conn.conn, space, index, key, selectOpts,
+		conn.evalOpts(opts))
+}
+
+// Execute executes the SQL statement via an eval request.
+func (conn *TextConnector) Execute(sql string, binds []interface{},
+	opts RequestOpts) (SQLResult, error) {
+	return executePlainTextConn(conn.conn, sql, binds, conn.evalOpts(opts))
+}
+
+// Ping checks the connection via an eval request.
+func (conn *TextConnector) Ping(opts RequestOpts) error {
+	return pingPlainTextConn(conn.conn, conn.evalOpts(opts))
+}
+
+// Watch watches the key with an eval request that pushes the values. The
+// connection is closed on stop.
+func (conn *TextConnector) Watch(key string, callback WatchCallback,
+	stop <-chan struct{}) error {
+	return watchEval(conn, conn.Close, key, callback, stop)
 }
 
 // Close closes the net.Conn created from.
@@ -1,11 +1,15 @@
 package connector_test
 
 import (
+	"bufio"
+	"encoding/base64"
 	"errors"
 	"net"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
 
 	. "github.com/tarantool/tt/cli/connector"
 )
@@ -53,3 +57,106 @@ func TestTextConnector_Close_nil(t *testing.T) {
 
 	assert.NoError(t, conn.Close())
 }
+
+// servePlainText reads a request from the connection and responds with the
+// encoded results of the evaluation.
+func servePlainText(t *testing.T, conn net.Conn, results []interface{}) <-chan string {
+	t.Helper()
+
+	requests := make(chan string, 1)
+	go func() {
+		request, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil {
+			close(requests)
+			return
+		}
+		requests <- request
+
+		encoded, err := msgpack.Marshal(results)
+		if err != nil {
+			close(requests)
+			return
+		}
+		conn.Write([]byte("---\n- data_enc: " +
+			base64.StdEncoding.EncodeToString(encoded) + "\n...\n"))
+	}()
+	return requests
+}
+
+func TestTextConnector_Select(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+	conn := NewTextConnector(client)
+	defer conn.Close()
+
+	tuples := []interface{}{[]interface{}{1, "one"}, []interface{}{2, "two"}}
+	requests := servePlainText(t, server, []interface{}{tuples})
+
+	ret, err := conn.Select("test", "pk", []interface{}{1},
+		SelectOpts{Iterator: "GE", Limit: 2}, RequestOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{
+		[]interface{}{int8(1), "one"},
+		[]interface{}{int8(2), "two"},
+	}, ret)
+	assert.Contains(t, <-requests, ":select(key, opts)")
+}
+
+func TestTextConnector_Execute(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+	conn := NewTextConnector(client)
+	defer conn.Close()
+
+	requests := servePlainText(t, server, []interface{}{map[string]interface{}{
+		"columns":           []string{"id", "name"},
+		"rows":              []interface{}{[]interface{}{1, "one"}},
+		"row_count":         0,
+		"autoincrement_ids": []interface{}{},
+	}})
+
+	ret, err := conn.Execute("SELECT * FROM test", nil, RequestOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, SQLResult{
+		Columns:          []string{"id", "name"},
+		Rows:             []interface{}{[]interface{}{int8(1), "one"}},
+		RowCount:         0,
+		AutoincrementIDs: []uint64{},
+	}, ret)
+	assert.Contains(t, <-requests, "box.execute(sql, binds)")
+}
+
+func TestTextConnector_Call(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+	conn := NewTextConnector(client)
+	defer conn.Close()
+
+	requests := servePlainText(t, server, []interface{}{"ok"})
+
+	ret, err := conn.Call("box.info", []interface{}{}, RequestOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"ok"}, ret)
+	assert.Contains(t, <-requests, "return box.info(...)")
+}
+
+func TestTextConnector_Ping(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+	conn := NewTextConnector(client)
+	defer conn.Close()
+
+	servePlainText(t, server, []interface{}{})
+	assert.NoError(t, conn.Ping(RequestOpts{}))
+}
+
+func TestTextConnector_Ping_closed(t *testing.T) {
+	client, server := net.Pipe()
+	conn := NewTextConnector(client)
+	defer conn.Close()
+
+	server.Close()
+	err := conn.Ping(RequestOpts{})
+	assert.Error(t, err)
+	assert.True(t, IsConnectionError(err))
+}
@@ -0,0 +1,47 @@
+package connector
+
+import (
+	"errors"
+	"fmt"
+)
+
+// WatchCallback is called with a value of the watched key.
+type WatchCallback func(value interface{})
+
+// Watcher is an interface that wraps Watch method.
+type Watcher interface {
+	// Watch calls the callback with the current value of the key and then
+	// on each change of the value until the stop channel is closed. The key
+	// is set with box.broadcast() on the instance.
+	Watch(key string, callback WatchCallback, stop <-chan struct{}) error
+}
+
+// watchEval watches the key with a long eval request that pushes the values
+// with box.session.push(). The server can't be asked to interrupt the
+// request, so the connection is closed with closeConn when the watching is
+// stopped.
+func watchEval(evaler Evaler, closeConn func() error, key string,
+	callback WatchCallback, stop <-chan struct{}) error {
+	done := make(chan error, 1)
+	go func() {
+		opts := RequestOpts{
+			PushCallback: func(value interface{}) {
+				callback(value)
+			},
+		}
+		_, err := evaler.Eval(watchFuncBody, []interface{}{key}, opts)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			err = errors.New("watching is finished by the instance")
+		}
+		return fmt.Errorf("failed to watch: %w", err)
+	case <-stop:
+		closeConn()
+		<-done
+		return nil
+	}
+}
@@ -0,0 +1,236 @@
+package connector_test
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+
+	. "github.com/tarantool/tt/cli/connector"
+)
+
+const (
+	testSalt     = "0123456789abcdefghij"
+	testUser     = "user"
+	testPassword = "password"
+)
+
+// fakeIprotoServer is a server that supports authentication, pings and eval
+// requests pushing values.
+type fakeIprotoServer struct {
+	listener net.Listener
+	// values are pushed as values of the watched key.
+	values []interface{}
+}
+
+func newFakeIprotoServer(t *testing.T, values []interface{}) *fakeIprotoServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &fakeIprotoServer{listener: listener, values: values}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (server *fakeIprotoServer) close() {
+	server.listener.Close()
+}
+
+func writePacket(conn net.Conn, header, body map[int]interface{}) error {
+	var payload bytes.Buffer
+	encoder := msgpack.NewEncoder(&payload)
+	if err := encoder.Encode(header); err != nil {
+		return err
+	}
+	if err := encoder.Encode(body); err != nil {
+		return err
+	}
+
+	packet := make([]byte, 5)
+	packet[0] = 0xce
+	binary.BigEndian.PutUint32(packet[1:], uint32(payload.Len()))
+	_, err := conn.Write(append(packet, payload.Bytes()...))
+	return err
+}
+
+func readPacket(conn net.Conn) (map[int]interface{}, map[int]interface{}, error) {
+	size := make([]byte, 5)
+	if _, err := io.ReadFull(conn, size); err != nil {
+		return nil, nil, err
+	}
+	payload := make([]byte, binary.BigEndian.Uint32(size[1:]))
+	if _, err := io.ReadFull(conn, payload); err != nil {
+		return nil, nil, err
+	}
+
+	var header, body map[int]interface{}
+	decoder := msgpack.NewDecoder(bytes.NewReader(payload))
+	if err := decoder.Decode(&header); err != nil {
+		return nil, nil, err
+	}
+	if err := decoder.Decode(&body); err != nil {
+		return nil, nil, err
+	}
+	return header, body, nil
+}
+
+func checkScramble(scramble []byte) bool {
+	hash1 := sha1.Sum([]byte(testPassword))
+	hash2 := sha1.Sum(hash1[:])
+	hash3 := sha1.Sum(append([]byte(testSalt), hash2[:]...))
+	for i := range hash3 {
+		hash3[i] ^= scramble[i]
+	}
+	return sha1.Sum(hash3[:]) == hash2
+}
+
+func (server *fakeIprotoServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	salt := base64.StdEncoding.EncodeToString([]byte(testSalt + strings.Repeat("0", 12)))
+	greeting := fmt.Sprintf("%-63s\n%-63s\n", "Tarantool 2.10.0 (Binary) uuid", salt)
+	if _, err := conn.Write([]byte(greeting)); err != nil {
+		return
+	}
+
+	for {
+		header, body, err := readPacket(conn)
+		if err != nil {
+			return
+		}
+
+		switch header[0x00] {
+		case int8(7):
+			// Authentication.
+			tuple := body[0x21].([]interface{})
+			code := 0
+			scramble, _ := tuple[1].(string)
+			if body[0x23] != testUser || !checkScramble([]byte(scramble)) {
+				code = 0x8000 | 47
+			}
+			writePacket(conn, map[int]interface{}{0x00: code, 0x01: header[0x01]},
+				map[int]interface{}{0x31: "User not found or supplied credentials are invalid"})
+		case int8(8):
+			// An eval request pushes the values of the watched key.
+			if args, _ := body[0x21].([]interface{}); len(args) != 1 || args[0] != "key" {
+				return
+			}
+			for _, value := range server.values {
+				writePacket(conn, map[int]interface{}{0x00: 0x80, 0x01: header[0x01]},
+					map[int]interface{}{0x30: []interface{}{value}})
+			}
+		case int8(64):
+			// Ping.
+			writePacket(conn, map[int]interface{}{0x00: 0, 0x01: header[0x01]},
+				map[int]interface{}{})
+		}
+	}
+}
+
+func TestBinaryConnector_Watch(t *testing.T) {
+	server := newFakeIprotoServer(t, []interface{}{"first", map[string]interface{}{"a": 1}})
+	defer server.close()
+
+	conn, err := Connect(ConnectOpts{
+		Network:  "tcp",
+		Address:  server.listener.Addr().String(),
+		Username: testUser,
+		Password: testPassword,
+	})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	values := make(chan interface{}, 10)
+	stop := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- conn.Watch("key", func(value interface{}) {
+			values <- value
+		}, stop)
+	}()
+
+	for _, expected := range []interface{}{
+		"first",
+		map[interface{}]interface{}{"a": uint64(1)},
+	} {
+		select {
+		case value := <-values:
+			assert.Equal(t, expected, value)
+		case <-time.After(time.Second):
+			require.Fail(t, "no value received")
+		}
+	}
+
+	close(stop)
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		require.Fail(t, "watch is not stopped")
+	}
+	// The key is watched via a separate connection.
+	assert.NoError(t, conn.Ping(RequestOpts{}))
+}
+
+func TestTextConnector_Watch(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+	conn := NewTextConnector(client)
+
+	go func() {
+		if _, err := bufio.NewReader(server).ReadString('\n'); err != nil {
+			return
+		}
+		for _, value := range []string{"first", "second"} {
+			push := "%TAG !push! tag:tarantool.io/push,2018\n--- " + value + "\n...\n"
+			if _, err := server.Write([]byte(push)); err != nil {
+				return
+			}
+		}
+	}()
+
+	values := make(chan interface{}, 10)
+	stop := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- conn.Watch("key", func(value interface{}) {
+			values <- value
+		}, stop)
+	}()
+
+	for _, expected := range []string{"first", "second"} {
+		select {
+		case value := <-values:
+			assert.Equal(t, expected, value)
+		case <-time.After(time.Second):
+			require.Fail(t, "no value received")
+		}
+	}
+
+	close(stop)
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		require.Fail(t, "watch is not stopped")
+	}
+}
@@ -0,0 +1,267 @@
+package api
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tarantool/tt/cli/ttlog"
+)
+
+const (
+	// TimestampHeader is a header with a Unix time of a signed request.
+	TimestampHeader = "X-TT-Timestamp"
+	// SignatureHeader is a header with a hex encoded HMAC-SHA256 signature
+	// of a request.
+	SignatureHeader = "X-TT-Signature"
+	// maxClockSkew is a maximum difference between the time of a signed
+	// request and the daemon time.
+	maxClockSkew = 5 * time.Minute
+	// maxRequestBodySize is a maximum size of a request body.
+	maxRequestBodySize = 1 << 20
+
+	// errCodeIPNotAllowed is an error code of a request from an address
+	// missing in the allowlist.
+	errCodeIPNotAllowed = "ip_not_allowed"
+	// errCodeInvalidRequest is an error code of a request that could not
+	// be read.
+	errCodeInvalidRequest = "invalid_request"
+	// errCodeUnauthorized is an error code of a request without valid
+	// credentials.
+	errCodeUnauthorized = "unauthorized"
+)
+
+// AuthOpts describes options of the request authentication.
+type AuthOpts struct {
+	// Token is a token expected in the "Authorization: Bearer" header.
+	Token string
+	// HMACKey is a key to check signatures of requests.
+	HMACKey string
+	// AllowedIPs is a list of IP addresses and CIDR networks allowed to
+	// send requests. All addresses are allowed if it is empty.
+	AllowedIPs []string
+}
+
+// AuthHandler checks a request before passing it to the next handler.
+type AuthHandler struct {
+	next       http.Handler
+	token      []byte
+	hmacKey    []byte
+	allowedIPs []*net.IPNet
+	logger     *ttlog.Logger
+	// now returns the current time, it is used to check signatures.
+	now func() time.Time
+}
+
+// parseAllowedIPs parses IP addresses and CIDR networks.
+func parseAllowedIPs(allowedIPs []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(allowedIPs))
+	for _, allowed := range allowedIPs {
+		if !strings.Contains(allowed, "/") {
+			ip := net.ParseIP(allowed)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP address: %q", allowed)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(allowed)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network: %q", allowed)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+// NewAuthHandler creates AuthHandler. Requests are passed as is if
+// the options are empty.
+func NewAuthHandler(next http.Handler, opts AuthOpts) (*AuthHandler, error) {
+	allowedIPs, err := parseAllowedIPs(opts.AllowedIPs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthHandler{
+		next:       next,
+		token:      []byte(opts.Token),
+		hmacKey:    []byte(opts.HMACKey),
+		allowedIPs: allowedIPs,
+		logger:     ttlog.NewCustomLogger(io.Discard, "", 0),
+		now:        time.Now,
+	}, nil
+}
+
+// Logger sets logger for AuthHandler.
+func (handler *AuthHandler) Logger(logger *ttlog.Logger) *AuthHandler {
+	handler.logger = logger
+	return handler
+}
+
+// checkIP checks the remote address of the request. Forwarding headers are
+// ignored, since they could be spoofed by the client.
+func (handler *AuthHandler) checkIP(req *http.Request) error {
+	if len(handler.allowedIPs) == 0 {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("invalid remote address: %q", req.RemoteAddr)
+	}
+
+	for _, allowed := range handler.allowedIPs {
+		if allowed.Contains(ip) {
+			return nil
+		}
+	}
+	return fmt.Errorf("IP address %s is not allowed", ip)
+}
+
+// getSignature returns a hex encoded HMAC-SHA256 signature of the request.
+// The signed message is the timestamp, the method, the request URI and
+// the body separated by new lines.
+func getSignature(key []byte, timestamp, method, uri string, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(timestamp + "\n" + method + "\n" + uri + "\n"))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// checkSignature checks the signature and the timestamp of the request.
+func (handler *AuthHandler) checkSignature(req *http.Request, body []byte) error {
+	timestamp := req.Header.Get(TimestampHeader)
+	signature := req.Header.Get(SignatureHeader)
+	if timestamp == "" || signature == "" {
+		return fmt.Errorf("request is not signed")
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp: %q", timestamp)
+	}
+	skew := handler.now().Sub(time.Unix(seconds, 0))
+	if math.Abs(float64(skew)) > float64(maxClockSkew) {
+		return fmt.Errorf("request timestamp is out of the allowed range")
+	}
+
+	expected := getSignature(handler.hmacKey, timestamp, req.Method, req.URL.RequestURI(), body)
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// bodyReadError is an error of reading a request body.
+type bodyReadError struct {
+	err error
+}
+
+// Error returns a string representation of the error.
+func (err *bodyReadError) Error() string {
+	return fmt.Sprintf("failed to read request body: %s", err.err)
+}
+
+// readBody reads the request body and restores it for the next handler.
+func readBody(req *http.Request) ([]byte, error) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, &bodyReadError{err}
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// checkCredentials checks the bearer token or the signature of the request.
+// The request is accepted if any of configured methods succeeds. The body is
+// read only to check the signature.
+func (handler *AuthHandler) checkCredentials(req *http.Request) error {
+	if len(handler.token) == 0 && len(handler.hmacKey) == 0 {
+		return nil
+	}
+
+	var errs []string
+	if len(handler.token) != 0 {
+		auth := req.Header.Get("Authorization")
+		if token := strings.TrimPrefix(auth, "Bearer "); token != auth {
+			if subtle.ConstantTimeCompare([]byte(token), handler.token) == 1 {
+				return nil
+			}
+			errs = append(errs, "invalid token")
+		} else {
+			errs = append(errs, "no bearer token")
+		}
+	}
+	if len(handler.hmacKey) != 0 {
+		body, err := readBody(req)
+		if err != nil {
+			return err
+		}
+		if err = handler.checkSignature(req, body); err == nil {
+			return nil
+		}
+		errs = append(errs, err.Error())
+	}
+	return fmt.Errorf("%s", strings.Join(errs, ", "))
+}
+
+// reject logs the failure and writes the error response.
+func (handler *AuthHandler) reject(wr http.ResponseWriter, req *http.Request,
+	status int, code string, err error) {
+	clientIpMsg, ipErr := getClientIP(req)
+	if ipErr != nil {
+		clientIpMsg = ipErr.Error()
+	}
+	handler.logger.Printf("Authentication failed: Client IP: %s; Remote address: %s; %s",
+		clientIpMsg, req.RemoteAddr, err)
+
+	if status == http.StatusUnauthorized {
+		wr.Header().Set("WWW-Authenticate", `Bearer realm="tt daemon"`)
+	}
+	wr.Header().Set("Content-Type", "application/json")
+	wr.WriteHeader(status)
+	json.NewEncoder(wr).Encode(&errorResult{Err: http.StatusText(status), Code: code})
+}
+
+// ServeHTTP checks the request and passes it to the next handler.
+func (handler *AuthHandler) ServeHTTP(wr http.ResponseWriter, req *http.Request) {
+	if err := handler.checkIP(req); err != nil {
+		handler.reject(wr, req, http.StatusForbidden, errCodeIPNotAllowed, err)
+		return
+	}
+
+	req.Body = http.MaxBytesReader(wr, req.Body, maxRequestBodySize)
+	if err := handler.checkCredentials(req); err != nil {
+		var readErr *bodyReadError
+		if errors.As(err, &readErr) {
+			handler.reject(wr, req, http.StatusBadRequest, errCodeInvalidRequest, err)
+		} else {
+			handler.reject(wr, req, http.StatusUnauthorized, errCodeUnauthorized, err)
+		}
+		return
+	}
+
+	handler.next.ServeHTTP(wr, req)
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/ttlog"
+)
+
+// echoHandler writes the request body back.
+type echoHandler struct{}
+
+func (echoHandler) ServeHTTP(wr http.ResponseWriter, req *http.Request) {
+	body, _ := ioutil.ReadAll(req.Body)
+	wr.Write(body)
+}
+
+func newTestAuthHandler(t *testing.T, opts AuthOpts) (*AuthHandler, *bytes.Buffer) {
+	handler, err := NewAuthHandler(echoHandler{}, opts)
+	require.NoError(t, err)
+	var logBuf bytes.Buffer
+	handler.Logger(ttlog.NewCustomLogger(&logBuf, "", 0))
+	handler.now = func() time.Time { return time.Unix(1000000, 0) }
+	return handler, &logBuf
+}
+
+func serve(handler http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestParseAllowedIPs(t *testing.T) {
+	nets, err := parseAllowedIPs([]string{"127.0.0.1", "10.0.0.0/8", "::1"})
+	require.NoError(t, err)
+	require.Len(t, nets, 3)
+	assert.Equal(t, "127.0.0.1/32", nets[0].String())
+	assert.Equal(t, "10.0.0.0/8", nets[1].String())
+	assert.Equal(t, "::1/128", nets[2].String())
+
+	_, err = parseAllowedIPs([]string{"localhost"})
+	assert.EqualError(t, err, `invalid IP address: "localhost"`)
+	_, err = parseAllowedIPs([]string{"10.0.0.0/33"})
+	assert.EqualError(t, err, `invalid network: "10.0.0.0/33"`)
+}
+
+func TestAuthHandler_noAuth(t *testing.T) {
+	handler, logBuf := newTestAuthHandler(t, AuthOpts{})
+	req := httptest.NewRequest("POST", "/tarantool", strings.NewReader("body"))
+	res := serve(handler, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "body", res.Body.String())
+	assert.Empty(t, logBuf.String())
+}
+
+func TestAuthHandler_allowedIPs(t *testing.T) {
+	handler, logBuf := newTestAuthHandler(t, AuthOpts{AllowedIPs: []string{"10.0.0.0/8"}})
+
+	req := httptest.NewRequest("POST", "/tarantool", strings.NewReader("body"))
+	req.RemoteAddr = "10.1.2.3:5000"
+	assert.Equal(t, http.StatusOK, serve(handler, req).Code)
+
+	// Forwarding headers are not trusted.
+	req = httptest.NewRequest("POST", "/tarantool", strings.NewReader("body"))
+	req.RemoteAddr = "192.168.0.1:5000"
+	req.Header.Set("X-REAL-IP", "10.1.2.3")
+	res := serve(handler, req)
+	assert.Equal(t, http.StatusForbidden, res.Code)
+	assert.Equal(t, "{\"err\":\"Forbidden\",\"code\":\"ip_not_allowed\"}\n", res.Body.String())
+	assert.Contains(t, logBuf.String(),
+		"Authentication failed: Client IP: 10.1.2.3; Remote address: 192.168.0.1:5000;"+
+			" IP address 192.168.0.1 is not allowed")
+}
+
+func TestAuthHandler_token(t *testing.T) {
+	handler, logBuf := newTestAuthHandler(t, AuthOpts{Token: "secret"})
+
+	req := httptest.NewRequest("POST", "/tarantool", strings.NewReader("body"))
+	req.Header.Set("Authorization", "Bearer secret")
+	res := serve(handler, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "body", res.Body.String())
+
+	for _, auth := range []string{"", "Bearer wrong", "secret"} {
+		req = httptest.NewRequest("POST", "/tarantool", strings.NewReader("body"))
+		req.Header.Set("Authorization", auth)
+		res = serve(handler, req)
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+		assert.Equal(t, `Bearer realm="tt daemon"`, res.Header().Get("WWW-Authenticate"))
+		assert.Equal(t, "{\"err\":\"Unauthorized\",\"code\":\"unauthorized\"}\n",
+			res.Body.String())
+	}
+	assert.Contains(t, logBuf.String(), "Authentication failed: Client IP: 192.0.2.1:1234;")
+	assert.Contains(t, logBuf.String(), "no bearer token")
+	assert.Contains(t, logBuf.String(), "invalid token")
+	assert.NotContains(t, logBuf.String(), "secret")
+}
+
+func TestAuthHandler_hmac(t *testing.T) {
+	handler, _ := newTestAuthHandler(t, AuthOpts{HMACKey: "key", Token: "secret"})
+	now := strconv.FormatInt(handler.now().Unix(), 10)
+
+	signedRequest := func(timestamp, body, signedBody string) *http.Request {
+		req := httptest.NewRequest("POST", "/tarantool?a=1", strings.NewReader(body))
+		req.Header.Set(TimestampHeader, timestamp)
+		req.Header.Set(SignatureHeader, getSignature([]byte("key"), timestamp,
+			"POST", "/tarantool?a=1", []byte(signedBody)))
+		return req
+	}
+
+	res := serve(handler, signedRequest(now, "body", "body"))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "body", res.Body.String())
+
+	// The bearer token is accepted too.
+	req := httptest.NewRequest("POST", "/tarantool", strings.NewReader("body"))
+	req.Header.Set("Authorization", "Bearer secret")
+	assert.Equal(t, http.StatusOK, serve(handler, req).Code)
+
+	cases := []struct {
+		name string
+		req  *http.Request
+	}{
+		{"not signed", httptest.NewRequest("POST", "/tarantool", nil)},
+		{"modified body", signedRequest(now, "body2", "body")},
+		{"expired", signedRequest(strconv.FormatInt(handler.now().Unix()-600, 10),
+			"body", "body")},
+		{"invalid timestamp", signedRequest("now", "body", "body")},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, http.StatusUnauthorized, serve(handler, tc.req).Code)
+		})
+	}
+}
+
+// readCounter counts reads of a request body.
+type readCounter struct {
+	reads int
+}
+
+func (counter *readCounter) Read(p []byte) (int, error) {
+	counter.reads++
+	return 0, io.EOF
+}
+
+func TestAuthHandler_body(t *testing.T) {
+	// The body is not read before the address and the token are checked.
+	handler, _ := newTestAuthHandler(t, AuthOpts{Token: "secret",
+		AllowedIPs: []string{"192.0.2.0/24"}})
+	counter := &readCounter{}
+	req := httptest.NewRequest("POST", "/tarantool", counter)
+	req.RemoteAddr = "198.51.100.1:1234"
+	assert.Equal(t, http.StatusForbidden, serve(handler, req).Code)
+	req = httptest.NewRequest("POST", "/tarantool", counter)
+	assert.Equal(t, http.StatusUnauthorized, serve(handler, req).Code)
+	assert.Equal(t, 0, counter.reads)
+
+	// The body of a signed request is limited.
+	handler, _ = newTestAuthHandler(t, AuthOpts{HMACKey: "key"})
+	body := strings.Repeat("a", maxRequestBodySize+1)
+	req = httptest.NewRequest("POST", "/tarantool", strings.NewReader(body))
+	res := serve(handler, req)
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.Contains(t, res.Body.String(), errCodeInvalidRequest)
+}
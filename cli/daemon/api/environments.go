@@ -0,0 +1,146 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/tarantool/tt/cli/ttlog"
+)
+
+const (
+	// EnvironmentsPath is a path of the environments resource.
+	EnvironmentsPath = "/v1/environments"
+	// DefaultEnvironment is a name of the environment the daemon is started
+	// in. It is used if no environments are configured.
+	DefaultEnvironment = "default"
+
+	// errCodeUnknownEnvironment is an error code of a request to a missing
+	// environment.
+	errCodeUnknownEnvironment = "unknown_environment"
+	// errCodeEnvironmentRequired is an error code of a request that is
+	// ambiguous without an environment.
+	errCodeEnvironmentRequired = "environment_required"
+)
+
+// Environment describes a tt environment managed by the daemon.
+type Environment struct {
+	// Name is a name of the environment.
+	Name string
+	// ConfigPath is a path to tt.yaml of the environment. The configuration
+	// of the daemon itself is used if it is empty.
+	ConfigPath string
+}
+
+// environmentNames returns names of the environments separated by commas.
+func environmentNames(envs []Environment) string {
+	names := make([]string, 0, len(envs))
+	for _, env := range envs {
+		names = append(names, env.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// resolveEnvironment returns the environment with the name. The name could
+// be omitted if there is the only environment.
+func resolveEnvironment(envs []Environment, name string) (Environment, *commandError) {
+	if name == "" {
+		if len(envs) == 1 {
+			return envs[0], nil
+		}
+		return Environment{}, &commandError{
+			status: http.StatusBadRequest,
+			code:   errCodeEnvironmentRequired,
+			msg: fmt.Sprintf("environment must be specified, available: %s",
+				environmentNames(envs)),
+		}
+	}
+
+	for _, env := range envs {
+		if env.Name == name {
+			return env, nil
+		}
+	}
+	return Environment{}, &commandError{
+		status: http.StatusBadRequest,
+		code:   errCodeUnknownEnvironment,
+		msg:    fmt.Sprintf("environment %q is not found", name),
+	}
+}
+
+// environmentJSON describes an environment.
+type environmentJSON struct {
+	// Name is a name of the environment.
+	Name string `json:"name"`
+	// ConfigPath is a path to tt.yaml of the environment.
+	ConfigPath string `json:"config_path,omitempty"`
+	// Instances is a number of enabled instances.
+	Instances int `json:"instances"`
+	// Err is an error of getting enabled instances.
+	Err string `json:"err,omitempty"`
+}
+
+// environmentsJSON describes a list of environments.
+type environmentsJSON struct {
+	Environments []environmentJSON `json:"environments"`
+}
+
+// EnvironmentsHandler handles requests to the environments resource.
+type EnvironmentsHandler struct {
+	manager InstanceManager
+	logger  *ttlog.Logger
+}
+
+// NewEnvironmentsHandler creates EnvironmentsHandler.
+func NewEnvironmentsHandler(manager InstanceManager) *EnvironmentsHandler {
+	return &EnvironmentsHandler{
+		manager: manager,
+		logger:  ttlog.NewCustomLogger(io.Discard, "", 0),
+	}
+}
+
+// Logger sets logger for EnvironmentsHandler.
+func (handler *EnvironmentsHandler) Logger(logger *ttlog.Logger) *EnvironmentsHandler {
+	handler.logger = logger
+	return handler
+}
+
+// ServeHTTP handles requests to the environments resource.
+func (handler *EnvironmentsHandler) ServeHTTP(wr http.ResponseWriter, req *http.Request) {
+	var res interface{}
+	status := http.StatusOK
+	if req.Method != http.MethodGet {
+		status = http.StatusMethodNotAllowed
+		res = &errorResult{
+			Err:  fmt.Sprintf("method %s is not allowed, use GET", req.Method),
+			Code: errCodeMethodNotAllowed,
+		}
+	} else {
+		envs := environmentsJSON{Environments: []environmentJSON{}}
+		for _, env := range handler.manager.Environments() {
+			envJSON := environmentJSON{Name: env.Name, ConfigPath: env.ConfigPath}
+			if instances, err := handler.manager.Instances(env.Name); err != nil {
+				envJSON.Err = err.Error()
+			} else {
+				envJSON.Instances = len(instances)
+			}
+			envs.Environments = append(envs.Environments, envJSON)
+		}
+		res = &envs
+	}
+
+	clientIpMsg, err := getClientIP(req)
+	if err != nil {
+		clientIpMsg = err.Error()
+	}
+	handler.logger.Printf("Client IP: %s; Request: %s %s; Status: %d",
+		clientIpMsg, req.Method, req.URL.Path, status)
+
+	wr.Header().Set("Content-Type", "application/json")
+	wr.WriteHeader(status)
+	if err := json.NewEncoder(wr).Encode(res); err != nil {
+		handler.logger.Printf("An error occurred while encoding the response: \"%v\"\n", err)
+	}
+}
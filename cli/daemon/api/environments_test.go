@@ -0,0 +1,122 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/running"
+)
+
+func TestResolveEnvironment(t *testing.T) {
+	prod := Environment{Name: "prod", ConfigPath: "/prod/tt.yaml"}
+	dev := Environment{Name: "dev", ConfigPath: "/dev/tt.yaml"}
+
+	env, err := resolveEnvironment([]Environment{prod}, "")
+	require.Nil(t, err)
+	assert.Equal(t, prod, env)
+
+	env, err = resolveEnvironment([]Environment{prod, dev}, "dev")
+	require.Nil(t, err)
+	assert.Equal(t, dev, env)
+
+	_, err = resolveEnvironment([]Environment{prod, dev}, "")
+	require.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.status)
+	assert.Equal(t, errCodeEnvironmentRequired, err.code)
+	assert.Equal(t, "environment must be specified, available: prod, dev", err.msg)
+
+	_, err = resolveEnvironment([]Environment{prod, dev}, "test")
+	require.NotNil(t, err)
+	assert.Equal(t, errCodeUnknownEnvironment, err.code)
+	assert.Equal(t, `environment "test" is not found`, err.msg)
+}
+
+func TestDaemonHandler_commandArgs(t *testing.T) {
+	handler := NewDaemonHandler("tt")
+	assert.Equal(t, []string{"status", "app"},
+		handler.commandArgs(&command{Name: "status", Params: []string{"app"}}))
+
+	handler.Environments([]Environment{{Name: "prod", ConfigPath: "/prod/tt.yaml"},
+		{Name: "dev", ConfigPath: "/dev/tt.yaml"}})
+	assert.Equal(t, []string{"--cfg", "/dev/tt.yaml", "stop", "app"},
+		handler.commandArgs(&command{Name: "stop", Params: []string{"app"},
+			Environment: "dev"}))
+}
+
+func TestEnvironmentsHandler(t *testing.T) {
+	manager := &fakeManager{
+		err: fmt.Errorf("tt.yaml not found"),
+		others: map[string][]running.InstanceCtx{
+			"prod": {newTestInstance(t, "app", "master")},
+		},
+	}
+	handler := NewEnvironmentsHandler(manager)
+
+	res := serve(handler, httptest.NewRequest("GET", "/v1/environments", nil))
+	require.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, `{"environments":[`+
+		`{"name":"default","instances":0,"err":"tt.yaml not found"},`+
+		`{"name":"prod","config_path":"prod/tt.yaml","instances":1}]}`+"\n",
+		res.Body.String())
+
+	res = serve(handler, httptest.NewRequest("POST", "/v1/environments", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, res.Code)
+}
+
+func TestInstancesHandler_environments(t *testing.T) {
+	manager := &fakeManager{
+		instances: []running.InstanceCtx{newTestInstance(t, "app", "master")},
+		others: map[string][]running.InstanceCtx{
+			"prod": {newTestInstance(t, "app", "master"), newTestInstance(t, "app", "replica")},
+		},
+	}
+	handler := NewInstancesHandler(manager)
+
+	res := serve(handler, httptest.NewRequest("GET", "/v1/instances", nil))
+	require.Equal(t, http.StatusOK, res.Code)
+	var list instancesJSON
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &list))
+	require.Len(t, list.Instances, 3)
+	assert.Equal(t, DefaultEnvironment, list.Instances[0].Environment)
+	assert.Equal(t, "prod", list.Instances[2].Environment)
+
+	res = serve(handler, httptest.NewRequest("GET", "/v1/instances?environment=prod", nil))
+	require.Equal(t, http.StatusOK, res.Code)
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &list))
+	require.Len(t, list.Instances, 2)
+
+	// The instance is found in the only environment.
+	res = serve(handler, httptest.NewRequest("POST", "/v1/instances/app/replica/start", nil))
+	require.Equal(t, http.StatusAccepted, res.Code)
+	assert.Equal(t, []string{"prod/app:replica"}, manager.started)
+
+	res = serve(handler, httptest.NewRequest("POST", "/v1/instances/app/master/start", nil))
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.Equal(t, `{"err":"instance app:master is found in several environments, `+
+		`environment must be specified: default, prod","code":"environment_required"}`+"\n",
+		res.Body.String())
+
+	res = serve(handler, httptest.NewRequest("POST",
+		"/v1/instances/app/master/start?environment=prod", nil))
+	require.Equal(t, http.StatusAccepted, res.Code)
+	assert.Equal(t, []string{"prod/app:replica", "prod/app:master"}, manager.started)
+
+	res = serve(handler, httptest.NewRequest("GET", "/v1/instances?environment=test", nil))
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.Equal(t, `{"err":"environment \"test\" is not found",`+
+		`"code":"unknown_environment"}`+"\n", res.Body.String())
+
+	// Failures of some environments are reported with instances of others.
+	manager.instances, manager.err = nil, fmt.Errorf("tt.yaml not found")
+	res = serve(handler, httptest.NewRequest("GET", "/v1/instances", nil))
+	require.Equal(t, http.StatusOK, res.Code)
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &list))
+	assert.Len(t, list.Instances, 2)
+	assert.Equal(t, []environmentErrorJSON{{DefaultEnvironment, "tt.yaml not found"}},
+		list.Errors)
+}
@@ -13,10 +13,21 @@ import (
 	"github.com/tarantool/tt/cli/ttlog"
 )
 
+// unixSocketClient is logged instead of an IP address of a client
+// connected to the unix socket.
+const unixSocketClient = "unix socket"
+
 // DaemonHandler is used to communicate with the daemon over HTTP.
 type DaemonHandler struct {
 	cmdPath string
 	logger  *ttlog.Logger
+	// allowedCommands is a set of commands available via the HTTP API.
+	allowedCommands map[string]bool
+	// instanceNames returns names of enabled instances allowed as
+	// command parameters.
+	instanceNames InstanceNamesFunc
+	// environments are environments commands could be run in.
+	environments []Environment
 }
 
 // resResult describes a failure during the command execution.
@@ -27,13 +38,13 @@ type resResult struct {
 // errorResult describes a failure during the command execution.
 type errorResult struct {
 	Err string `json:"err"`
+	// Code is an error code of a rejected request.
+	Code string `json:"code,omitempty"`
 }
 
 // callCommand invokes the command and returns the execution result.
 func (handler *DaemonHandler) callCommand(ttCmd *command) (string, error) {
-	newArgs := append([]string{ttCmd.Name}, ttCmd.Params...)
-
-	cmd := exec.Command(handler.cmdPath, newArgs...)
+	cmd := exec.Command(handler.cmdPath, handler.commandArgs(ttCmd)...)
 
 	var stderr bytes.Buffer
 	var stdout bytes.Buffer
@@ -50,10 +61,37 @@ func (handler *DaemonHandler) callCommand(ttCmd *command) (string, error) {
 
 // NewDaemonHandler creates DaemonHandler.
 func NewDaemonHandler(cmdPath string) *DaemonHandler {
-	return &DaemonHandler{
+	handler := &DaemonHandler{
 		cmdPath: cmdPath,
 		logger:  ttlog.NewCustomLogger(io.Discard, "", 0),
+		instanceNames: func(env string) ([]string, error) {
+			return nil, nil
+		},
+		environments: []Environment{{Name: DefaultEnvironment}},
 	}
+	return handler.AllowedCommands(DefaultAllowedCommands)
+}
+
+// AllowedCommands sets commands available via the HTTP API.
+func (handler *DaemonHandler) AllowedCommands(commands []string) *DaemonHandler {
+	handler.allowedCommands = make(map[string]bool, len(commands))
+	for _, command := range commands {
+		handler.allowedCommands[command] = true
+	}
+	return handler
+}
+
+// InstanceNames sets a function to get enabled instances allowed as
+// command parameters.
+func (handler *DaemonHandler) InstanceNames(instanceNames InstanceNamesFunc) *DaemonHandler {
+	handler.instanceNames = instanceNames
+	return handler
+}
+
+// Environments sets environments commands could be run in.
+func (handler *DaemonHandler) Environments(envs []Environment) *DaemonHandler {
+	handler.environments = envs
+	return handler
 }
 
 // Logger sets logger for DaemonHandler.
@@ -63,7 +101,12 @@ func (handler *DaemonHandler) Logger(logger *ttlog.Logger) *DaemonHandler {
 }
 
 // getClientIP gets the IP address of the client for an incoming HTTP request.
-func (handler *DaemonHandler) getClientIP(req *http.Request) (string, error) {
+func getClientIP(req *http.Request) (string, error) {
+	// Requests received on a unix socket have no client address.
+	if req.RemoteAddr == "" || req.RemoteAddr == "@" {
+		return unixSocketClient, nil
+	}
+
 	// Get IP from the X-REAL-IP header.
 	// X-REAL-IP header contains only one
 	// IP address of the client machine.
@@ -119,7 +162,7 @@ func (handler *DaemonHandler) ServeHTTP(wr http.ResponseWriter, req *http.Reques
 
 	// Construct client IP msg.
 	var clientIpMsg string
-	if ip, err := handler.getClientIP(req); err != nil {
+	if ip, err := getClientIP(req); err != nil {
 		clientIpMsg = err.Error()
 	} else {
 		clientIpMsg = ip
@@ -128,12 +171,15 @@ func (handler *DaemonHandler) ServeHTTP(wr http.ResponseWriter, req *http.Reques
 	rawBody, err := parseCommand(req.Body, &cmd)
 	if err != nil {
 		status = http.StatusBadRequest
-		res = &errorResult{err.Error()}
+		res = &errorResult{Err: err.Error()}
+	} else if cmdErr := handler.checkCommand(&cmd); cmdErr != nil {
+		status = cmdErr.status
+		res = &errorResult{Err: cmdErr.Error(), Code: cmdErr.code}
 	} else {
 		status = http.StatusOK
 		commandRes, err := handler.callCommand(&cmd)
 		if err != nil {
-			res = &errorResult{err.Error()}
+			res = &errorResult{Err: err.Error()}
 		} else {
 			res = &resResult{commandRes}
 		}
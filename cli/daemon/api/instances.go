@@ -0,0 +1,454 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/tarantool/tt/cli/process_utils"
+	"github.com/tarantool/tt/cli/running"
+	"github.com/tarantool/tt/cli/ttlog"
+)
+
+const (
+	// InstancesPath is a path of the instances resource.
+	InstancesPath = "/v1/instances"
+
+	// errCodeNotFound is an error code of a missing resource.
+	errCodeNotFound = "not_found"
+	// errCodeMethodNotAllowed is an error code of an unsupported method.
+	errCodeMethodNotAllowed = "method_not_allowed"
+	// errCodeAlreadyRunning is an error code of a start of a running
+	// instance.
+	errCodeAlreadyRunning = "already_running"
+	// errCodeNotRunning is an error code of an action that requires
+	// a running instance.
+	errCodeNotRunning = "not_running"
+	// errCodeActionFailed is an error code of a failed action.
+	errCodeActionFailed = "action_failed"
+)
+
+// Instance states.
+const (
+	instanceStateRunning = "running"
+	instanceStateStopped = "stopped"
+	instanceStateDead    = "dead"
+)
+
+// InstanceManager provides instances of the tt environments.
+type InstanceManager interface {
+	// Environments returns the managed environments.
+	Environments() []Environment
+	// Instances returns enabled instances of the environment.
+	Instances(env string) ([]running.InstanceCtx, error)
+	// Start starts the instance of the environment in background.
+	Start(env string, inst *running.InstanceCtx) error
+}
+
+// ManagerInstanceNames returns a function to get names of the manager
+// instances: application names and full instance names.
+func ManagerInstanceNames(manager InstanceManager) InstanceNamesFunc {
+	return func(env string) ([]string, error) {
+		instances, err := manager.Instances(env)
+		if err != nil {
+			return nil, err
+		}
+
+		names := []string{}
+		seen := map[string]bool{}
+		for _, inst := range instances {
+			for _, name := range []string{inst.AppName, running.GetAppInstanceName(inst)} {
+				if !seen[name] {
+					seen[name] = true
+					names = append(names, name)
+				}
+			}
+		}
+		return names, nil
+	}
+}
+
+// instancePathsJSON describes paths of an instance.
+type instancePathsJSON struct {
+	App           string `json:"app"`
+	RunDir        string `json:"run_dir"`
+	LogDir        string `json:"log_dir"`
+	Log           string `json:"log"`
+	PIDFile       string `json:"pid_file"`
+	ConsoleSocket string `json:"console_socket"`
+	WalDir        string `json:"wal_dir"`
+	MemtxDir      string `json:"memtx_dir"`
+	VinylDir      string `json:"vinyl_dir"`
+}
+
+// instanceJSON describes an instance and its state.
+type instanceJSON struct {
+	// Environment is a name of the environment of the instance.
+	Environment string `json:"environment"`
+	// App is a name of the application.
+	App string `json:"app"`
+	// Instance is a name of the instance. It is equal to the application
+	// name for a single instance application.
+	Instance string `json:"instance"`
+	// Name is a full name of the instance used by tt commands.
+	Name string `json:"name"`
+	// State is a state of the instance: running, stopped or dead.
+	State string `json:"state"`
+	// PID is a PID of the instance watchdog if the instance is running.
+	PID int `json:"pid,omitempty"`
+	// Paths are paths of the instance files.
+	Paths instancePathsJSON `json:"paths"`
+}
+
+// environmentErrorJSON describes a failure to get instances of an environment.
+type environmentErrorJSON struct {
+	Environment string `json:"environment"`
+	Err         string `json:"err"`
+}
+
+// instancesJSON describes a list of instances.
+type instancesJSON struct {
+	Instances []instanceJSON `json:"instances"`
+	// Errors are failures to get instances of environments.
+	Errors []environmentErrorJSON `json:"errors,omitempty"`
+}
+
+// environmentInstance is an instance of an environment.
+type environmentInstance struct {
+	env  string
+	inst running.InstanceCtx
+}
+
+// actionResultJSON describes a result of an action with an instance.
+type actionResultJSON struct {
+	// Action is a name of the action.
+	Action string `json:"action"`
+	// Message is a message of the action.
+	Message string `json:"message,omitempty"`
+	// Instance is the instance after the action.
+	Instance instanceJSON `json:"instance"`
+}
+
+// newInstanceJSON returns a description of the instance with its state.
+func newInstanceJSON(env string, inst running.InstanceCtx) instanceJSON {
+	res := instanceJSON{
+		Environment: env,
+		App:         inst.AppName,
+		Instance:    inst.InstName,
+		Name:        running.GetAppInstanceName(inst),
+		Paths: instancePathsJSON{
+			App:           inst.AppPath,
+			RunDir:        inst.RunDir,
+			LogDir:        inst.LogDir,
+			Log:           inst.Log,
+			PIDFile:       inst.PIDFile,
+			ConsoleSocket: inst.ConsoleSocket,
+			WalDir:        inst.WalDir,
+			MemtxDir:      inst.MemtxDir,
+			VinylDir:      inst.VinylDir,
+		},
+	}
+
+	switch running.Status(&inst).Code {
+	case process_utils.ProcessRunningCode:
+		res.State = instanceStateRunning
+		res.PID, _ = process_utils.GetPIDFromFile(inst.PIDFile)
+	case process_utils.ProcessDeadCode:
+		res.State = instanceStateDead
+	default:
+		res.State = instanceStateStopped
+	}
+	return res
+}
+
+// InstancesHandler handles requests to the instances resource.
+type InstancesHandler struct {
+	manager         InstanceManager
+	allowedCommands map[string]bool
+	logger          *ttlog.Logger
+}
+
+// NewInstancesHandler creates InstancesHandler.
+func NewInstancesHandler(manager InstanceManager) *InstancesHandler {
+	handler := &InstancesHandler{
+		manager: manager,
+		logger:  ttlog.NewCustomLogger(io.Discard, "", 0),
+	}
+	return handler.AllowedCommands(DefaultAllowedCommands)
+}
+
+// AllowedCommands sets actions available via the HTTP API.
+func (handler *InstancesHandler) AllowedCommands(commands []string) *InstancesHandler {
+	handler.allowedCommands = make(map[string]bool, len(commands))
+	for _, command := range commands {
+		handler.allowedCommands[command] = true
+	}
+	return handler
+}
+
+// Logger sets logger for InstancesHandler.
+func (handler *InstancesHandler) Logger(logger *ttlog.Logger) *InstancesHandler {
+	handler.logger = logger
+	return handler
+}
+
+// getInstances returns enabled instances of the environment or of all
+// environments if it is empty. Failures are returned for each environment.
+// An error is returned if instances of no environment could be got.
+func (handler *InstancesHandler) getInstances(envName string) ([]environmentInstance,
+	[]environmentErrorJSON, *commandError) {
+	envs := handler.manager.Environments()
+	if envName != "" {
+		env, err := resolveEnvironment(envs, envName)
+		if err != nil {
+			return nil, nil, err
+		}
+		envs = []Environment{env}
+	}
+
+	instances := []environmentInstance{}
+	envErrors := []environmentErrorJSON{}
+	for _, env := range envs {
+		envInstances, err := handler.manager.Instances(env.Name)
+		if err != nil {
+			envErrors = append(envErrors, environmentErrorJSON{env.Name, err.Error()})
+			continue
+		}
+		for _, inst := range envInstances {
+			instances = append(instances, environmentInstance{env.Name, inst})
+		}
+	}
+
+	if len(envs) > 0 && len(envErrors) == len(envs) {
+		msgs := make([]string, 0, len(envErrors))
+		for _, envErr := range envErrors {
+			msgs = append(msgs, envErr.Err)
+			if len(envs) > 1 {
+				msgs[len(msgs)-1] = envErr.Environment + ": " + envErr.Err
+			}
+		}
+		return nil, nil, &commandError{
+			status: http.StatusInternalServerError,
+			code:   errCodeInstancesUnavailable,
+			msg: fmt.Sprintf("failed to get enabled instances: %s",
+				strings.Join(msgs, "; ")),
+		}
+	}
+	return instances, envErrors, nil
+}
+
+// findInstance returns the instance of the application. The instance is
+// looked for in all environments if the environment is empty.
+func (handler *InstancesHandler) findInstance(env, app, inst string) (environmentInstance,
+	*commandError) {
+	instances, _, err := handler.getInstances(env)
+	if err != nil {
+		return environmentInstance{}, err
+	}
+
+	found := []environmentInstance{}
+	for _, instance := range instances {
+		if instance.inst.AppName == app && instance.inst.InstName == inst {
+			found = append(found, instance)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return environmentInstance{}, &commandError{
+			status: http.StatusNotFound,
+			code:   errCodeNotFound,
+			msg:    fmt.Sprintf("instance %s:%s is not found", app, inst),
+		}
+	case 1:
+		return found[0], nil
+	}
+
+	envs := make([]string, 0, len(found))
+	for _, instance := range found {
+		envs = append(envs, instance.env)
+	}
+	return environmentInstance{}, &commandError{
+		status: http.StatusBadRequest,
+		code:   errCodeEnvironmentRequired,
+		msg: fmt.Sprintf("instance %s:%s is found in several environments, "+
+			"environment must be specified: %s", app, inst, strings.Join(envs, ", ")),
+	}
+}
+
+// listInstances returns enabled instances of the environment or of all
+// environments if it is empty.
+func (handler *InstancesHandler) listInstances(env string) (interface{}, *commandError) {
+	instances, envErrors, err := handler.getInstances(env)
+	if err != nil {
+		return nil, err
+	}
+
+	res := instancesJSON{
+		Instances: make([]instanceJSON, 0, len(instances)),
+		Errors:    envErrors,
+	}
+	for _, inst := range instances {
+		res.Instances = append(res.Instances, newInstanceJSON(inst.env, inst.inst))
+	}
+	return &res, nil
+}
+
+// doAction performs the action with the instance. It returns a status of
+// the response, since a start is not finished on return.
+func (handler *InstancesHandler) doAction(envInst environmentInstance,
+	action string) (int, interface{}, *commandError) {
+	env, inst := envInst.env, envInst.inst
+	if !handler.allowedCommands[action] {
+		return 0, nil, &commandError{
+			status: http.StatusForbidden,
+			code:   errCodeCommandNotAllowed,
+			msg:    fmt.Sprintf("command %q is not allowed", action),
+		}
+	}
+
+	name := running.GetAppInstanceName(inst)
+	isRunning := newInstanceJSON(env, inst).State == instanceStateRunning
+	actionErr := func(err error) *commandError {
+		return &commandError{
+			status: http.StatusInternalServerError,
+			code:   errCodeActionFailed,
+			msg:    fmt.Sprintf("failed to %s %s: %s", action, name, err),
+		}
+	}
+	notRunningErr := &commandError{
+		status: http.StatusConflict,
+		code:   errCodeNotRunning,
+		msg:    fmt.Sprintf("instance %s is not running", name),
+	}
+
+	status := http.StatusOK
+	res := actionResultJSON{Action: action}
+	switch action {
+	case "start":
+		if isRunning {
+			return 0, nil, &commandError{
+				status: http.StatusConflict,
+				code:   errCodeAlreadyRunning,
+				msg:    fmt.Sprintf("instance %s is already running", name),
+			}
+		}
+		if err := handler.manager.Start(env, &inst); err != nil {
+			return 0, nil, actionErr(err)
+		}
+		status = http.StatusAccepted
+		res.Message = fmt.Sprintf("Starting an instance [%s]...", name)
+	case "stop":
+		if !isRunning {
+			return 0, nil, notRunningErr
+		}
+		if err := running.Stop(&inst); err != nil {
+			return 0, nil, actionErr(err)
+		}
+		res.Message = fmt.Sprintf("The Instance %s has been terminated.", name)
+	case "restart":
+		if isRunning {
+			if err := running.Stop(&inst); err != nil {
+				return 0, nil, actionErr(err)
+			}
+		}
+		if err := handler.manager.Start(env, &inst); err != nil {
+			return 0, nil, actionErr(err)
+		}
+		status = http.StatusAccepted
+		res.Message = fmt.Sprintf("Restarting an instance [%s]...", name)
+	case "logrotate":
+		if !isRunning {
+			return 0, nil, notRunningErr
+		}
+		msg, err := running.Logrotate(&inst)
+		if err != nil {
+			return 0, nil, actionErr(err)
+		}
+		res.Message = msg
+	default:
+		return 0, nil, &commandError{
+			status: http.StatusNotFound,
+			code:   errCodeNotFound,
+			msg:    fmt.Sprintf("unknown action %q", action),
+		}
+	}
+
+	res.Instance = newInstanceJSON(env, inst)
+	return status, &res, nil
+}
+
+// route handles the request and returns a status and a result.
+func (handler *InstancesHandler) route(req *http.Request) (int, interface{}, *commandError) {
+	methodErr := func(allowed string) *commandError {
+		return &commandError{
+			status: http.StatusMethodNotAllowed,
+			code:   errCodeMethodNotAllowed,
+			msg:    fmt.Sprintf("method %s is not allowed, use %s", req.Method, allowed),
+		}
+	}
+
+	env := req.URL.Query().Get("environment")
+	path := strings.Trim(strings.TrimPrefix(req.URL.Path, InstancesPath), "/")
+	parts := []string{}
+	if path != "" {
+		parts = strings.Split(path, "/")
+	}
+
+	switch len(parts) {
+	case 0:
+		if req.Method != http.MethodGet {
+			return 0, nil, methodErr(http.MethodGet)
+		}
+		res, err := handler.listInstances(env)
+		return http.StatusOK, res, err
+	case 2:
+		if req.Method != http.MethodGet {
+			return 0, nil, methodErr(http.MethodGet)
+		}
+		inst, err := handler.findInstance(env, parts[0], parts[1])
+		if err != nil {
+			return 0, nil, err
+		}
+		res := newInstanceJSON(inst.env, inst.inst)
+		return http.StatusOK, &res, nil
+	case 3:
+		if req.Method != http.MethodPost {
+			return 0, nil, methodErr(http.MethodPost)
+		}
+		inst, err := handler.findInstance(env, parts[0], parts[1])
+		if err != nil {
+			return 0, nil, err
+		}
+		return handler.doAction(inst, parts[2])
+	}
+
+	return 0, nil, &commandError{
+		status: http.StatusNotFound,
+		code:   errCodeNotFound,
+		msg:    fmt.Sprintf("resource %s is not found", req.URL.Path),
+	}
+}
+
+// ServeHTTP handles requests to the instances resource.
+func (handler *InstancesHandler) ServeHTTP(wr http.ResponseWriter, req *http.Request) {
+	status, res, cmdErr := handler.route(req)
+	if cmdErr != nil {
+		status = cmdErr.status
+		res = &errorResult{Err: cmdErr.Error(), Code: cmdErr.code}
+	}
+
+	clientIpMsg, err := getClientIP(req)
+	if err != nil {
+		clientIpMsg = err.Error()
+	}
+	handler.logger.Printf("Client IP: %s; Request: %s %s; Status: %d",
+		clientIpMsg, req.Method, req.URL.Path, status)
+
+	wr.Header().Set("Content-Type", "application/json")
+	wr.WriteHeader(status)
+	if err := json.NewEncoder(wr).Encode(res); err != nil {
+		handler.logger.Printf("An error occurred while encoding the response: \"%v\"\n", err)
+	}
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/running"
+)
+
+// fakeManager is an instance manager with predefined instances.
+type fakeManager struct {
+	// instances are instances of the default environment.
+	instances []running.InstanceCtx
+	// err is an error of getting instances of the default environment.
+	err error
+	// others are instances of other environments.
+	others  map[string][]running.InstanceCtx
+	started []string
+}
+
+func (manager *fakeManager) Environments() []Environment {
+	envs := []Environment{{Name: DefaultEnvironment}}
+	names := []string{}
+	for name := range manager.others {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		envs = append(envs, Environment{Name: name, ConfigPath: name + "/tt.yaml"})
+	}
+	return envs
+}
+
+func (manager *fakeManager) Instances(env string) ([]running.InstanceCtx, error) {
+	if env == DefaultEnvironment {
+		return manager.instances, manager.err
+	}
+	if instances, ok := manager.others[env]; ok {
+		return instances, nil
+	}
+	return nil, fmt.Errorf("unknown environment %q", env)
+}
+
+func (manager *fakeManager) Start(env string, inst *running.InstanceCtx) error {
+	name := running.GetAppInstanceName(*inst)
+	if env != DefaultEnvironment {
+		name = env + "/" + name
+	}
+	manager.started = append(manager.started, name)
+	return nil
+}
+
+// newTestInstance returns an instance with files in the temporary directory.
+func newTestInstance(t *testing.T, app, inst string) running.InstanceCtx {
+	runDir := t.TempDir()
+	return running.InstanceCtx{
+		AppName:       app,
+		InstName:      inst,
+		AppPath:       filepath.Join(runDir, "init.lua"),
+		RunDir:        runDir,
+		PIDFile:       filepath.Join(runDir, inst+".pid"),
+		ConsoleSocket: filepath.Join(runDir, inst+".control"),
+	}
+}
+
+// startTestProcess starts a process and writes its PID to the instance PID file.
+func startTestProcess(t *testing.T, inst running.InstanceCtx) int {
+	cmd := exec.Command("sleep", "60")
+	require.NoError(t, cmd.Start())
+	go cmd.Wait()
+	t.Cleanup(func() { cmd.Process.Kill() })
+	require.NoError(t, os.WriteFile(inst.PIDFile,
+		[]byte(strconv.Itoa(cmd.Process.Pid)), 0644))
+	return cmd.Process.Pid
+}
+
+func TestInstancesHandler(t *testing.T) {
+	stopped := newTestInstance(t, "app", "master")
+	runningInst := newTestInstance(t, "app", "replica")
+	pid := startTestProcess(t, runningInst)
+	manager := &fakeManager{instances: []running.InstanceCtx{stopped, runningInst}}
+	handler := NewInstancesHandler(manager)
+
+	res := serve(handler, httptest.NewRequest("GET", "/v1/instances", nil))
+	require.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
+	var list instancesJSON
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &list))
+	require.Len(t, list.Instances, 2)
+	assert.Equal(t, "app:master", list.Instances[0].Name)
+	assert.Equal(t, instanceStateStopped, list.Instances[0].State)
+	assert.Equal(t, 0, list.Instances[0].PID)
+	assert.Equal(t, stopped.PIDFile, list.Instances[0].Paths.PIDFile)
+	assert.Equal(t, instanceStateRunning, list.Instances[1].State)
+	assert.Equal(t, pid, list.Instances[1].PID)
+
+	res = serve(handler, httptest.NewRequest("GET", "/v1/instances/app/replica", nil))
+	require.Equal(t, http.StatusOK, res.Code)
+	var inst instanceJSON
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &inst))
+	assert.Equal(t, "app", inst.App)
+	assert.Equal(t, "replica", inst.Instance)
+	assert.Equal(t, instanceStateRunning, inst.State)
+
+	res = serve(handler, httptest.NewRequest("POST", "/v1/instances/app/master/start", nil))
+	require.Equal(t, http.StatusAccepted, res.Code)
+	var result actionResultJSON
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &result))
+	assert.Equal(t, "start", result.Action)
+	assert.Equal(t, "app:master", result.Instance.Name)
+	assert.Equal(t, []string{"app:master"}, manager.started)
+
+	res = serve(handler, httptest.NewRequest("POST", "/v1/instances/app/replica/stop", nil))
+	require.Equal(t, http.StatusOK, res.Code)
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &result))
+	assert.Equal(t, "stop", result.Action)
+	assert.NotEqual(t, instanceStateRunning, result.Instance.State)
+}
+
+func TestInstancesHandler_errors(t *testing.T) {
+	stopped := newTestInstance(t, "app", "master")
+	runningInst := newTestInstance(t, "app", "replica")
+	startTestProcess(t, runningInst)
+	manager := &fakeManager{instances: []running.InstanceCtx{stopped, runningInst}}
+	handler := NewInstancesHandler(manager).AllowedCommands([]string{"start", "stop"})
+
+	cases := []struct {
+		method   string
+		path     string
+		status   int
+		expected string
+	}{
+		{"POST", "/v1/instances", http.StatusMethodNotAllowed,
+			`{"err":"method POST is not allowed, use GET","code":"method_not_allowed"}`},
+		{"GET", "/v1/instances/app/master/start", http.StatusMethodNotAllowed,
+			`{"err":"method GET is not allowed, use POST","code":"method_not_allowed"}`},
+		{"GET", "/v1/instances/app/other", http.StatusNotFound,
+			`{"err":"instance app:other is not found","code":"not_found"}`},
+		{"GET", "/v1/instances/app", http.StatusNotFound,
+			`{"err":"resource /v1/instances/app is not found","code":"not_found"}`},
+		{"POST", "/v1/instances/app/master/kill", http.StatusForbidden,
+			`{"err":"command \"kill\" is not allowed","code":"command_not_allowed"}`},
+		{"POST", "/v1/instances/app/replica/start", http.StatusConflict,
+			`{"err":"instance app:replica is already running","code":"already_running"}`},
+		{"POST", "/v1/instances/app/master/stop", http.StatusConflict,
+			`{"err":"instance app:master is not running","code":"not_running"}`},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%s %s", tc.method, tc.path), func(t *testing.T) {
+			res := serve(handler, httptest.NewRequest(tc.method, tc.path, nil))
+			assert.Equal(t, tc.status, res.Code)
+			assert.Equal(t, tc.expected+"\n", res.Body.String())
+		})
+	}
+	assert.Empty(t, manager.started)
+
+	manager.err = fmt.Errorf("tt.yaml not found")
+	res := serve(handler, httptest.NewRequest("GET", "/v1/instances", nil))
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
+	assert.Equal(t, `{"err":"failed to get enabled instances: tt.yaml not found",`+
+		`"code":"instances_unavailable"}`+"\n", res.Body.String())
+}
+
+func TestManagerInstanceNames(t *testing.T) {
+	manager := &fakeManager{instances: []running.InstanceCtx{
+		{AppName: "app", InstName: "master"},
+		{AppName: "app", InstName: "replica"},
+		{AppName: "single", InstName: "single", SingleApp: true},
+	}}
+	names, err := ManagerInstanceNames(manager)(DefaultEnvironment)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app", "app:master", "app:replica", "single"}, names)
+}
+
+func TestOpenAPIHandler(t *testing.T) {
+	res := serve(OpenAPIHandler{}, httptest.NewRequest("GET", "/v1/openapi.json", nil))
+	require.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &doc))
+	assert.Contains(t, doc["paths"], "/v1/instances")
+
+	res = serve(OpenAPIHandler{}, httptest.NewRequest("POST", "/v1/openapi.json", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, res.Code)
+}
@@ -0,0 +1,554 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tarantool/tt/cli/ttlog"
+)
+
+const (
+	// JobsPath is a path of the jobs resource.
+	JobsPath = "/v1/jobs"
+	// DefaultJobsHistorySize is a number of finished jobs kept in memory
+	// by default.
+	DefaultJobsHistorySize = 100
+	// maxJobOutputSize is a maximum size of a job output kept in memory.
+	maxJobOutputSize = 1 << 20
+
+	// errCodeJobFinished is an error code of a cancellation of a finished job.
+	errCodeJobFinished = "job_finished"
+	// errCodeStreamUnsupported is an error code of a log stream request
+	// to a connection without streaming support.
+	errCodeStreamUnsupported = "stream_unsupported"
+)
+
+// Job states.
+const (
+	jobStateRunning   = "running"
+	jobStateSucceeded = "succeeded"
+	jobStateFailed    = "failed"
+	jobStateCanceled  = "canceled"
+)
+
+// jobRequestJSON describes a request to create a job.
+type jobRequestJSON struct {
+	// Name is a name of the command. It should be in the allowlist.
+	Name string `json:"command_name"`
+	// Params are command parameters: a name of an enabled application
+	// or instance.
+	Params []string `json:"params"`
+	// Environment is a name of the environment to run the command in.
+	Environment string `json:"environment"`
+	// Timeout is a maximum duration of the job in seconds. The job is
+	// not limited if it is zero.
+	Timeout int `json:"timeout"`
+}
+
+// jobJSON describes a job and its state.
+type jobJSON struct {
+	// ID is an identifier of the job.
+	ID string `json:"id"`
+	// Name is a name of the command.
+	Name string `json:"command_name"`
+	// Params are command parameters.
+	Params []string `json:"params"`
+	// Environment is a name of the environment the command is run in.
+	Environment string `json:"environment"`
+	// State is a state of the job: running, succeeded, failed or canceled.
+	State string `json:"state"`
+	// Created is a time the job was created at.
+	Created time.Time `json:"created_at"`
+	// Finished is a time the job was finished at.
+	Finished *time.Time `json:"finished_at,omitempty"`
+	// ExitCode is an exit code of the command of the finished job.
+	ExitCode *int `json:"exit_code,omitempty"`
+	// Err is a failure reason of the job.
+	Err string `json:"err,omitempty"`
+	// Output is the command output. It is omitted in the list of jobs.
+	Output *string `json:"output,omitempty"`
+}
+
+// jobsJSON describes a list of jobs.
+type jobsJSON struct {
+	Jobs []jobJSON `json:"jobs"`
+}
+
+// job is a tt command executed in background.
+type job struct {
+	id      string
+	cmd     command
+	created time.Time
+	cancel  context.CancelFunc
+
+	mutex    sync.Mutex
+	state    string
+	finished time.Time
+	exitCode int
+	err      string
+	output   []byte
+	// outputLimit is a maximum size of the output. The oldest half of
+	// the output is dropped when the limit is exceeded.
+	outputLimit int
+	// dropped is a size of the dropped output.
+	dropped int
+	// updated is closed and replaced on each output or state change to
+	// wake up log streams.
+	updated chan struct{}
+}
+
+// Write appends the command output to the job output.
+func (job *job) Write(data []byte) (int, error) {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+	job.output = append(job.output, data...)
+	if len(job.output) > job.outputLimit {
+		// The output is cut at a line start if possible.
+		cut := len(job.output) - job.outputLimit/2
+		if end := bytes.IndexByte(job.output[cut:], '\n'); end >= 0 {
+			cut += end + 1
+		}
+		output := make([]byte, len(job.output)-cut, job.outputLimit)
+		copy(output, job.output[cut:])
+		job.output = output
+		job.dropped += cut
+	}
+	job.notify()
+	return len(data), nil
+}
+
+// droppedMessage returns a message about the dropped output.
+func droppedMessage(size int) string {
+	return fmt.Sprintf("... %d bytes of the output are dropped ...", size)
+}
+
+// notify wakes up log streams. The job mutex must be locked.
+func (job *job) notify() {
+	close(job.updated)
+	job.updated = make(chan struct{})
+}
+
+// finish sets the final state of the job.
+func (job *job) finish(state string, exitCode int, err string) {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+	job.state = state
+	job.exitCode = exitCode
+	job.err = err
+	job.finished = time.Now()
+	job.notify()
+}
+
+// isRunning returns true if the job is not finished.
+func (job *job) isRunning() bool {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+	return job.state == jobStateRunning
+}
+
+// toJSON returns a description of the job. The output is included if
+// withOutput is set.
+func (job *job) toJSON(withOutput bool) jobJSON {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	res := jobJSON{
+		ID:          job.id,
+		Name:        job.cmd.Name,
+		Params:      job.cmd.Params,
+		Environment: job.cmd.Environment,
+		State:       job.state,
+		Created:     job.created,
+		Err:         job.err,
+	}
+	if res.Params == nil {
+		res.Params = []string{}
+	}
+	if job.state != jobStateRunning {
+		finished, exitCode := job.finished, job.exitCode
+		res.Finished, res.ExitCode = &finished, &exitCode
+	}
+	if withOutput {
+		output := string(job.output)
+		if job.dropped > 0 {
+			output = droppedMessage(job.dropped) + "\n" + output
+		}
+		res.Output = &output
+	}
+	return res
+}
+
+// JobsHandler runs tt commands as background jobs and handles requests
+// to the jobs resource.
+type JobsHandler struct {
+	// commands checks and runs commands.
+	commands    *DaemonHandler
+	historySize int
+	outputLimit int
+	logger      *ttlog.Logger
+
+	mutex  sync.Mutex
+	lastID int
+	// jobs are jobs in order of creation.
+	jobs []*job
+}
+
+// NewJobsHandler creates JobsHandler. Commands are checked and run as
+// the daemon handler does.
+func NewJobsHandler(commands *DaemonHandler) *JobsHandler {
+	return &JobsHandler{
+		commands:    commands,
+		historySize: DefaultJobsHistorySize,
+		outputLimit: maxJobOutputSize,
+		logger:      ttlog.NewCustomLogger(io.Discard, "", 0),
+	}
+}
+
+// HistorySize sets a number of finished jobs kept in memory. The default
+// size is used if it is not positive.
+func (handler *JobsHandler) HistorySize(size int) *JobsHandler {
+	if size <= 0 {
+		size = DefaultJobsHistorySize
+	}
+	handler.historySize = size
+	return handler
+}
+
+// Logger sets logger for JobsHandler.
+func (handler *JobsHandler) Logger(logger *ttlog.Logger) *JobsHandler {
+	handler.logger = logger
+	return handler
+}
+
+// addJob registers the job and removes the oldest finished jobs beyond
+// the history size. Running jobs are never removed.
+func (handler *JobsHandler) addJob(newJob *job) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	handler.lastID++
+	newJob.id = strconv.Itoa(handler.lastID)
+	handler.jobs = append(handler.jobs, newJob)
+
+	finished := 0
+	for _, job := range handler.jobs {
+		if !job.isRunning() {
+			finished++
+		}
+	}
+	jobs := handler.jobs[:0]
+	for _, job := range handler.jobs {
+		if finished > handler.historySize && !job.isRunning() {
+			finished--
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	handler.jobs = jobs
+}
+
+// findJob returns the job with the id.
+func (handler *JobsHandler) findJob(id string) (*job, *commandError) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+	for _, job := range handler.jobs {
+		if job.id == id {
+			return job, nil
+		}
+	}
+	return nil, &commandError{
+		status: http.StatusNotFound,
+		code:   errCodeNotFound,
+		msg:    fmt.Sprintf("job %s is not found", id),
+	}
+}
+
+// run executes the command of the job and sets its final state.
+func (handler *JobsHandler) run(ctx context.Context, job *job) {
+	defer job.cancel()
+
+	cmd := exec.CommandContext(ctx, handler.commands.cmdPath,
+		handler.commands.commandArgs(&job.cmd)...)
+	cmd.Stdout = job
+	cmd.Stderr = job
+	err := cmd.Run()
+
+	state, exitCode, errMsg := jobStateSucceeded, 0, ""
+	if err != nil {
+		state, exitCode, errMsg = jobStateFailed, -1, err.Error()
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			exitCode = exitErr.ExitCode()
+		}
+		switch ctx.Err() {
+		case context.Canceled:
+			state, errMsg = jobStateCanceled, "job is canceled"
+		case context.DeadlineExceeded:
+			errMsg = "job timeout is exceeded"
+		}
+	}
+	job.finish(state, exitCode, errMsg)
+	handler.logger.Printf("Job %s (%s) is finished: %s", job.id, job.cmd.Name, state)
+}
+
+// createJob checks the requested command and starts a job.
+func (handler *JobsHandler) createJob(body io.Reader) (*job, *commandError) {
+	invalidErr := func(err error) *commandError {
+		return &commandError{
+			status: http.StatusBadRequest,
+			code:   errCodeInvalidRequest,
+			msg:    err.Error(),
+		}
+	}
+
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, invalidErr(fmt.Errorf("failed to read request body: %s", err))
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var jobReq jobRequestJSON
+	if err := decoder.Decode(&jobReq); err != nil {
+		return nil, invalidErr(err)
+	}
+	if jobReq.Timeout < 0 {
+		return nil, invalidErr(fmt.Errorf("timeout must not be negative"))
+	}
+
+	cmd := command{Name: jobReq.Name, Params: jobReq.Params, Environment: jobReq.Environment}
+	if cmdErr := handler.commands.checkCommand(&cmd); cmdErr != nil {
+		return nil, cmdErr
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if jobReq.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(),
+			time.Duration(jobReq.Timeout)*time.Second)
+	}
+	newJob := &job{
+		cmd:         cmd,
+		created:     time.Now(),
+		cancel:      cancel,
+		state:       jobStateRunning,
+		updated:     make(chan struct{}),
+		outputLimit: handler.outputLimit,
+	}
+	handler.addJob(newJob)
+	go handler.run(ctx, newJob)
+	return newJob, nil
+}
+
+// listJobs returns all jobs without their output.
+func (handler *JobsHandler) listJobs() *jobsJSON {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+	res := jobsJSON{Jobs: make([]jobJSON, 0, len(handler.jobs))}
+	for _, job := range handler.jobs {
+		res.Jobs = append(res.Jobs, job.toJSON(false))
+	}
+	return &res
+}
+
+// CancelAll cancels all running jobs.
+func (handler *JobsHandler) CancelAll() {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+	for _, job := range handler.jobs {
+		job.cancel()
+	}
+}
+
+// writeEvent writes a Server-Sent Event. Each line of the data is sent
+// in a separate data field.
+func writeEvent(wr io.Writer, event, data string) error {
+	var buf strings.Builder
+	buf.WriteString("event: " + event + "\n")
+	for _, line := range strings.Split(data, "\n") {
+		buf.WriteString("data: " + line + "\n")
+	}
+	buf.WriteString("\n")
+	_, err := io.WriteString(wr, buf.String())
+	return err
+}
+
+// streamLog sends the job output as Server-Sent Events until the job is
+// finished or the client is disconnected. Each complete line of
+// the output is sent as an "output" event, the final state of the job is
+// sent as an "end" event.
+func (handler *JobsHandler) streamLog(wr http.ResponseWriter, req *http.Request, job *job) {
+	flusher := wr.(http.Flusher)
+	wr.Header().Set("Content-Type", "text/event-stream")
+	wr.Header().Set("Cache-Control", "no-cache")
+	wr.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// sent is a size of the sent output including the dropped one.
+	sent := 0
+	for {
+		job.mutex.Lock()
+		skipped := 0
+		if sent < job.dropped {
+			skipped, sent = job.dropped-sent, job.dropped
+		}
+		output := job.output[sent-job.dropped:]
+		updated := job.updated
+		running := job.state == jobStateRunning
+		job.mutex.Unlock()
+
+		if skipped > 0 {
+			if err := writeEvent(wr, "output", droppedMessage(skipped)); err != nil {
+				return
+			}
+		}
+
+		// Only complete lines are sent until the job is finished.
+		if end := bytes.LastIndexByte(output, '\n'); end >= 0 || !running {
+			chunk := output
+			if running {
+				chunk = output[:end+1]
+			}
+			sent += len(chunk)
+			text := strings.TrimSuffix(string(chunk), "\n")
+			if len(chunk) > 0 {
+				if err := writeEvent(wr, "output", text); err != nil {
+					return
+				}
+			}
+		}
+
+		if !running {
+			state, _ := json.Marshal(job.toJSON(false))
+			writeEvent(wr, "end", string(state))
+			flusher.Flush()
+			return
+		}
+		flusher.Flush()
+
+		select {
+		case <-updated:
+		case <-req.Context().Done():
+			return
+		}
+	}
+}
+
+// route handles the request and returns a status and a result. The log
+// stream is written by route itself, in this case the result is nil.
+func (handler *JobsHandler) route(wr http.ResponseWriter,
+	req *http.Request) (int, interface{}, *commandError) {
+	methodErr := func(allowed string) *commandError {
+		return &commandError{
+			status: http.StatusMethodNotAllowed,
+			code:   errCodeMethodNotAllowed,
+			msg:    fmt.Sprintf("method %s is not allowed, use %s", req.Method, allowed),
+		}
+	}
+
+	path := strings.Trim(strings.TrimPrefix(req.URL.Path, JobsPath), "/")
+	parts := []string{}
+	if path != "" {
+		parts = strings.Split(path, "/")
+	}
+
+	switch len(parts) {
+	case 0:
+		switch req.Method {
+		case http.MethodGet:
+			return http.StatusOK, handler.listJobs(), nil
+		case http.MethodPost:
+			job, err := handler.createJob(req.Body)
+			if err != nil {
+				return 0, nil, err
+			}
+			wr.Header().Set("Location", JobsPath+"/"+job.id)
+			res := job.toJSON(false)
+			return http.StatusAccepted, &res, nil
+		}
+		return 0, nil, methodErr(http.MethodGet + " or " + http.MethodPost)
+	case 1:
+		if req.Method != http.MethodGet {
+			return 0, nil, methodErr(http.MethodGet)
+		}
+		job, err := handler.findJob(parts[0])
+		if err != nil {
+			return 0, nil, err
+		}
+		res := job.toJSON(true)
+		return http.StatusOK, &res, nil
+	case 2:
+		job, err := handler.findJob(parts[0])
+		if err != nil {
+			return 0, nil, err
+		}
+		switch parts[1] {
+		case "log":
+			if req.Method != http.MethodGet {
+				return 0, nil, methodErr(http.MethodGet)
+			}
+			if _, ok := wr.(http.Flusher); !ok {
+				return 0, nil, &commandError{
+					status: http.StatusInternalServerError,
+					code:   errCodeStreamUnsupported,
+					msg:    "streaming is not supported",
+				}
+			}
+			handler.streamLog(wr, req, job)
+			return http.StatusOK, nil, nil
+		case "cancel":
+			if req.Method != http.MethodPost {
+				return 0, nil, methodErr(http.MethodPost)
+			}
+			if !job.isRunning() {
+				return 0, nil, &commandError{
+					status: http.StatusConflict,
+					code:   errCodeJobFinished,
+					msg:    fmt.Sprintf("job %s is already finished", job.id),
+				}
+			}
+			job.cancel()
+			res := job.toJSON(false)
+			return http.StatusAccepted, &res, nil
+		}
+	}
+
+	return 0, nil, &commandError{
+		status: http.StatusNotFound,
+		code:   errCodeNotFound,
+		msg:    fmt.Sprintf("resource %s is not found", req.URL.Path),
+	}
+}
+
+// ServeHTTP handles requests to the jobs resource.
+func (handler *JobsHandler) ServeHTTP(wr http.ResponseWriter, req *http.Request) {
+	status, res, cmdErr := handler.route(wr, req)
+	if cmdErr != nil {
+		status = cmdErr.status
+		res = &errorResult{Err: cmdErr.Error(), Code: cmdErr.code}
+	}
+
+	clientIpMsg, err := getClientIP(req)
+	if err != nil {
+		clientIpMsg = err.Error()
+	}
+	handler.logger.Printf("Client IP: %s; Request: %s %s; Status: %d",
+		clientIpMsg, req.Method, req.URL.Path, status)
+
+	if res == nil {
+		// The log stream is already written.
+		return
+	}
+	wr.Header().Set("Content-Type", "application/json")
+	wr.WriteHeader(status)
+	if err := json.NewEncoder(wr).Encode(res); err != nil {
+		handler.logger.Printf("An error occurred while encoding the response: \"%v\"\n", err)
+	}
+}
@@ -0,0 +1,211 @@
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testJobScript prints its arguments, "start" blocks and "stop" fails.
+const testJobScript = `#!/bin/sh
+echo "$@"
+echo line2
+if [ "$1" = start ]; then exec sleep 60; fi
+if [ "$1" = stop ]; then exit 3; fi
+`
+
+func newTestJobsHandler(t *testing.T) *JobsHandler {
+	cmdPath := filepath.Join(t.TempDir(), "tt")
+	require.NoError(t, os.WriteFile(cmdPath, []byte(testJobScript), 0755))
+	handler := NewJobsHandler(NewDaemonHandler(cmdPath).
+		InstanceNames(func(env string) ([]string, error) {
+			return []string{"app"}, nil
+		}))
+	t.Cleanup(handler.CancelAll)
+	return handler
+}
+
+// postJob creates a job and returns its description.
+func postJob(t *testing.T, handler *JobsHandler, body string) jobJSON {
+	res := serve(handler, httptest.NewRequest("POST", "/v1/jobs", strings.NewReader(body)))
+	require.Equal(t, http.StatusAccepted, res.Code, res.Body.String())
+	var job jobJSON
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &job))
+	assert.Equal(t, "/v1/jobs/"+job.ID, res.Header().Get("Location"))
+	return job
+}
+
+// waitJob waits for the job to be finished and returns its description.
+func waitJob(t *testing.T, handler *JobsHandler, id string) jobJSON {
+	var job jobJSON
+	require.Eventually(t, func() bool {
+		res := serve(handler, httptest.NewRequest("GET", "/v1/jobs/"+id, nil))
+		require.Equal(t, http.StatusOK, res.Code)
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &job))
+		return job.State != jobStateRunning
+	}, 10*time.Second, 10*time.Millisecond)
+	return job
+}
+
+func TestJobsHandler(t *testing.T) {
+	handler := newTestJobsHandler(t)
+
+	job := postJob(t, handler, `{"command_name": "status", "params": ["app"]}`)
+	assert.Equal(t, "1", job.ID)
+	assert.Equal(t, "status", job.Name)
+	assert.Equal(t, []string{"app"}, job.Params)
+
+	job = waitJob(t, handler, job.ID)
+	assert.Equal(t, jobStateSucceeded, job.State)
+	require.NotNil(t, job.ExitCode)
+	assert.Equal(t, 0, *job.ExitCode)
+	require.NotNil(t, job.Output)
+	assert.Equal(t, "status app\nline2\n", *job.Output)
+	assert.NotNil(t, job.Finished)
+
+	job = waitJob(t, handler, postJob(t, handler, `{"command_name": "stop"}`).ID)
+	assert.Equal(t, jobStateFailed, job.State)
+	assert.Equal(t, 3, *job.ExitCode)
+	assert.Equal(t, "exit status 3", job.Err)
+
+	res := serve(handler, httptest.NewRequest("GET", "/v1/jobs", nil))
+	require.Equal(t, http.StatusOK, res.Code)
+	var list jobsJSON
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &list))
+	require.Len(t, list.Jobs, 2)
+	assert.Equal(t, "1", list.Jobs[0].ID)
+	assert.Nil(t, list.Jobs[0].Output)
+}
+
+func TestJobsHandler_cancel(t *testing.T) {
+	handler := newTestJobsHandler(t)
+
+	job := postJob(t, handler, `{"command_name": "start"}`)
+	res := serve(handler, httptest.NewRequest("POST", "/v1/jobs/"+job.ID+"/cancel", nil))
+	assert.Equal(t, http.StatusAccepted, res.Code)
+
+	job = waitJob(t, handler, job.ID)
+	assert.Equal(t, jobStateCanceled, job.State)
+	assert.Equal(t, "job is canceled", job.Err)
+
+	res = serve(handler, httptest.NewRequest("POST", "/v1/jobs/"+job.ID+"/cancel", nil))
+	assert.Equal(t, http.StatusConflict, res.Code)
+	assert.Equal(t, `{"err":"job 1 is already finished","code":"job_finished"}`+"\n",
+		res.Body.String())
+
+	job = waitJob(t, handler,
+		postJob(t, handler, `{"command_name": "start", "timeout": 1}`).ID)
+	assert.Equal(t, jobStateFailed, job.State)
+	assert.Equal(t, "job timeout is exceeded", job.Err)
+}
+
+func TestJobsHandler_history(t *testing.T) {
+	handler := newTestJobsHandler(t).HistorySize(2)
+
+	running := postJob(t, handler, `{"command_name": "start"}`)
+	for i := 0; i < 3; i++ {
+		waitJob(t, handler, postJob(t, handler, `{"command_name": "status"}`).ID)
+	}
+	postJob(t, handler, `{"command_name": "status"}`)
+
+	// Running jobs and two last finished jobs are kept.
+	ids := []string{}
+	for _, job := range handler.listJobs().Jobs {
+		ids = append(ids, job.ID)
+	}
+	assert.Equal(t, []string{running.ID, "3", "4", "5"}, ids)
+}
+
+func TestJobsHandler_errors(t *testing.T) {
+	handler := newTestJobsHandler(t)
+
+	cases := []struct {
+		method   string
+		path     string
+		body     string
+		status   int
+		expected string
+	}{
+		{"POST", "/v1/jobs", `{"command_name": "version"}`, http.StatusForbidden,
+			`{"err":"command \"version\" is not allowed","code":"command_not_allowed"}`},
+		{"POST", "/v1/jobs", `{"command_name": "status", "params": ["other"]}`,
+			http.StatusBadRequest,
+			`{"err":"instance \"other\" is not enabled","code":"unknown_instance"}`},
+		{"POST", "/v1/jobs", `{"command": "status"}`, http.StatusBadRequest,
+			`{"err":"json: unknown field \"command\"","code":"invalid_request"}`},
+		{"POST", "/v1/jobs", `{"command_name": "status", "timeout": -1}`,
+			http.StatusBadRequest,
+			`{"err":"timeout must not be negative","code":"invalid_request"}`},
+		{"DELETE", "/v1/jobs", "", http.StatusMethodNotAllowed,
+			`{"err":"method DELETE is not allowed, use GET or POST",` +
+				`"code":"method_not_allowed"}`},
+		{"GET", "/v1/jobs/42", "", http.StatusNotFound,
+			`{"err":"job 42 is not found","code":"not_found"}`},
+		{"POST", "/v1/jobs/42/cancel", "", http.StatusNotFound,
+			`{"err":"job 42 is not found","code":"not_found"}`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.method+" "+tc.path+" "+tc.body, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			res := serve(handler, req)
+			assert.Equal(t, tc.status, res.Code)
+			assert.Equal(t, tc.expected+"\n", res.Body.String())
+		})
+	}
+}
+
+func TestJobsHandler_log(t *testing.T) {
+	handler := newTestJobsHandler(t)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	job := postJob(t, handler, `{"command_name": "status", "params": ["app"]}`)
+	res, err := http.Get(server.URL + "/v1/jobs/" + job.ID + "/log")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	// The stream is finished with the end event.
+	body, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	events := strings.Split(strings.TrimSuffix(string(body), "\n\n"), "\n\n")
+	require.NotEmpty(t, events)
+	assert.Contains(t, string(body), "data: status app\n")
+	assert.Contains(t, string(body), "data: line2\n")
+	end := events[len(events)-1]
+	require.True(t, strings.HasPrefix(end, "event: end\ndata: "), end)
+	var state jobJSON
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(end,
+		"event: end\ndata: ")), &state))
+	assert.Equal(t, jobStateSucceeded, state.State)
+}
+
+func TestJob_outputLimit(t *testing.T) {
+	job := &job{state: jobStateRunning, updated: make(chan struct{}), outputLimit: 16}
+	job.Write([]byte("line1\nline2\n"))
+	assert.Nil(t, job.toJSON(false).Output)
+	assert.Equal(t, "line1\nline2\n", *job.toJSON(true).Output)
+
+	// The oldest half of the output is dropped till the line end.
+	job.Write([]byte("line3\nline4\n"))
+	assert.Equal(t, "... 18 bytes of the output are dropped ...\nline4\n",
+		*job.toJSON(true).Output)
+	job.finish(jobStateSucceeded, 0, "")
+
+	handler := NewJobsHandler(NewDaemonHandler("tt"))
+	res := httptest.NewRecorder()
+	handler.streamLog(res, httptest.NewRequest("GET", "/v1/jobs/1/log", nil), job)
+	assert.True(t, strings.HasPrefix(res.Body.String(),
+		"event: output\ndata: ... 18 bytes of the output are dropped ...\n\n"+
+			"event: output\ndata: line4\n\nevent: end\n"), res.Body.String())
+}
@@ -0,0 +1,300 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tarantool/tt/cli/process_utils"
+	"github.com/tarantool/tt/cli/running"
+)
+
+// MetricsPath is a path of the metrics in the Prometheus text format.
+const MetricsPath = "/metrics"
+
+// latencyBuckets are upper bounds of the request latency histogram buckets
+// in seconds.
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// requestKey identifies a series of the requests counter.
+type requestKey struct {
+	handler string
+	method  string
+	code    int
+}
+
+// latencyKey identifies a series of the latency histogram.
+type latencyKey struct {
+	handler string
+	method  string
+}
+
+// latencyHistogram is a cumulative histogram of request latencies.
+type latencyHistogram struct {
+	// buckets are counts of requests with a latency less than or equal to
+	// the corresponding upper bound of latencyBuckets.
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+// Metrics collects metrics of the HTTP API and writes them with metrics
+// of the environment instances in the Prometheus text format.
+type Metrics struct {
+	manager InstanceManager
+	// now returns the current time, it is used to get the PID file age.
+	now func() time.Time
+
+	mutex     sync.Mutex
+	requests  map[requestKey]uint64
+	latencies map[latencyKey]*latencyHistogram
+}
+
+// NewMetrics creates Metrics. Instance metrics are not written if
+// the manager is nil.
+func NewMetrics(manager InstanceManager) *Metrics {
+	return &Metrics{
+		manager:   manager,
+		now:       time.Now,
+		requests:  map[requestKey]uint64{},
+		latencies: map[latencyKey]*latencyHistogram{},
+	}
+}
+
+// observe records the request.
+func (metrics *Metrics) observe(handler, method string, code int, duration time.Duration) {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	metrics.requests[requestKey{handler, method, code}]++
+
+	key := latencyKey{handler, method}
+	histogram, ok := metrics.latencies[key]
+	if !ok {
+		histogram = &latencyHistogram{buckets: make([]uint64, len(latencyBuckets))}
+		metrics.latencies[key] = histogram
+	}
+	seconds := duration.Seconds()
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			histogram.buckets[i]++
+		}
+	}
+	histogram.count++
+	histogram.sum += seconds
+}
+
+// statusRecorder remembers a status of the response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader remembers the status and writes it.
+func (recorder *statusRecorder) WriteHeader(status int) {
+	recorder.status = status
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+// Flush sends buffered data to the client, it is required for log streams.
+func (recorder *statusRecorder) Flush() {
+	if flusher, ok := recorder.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Handler returns a handler recording requests to the next handler with
+// the handler label.
+func (metrics *Metrics) Handler(handler string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(wr http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: wr, status: http.StatusOK}
+		next.ServeHTTP(recorder, req)
+
+		// Methods are limited to keep the number of label values bounded.
+		method := req.Method
+		switch method {
+		case http.MethodGet, http.MethodPost, http.MethodHead, http.MethodPut,
+			http.MethodDelete, http.MethodPatch, http.MethodOptions:
+		default:
+			method = "OTHER"
+		}
+		metrics.observe(handler, method, recorder.status, time.Since(start))
+	})
+}
+
+// escapeLabel escapes a label value of the Prometheus text format.
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// formatFloat formats a sample value of the Prometheus text format.
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// metricsWriter writes metric families in the Prometheus text format.
+type metricsWriter struct {
+	builder strings.Builder
+}
+
+// family writes a header of the metric family.
+func (writer *metricsWriter) family(name, metricType, help string) {
+	fmt.Fprintf(&writer.builder, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+// sample writes a sample. Labels are pairs of names and values.
+func (writer *metricsWriter) sample(name string, value string, labels ...string) {
+	writer.builder.WriteString(name)
+	if len(labels) > 0 {
+		pairs := make([]string, 0, len(labels)/2)
+		for i := 0; i+1 < len(labels); i += 2 {
+			pairs = append(pairs, fmt.Sprintf(`%s="%s"`, labels[i], escapeLabel(labels[i+1])))
+		}
+		writer.builder.WriteString("{" + strings.Join(pairs, ",") + "}")
+	}
+	writer.builder.WriteString(" " + value + "\n")
+}
+
+// instanceSample is a sample of an instance metric.
+type instanceSample struct {
+	labels []string
+	value  float64
+}
+
+// writeInstances writes metrics of the instances of the environments.
+func (metrics *Metrics) writeInstances(writer *metricsWriter) {
+	var available, up, restarts, pidAge, logSize []instanceSample
+	for _, env := range metrics.manager.Environments() {
+		instances, err := metrics.manager.Instances(env.Name)
+		envAvailable := 1.0
+		if err != nil {
+			envAvailable = 0
+		}
+		available = append(available,
+			instanceSample{[]string{"environment", env.Name}, envAvailable})
+
+		for _, inst := range instances {
+			labels := []string{"environment", env.Name, "app", inst.AppName,
+				"instance", inst.InstName}
+
+			state := 0.0
+			if running.Status(&inst).Code == process_utils.ProcessRunningCode {
+				state = 1
+			}
+			up = append(up, instanceSample{labels, state})
+
+			if count, err := running.GetRestarts(&inst); err == nil {
+				restarts = append(restarts, instanceSample{labels, float64(count)})
+			}
+			if info, err := os.Stat(inst.PIDFile); err == nil {
+				age := metrics.now().Sub(info.ModTime()).Seconds()
+				pidAge = append(pidAge, instanceSample{labels, age})
+			}
+			if info, err := os.Stat(inst.Log); err == nil && !info.IsDir() {
+				logSize = append(logSize, instanceSample{labels, float64(info.Size())})
+			}
+		}
+	}
+
+	families := []struct {
+		name       string
+		metricType string
+		help       string
+		samples    []instanceSample
+	}{
+		{"tt_daemon_instances_available", "gauge",
+			"Whether enabled instances of the environment could be found.", available},
+		{"tt_instance_up", "gauge", "Whether the instance is running.", up},
+		{"tt_instance_watchdog_restarts", "gauge",
+			"Number of restarts of the instance by the watchdog since its start.", restarts},
+		{"tt_instance_pid_file_age_seconds", "gauge",
+			"Time since the modification of the instance PID file.", pidAge},
+		{"tt_instance_log_file_size_bytes", "gauge", "Size of the instance log file.", logSize},
+	}
+	for _, family := range families {
+		writer.family(family.name, family.metricType, family.help)
+		for _, sample := range family.samples {
+			writer.sample(family.name, formatFloat(sample.value), sample.labels...)
+		}
+	}
+}
+
+// writeRequests writes metrics of the HTTP API requests.
+func (metrics *Metrics) writeRequests(writer *metricsWriter) {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	requestKeys := make([]requestKey, 0, len(metrics.requests))
+	for key := range metrics.requests {
+		requestKeys = append(requestKeys, key)
+	}
+	sort.Slice(requestKeys, func(i, j int) bool {
+		a, b := requestKeys[i], requestKeys[j]
+		if a.handler != b.handler {
+			return a.handler < b.handler
+		}
+		if a.method != b.method {
+			return a.method < b.method
+		}
+		return a.code < b.code
+	})
+	writer.family("tt_daemon_http_requests_total", "counter",
+		"Number of HTTP API requests.")
+	for _, key := range requestKeys {
+		writer.sample("tt_daemon_http_requests_total",
+			strconv.FormatUint(metrics.requests[key], 10),
+			"handler", key.handler, "method", key.method, "code", strconv.Itoa(key.code))
+	}
+
+	latencyKeys := make([]latencyKey, 0, len(metrics.latencies))
+	for key := range metrics.latencies {
+		latencyKeys = append(latencyKeys, key)
+	}
+	sort.Slice(latencyKeys, func(i, j int) bool {
+		a, b := latencyKeys[i], latencyKeys[j]
+		if a.handler != b.handler {
+			return a.handler < b.handler
+		}
+		return a.method < b.method
+	})
+	name := "tt_daemon_http_request_duration_seconds"
+	writer.family(name, "histogram", "Latency of HTTP API requests.")
+	for _, key := range latencyKeys {
+		histogram := metrics.latencies[key]
+		labels := []string{"handler", key.handler, "method", key.method}
+		for i, bound := range latencyBuckets {
+			writer.sample(name+"_bucket", strconv.FormatUint(histogram.buckets[i], 10),
+				append(labels, "le", formatFloat(bound))...)
+		}
+		writer.sample(name+"_bucket", strconv.FormatUint(histogram.count, 10),
+			append(labels, "le", "+Inf")...)
+		writer.sample(name+"_sum", formatFloat(histogram.sum), labels...)
+		writer.sample(name+"_count", strconv.FormatUint(histogram.count, 10), labels...)
+	}
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format.
+func (metrics *Metrics) ServeHTTP(wr http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		wr.Header().Set("Allow", http.MethodGet)
+		http.Error(wr, http.StatusText(http.StatusMethodNotAllowed),
+			http.StatusMethodNotAllowed)
+		return
+	}
+
+	var writer metricsWriter
+	if metrics.manager != nil {
+		metrics.writeInstances(&writer)
+	}
+	metrics.writeRequests(&writer)
+
+	wr.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	io.WriteString(wr, writer.builder.String())
+}
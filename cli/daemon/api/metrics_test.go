@@ -0,0 +1,110 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/running"
+)
+
+func TestMetrics_instances(t *testing.T) {
+	stopped := newTestInstance(t, "app", "master")
+	runningInst := newTestInstance(t, "app", "replica")
+	startTestProcess(t, runningInst)
+	runningInst.RestartsFile = filepath.Join(runningInst.RunDir, "replica.restarts")
+	require.NoError(t, os.WriteFile(runningInst.RestartsFile, []byte("3"), 0644))
+	runningInst.Log = filepath.Join(runningInst.RunDir, "replica.log")
+	require.NoError(t, os.WriteFile(runningInst.Log, []byte("0123456789"), 0644))
+
+	pidTime := time.Unix(1000000, 0)
+	require.NoError(t, os.Chtimes(runningInst.PIDFile, pidTime, pidTime))
+
+	metrics := NewMetrics(&fakeManager{
+		instances: []running.InstanceCtx{stopped, runningInst},
+	})
+	metrics.now = func() time.Time { return pidTime.Add(90 * time.Second) }
+
+	res := serve(metrics, httptest.NewRequest("GET", "/metrics", nil))
+	require.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", res.Header().Get("Content-Type"))
+	body := res.Body.String()
+	master := `{environment="default",app="app",instance="master"}`
+	replica := `{environment="default",app="app",instance="replica"}`
+	for _, expected := range []string{
+		"# TYPE tt_daemon_instances_available gauge\n" +
+			`tt_daemon_instances_available{environment="default"} 1` + "\n",
+		"# TYPE tt_instance_up gauge\n" +
+			"tt_instance_up" + master + " 0\n" +
+			"tt_instance_up" + replica + " 1\n",
+		"tt_instance_watchdog_restarts" + master + " 0\n",
+		"tt_instance_watchdog_restarts" + replica + " 3\n",
+		"tt_instance_pid_file_age_seconds" + replica + " 90\n",
+		"tt_instance_log_file_size_bytes" + replica + " 10\n",
+	} {
+		assert.Contains(t, body, expected)
+	}
+	assert.NotContains(t, body, "tt_instance_pid_file_age_seconds"+master)
+
+	metrics = NewMetrics(&fakeManager{err: fmt.Errorf("tt.yaml not found")})
+	res = serve(metrics, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Contains(t, res.Body.String(),
+		`tt_daemon_instances_available{environment="default"} 0`+"\n")
+
+	res = serve(metrics, httptest.NewRequest("POST", "/metrics", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, res.Code)
+}
+
+func TestMetrics_requests(t *testing.T) {
+	metrics := NewMetrics(nil)
+	handler := metrics.Handler("/v1/instances",
+		http.HandlerFunc(func(wr http.ResponseWriter, req *http.Request) {
+			if req.Method != http.MethodGet {
+				wr.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		}))
+	serve(handler, httptest.NewRequest("GET", "/v1/instances", nil))
+	serve(handler, httptest.NewRequest("GET", "/v1/instances", nil))
+	serve(handler, httptest.NewRequest("POST", "/v1/instances", nil))
+	serve(handler, httptest.NewRequest("BREW", "/v1/instances", nil))
+	metrics.observe("/tarantool", "POST", http.StatusOK, 300*time.Millisecond)
+
+	body := serve(metrics, httptest.NewRequest("GET", "/metrics", nil)).Body.String()
+	assert.NotContains(t, body, "tt_instance_up")
+	for _, expected := range []string{
+		"# TYPE tt_daemon_http_requests_total counter\n" +
+			`tt_daemon_http_requests_total{handler="/tarantool",method="POST",code="200"} 1` +
+			"\n" +
+			`tt_daemon_http_requests_total{handler="/v1/instances",method="GET",code="200"} 2` +
+			"\n" +
+			`tt_daemon_http_requests_total{handler="/v1/instances",method="OTHER",code="405"} 1` +
+			"\n" +
+			`tt_daemon_http_requests_total{handler="/v1/instances",method="POST",code="405"} 1` +
+			"\n",
+		"# TYPE tt_daemon_http_request_duration_seconds histogram\n",
+		`tt_daemon_http_request_duration_seconds_bucket{handler="/tarantool",method="POST",` +
+			`le="0.25"} 0` + "\n",
+		`tt_daemon_http_request_duration_seconds_bucket{handler="/tarantool",method="POST",` +
+			`le="0.5"} 1` + "\n",
+		`tt_daemon_http_request_duration_seconds_bucket{handler="/tarantool",method="POST",` +
+			`le="+Inf"} 1` + "\n",
+		`tt_daemon_http_request_duration_seconds_sum{handler="/tarantool",method="POST"} 0.3` +
+			"\n",
+		`tt_daemon_http_request_duration_seconds_count{handler="/v1/instances",method="GET"} 2` +
+			"\n",
+	} {
+		assert.Contains(t, body, expected)
+	}
+}
+
+func TestEscapeLabel(t *testing.T) {
+	assert.Equal(t, `a\\b\"c\nd`, escapeLabel("a\\b\"c\nd"))
+	assert.False(t, strings.Contains(escapeLabel("a\nb"), "\n"))
+}
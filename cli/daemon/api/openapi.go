@@ -0,0 +1,31 @@
+package api
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// OpenAPIPath is a path of the OpenAPI document.
+const OpenAPIPath = "/v1/openapi.json"
+
+//go:embed openapi.json
+var openAPIDocument []byte
+
+// OpenAPIHandler serves the OpenAPI document of the HTTP API.
+type OpenAPIHandler struct{}
+
+// ServeHTTP writes the OpenAPI document.
+func (OpenAPIHandler) ServeHTTP(wr http.ResponseWriter, req *http.Request) {
+	wr.Header().Set("Content-Type", "application/json")
+	if req.Method != http.MethodGet {
+		wr.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(wr).Encode(&errorResult{
+			Err:  fmt.Sprintf("method %s is not allowed, use GET", req.Method),
+			Code: errCodeMethodNotAllowed,
+		})
+		return
+	}
+	wr.Write(openAPIDocument)
+}
@@ -12,11 +12,15 @@ import (
 
 // commandJSON describes the tt command sent using the HTTP API.
 type commandJSON struct {
-	// Name is a name of the command.
-	// Now available: start, stop, status, restart.
+	// Name is a name of the command. It should be in the allowlist,
+	// by default: start, stop, status, restart, logrotate.
 	Name string `json:"command_name"`
-	// Params are command parameters.
+	// Params are command parameters: a name of an enabled application
+	// or instance.
 	Params []string `json:"params"`
+	// Environment is a name of the environment to run the command in.
+	// It could be omitted if the daemon manages the only environment.
+	Environment string `json:"environment"`
 }
 
 // command describes the tt command.
@@ -25,6 +29,8 @@ type command struct {
 	Name string
 	// Params are command parameters.
 	Params []string
+	// Environment is a name of the environment.
+	Environment string
 }
 
 // parseCommand decodes JSON, checks the parameters
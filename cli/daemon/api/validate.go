@@ -0,0 +1,112 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// errCodeCommandNotAllowed is an error code of a command missing in
+	// the allowlist.
+	errCodeCommandNotAllowed = "command_not_allowed"
+	// errCodeInvalidParams is an error code of invalid command parameters.
+	errCodeInvalidParams = "invalid_params"
+	// errCodeUnknownInstance is an error code of a parameter that is not
+	// an enabled instance.
+	errCodeUnknownInstance = "unknown_instance"
+	// errCodeInstancesUnavailable is an error code of a failure to get
+	// enabled instances.
+	errCodeInstancesUnavailable = "instances_unavailable"
+)
+
+// DefaultAllowedCommands is a list of commands available via the HTTP API
+// by default.
+var DefaultAllowedCommands = []string{"start", "stop", "status", "restart", "logrotate"}
+
+// InstanceNamesFunc returns names of the enabled instances of the environment
+// that could be passed to commands: application names and "<app>:<instance>"
+// names.
+type InstanceNamesFunc func(env string) ([]string, error)
+
+// commandError describes a rejected command.
+type commandError struct {
+	// status is an HTTP status of the response.
+	status int
+	// code is an error code of the response.
+	code string
+	// msg is an error message.
+	msg string
+}
+
+// Error returns the error message.
+func (err *commandError) Error() string {
+	return err.msg
+}
+
+// checkCommand checks that the command is allowed and its parameters are
+// names of enabled instances. The environment of the command is set to
+// the resolved one.
+func (handler *DaemonHandler) checkCommand(cmd *command) *commandError {
+	if !handler.allowedCommands[cmd.Name] {
+		return &commandError{
+			status: http.StatusForbidden,
+			code:   errCodeCommandNotAllowed,
+			msg:    fmt.Sprintf("command %q is not allowed", cmd.Name),
+		}
+	}
+
+	env, envErr := resolveEnvironment(handler.environments, cmd.Environment)
+	if envErr != nil {
+		return envErr
+	}
+	cmd.Environment = env.Name
+
+	if len(cmd.Params) == 0 {
+		return nil
+	}
+	if len(cmd.Params) > 1 {
+		return &commandError{
+			status: http.StatusBadRequest,
+			code:   errCodeInvalidParams,
+			msg:    "only one instance name could be specified",
+		}
+	}
+	if strings.HasPrefix(cmd.Params[0], "-") {
+		return &commandError{
+			status: http.StatusBadRequest,
+			code:   errCodeInvalidParams,
+			msg:    fmt.Sprintf("flags are not allowed: %q", cmd.Params[0]),
+		}
+	}
+
+	names, err := handler.instanceNames(cmd.Environment)
+	if err != nil {
+		return &commandError{
+			status: http.StatusInternalServerError,
+			code:   errCodeInstancesUnavailable,
+			msg:    fmt.Sprintf("failed to get enabled instances: %s", err),
+		}
+	}
+	for _, name := range names {
+		if name == cmd.Params[0] {
+			return nil
+		}
+	}
+	return &commandError{
+		status: http.StatusBadRequest,
+		code:   errCodeUnknownInstance,
+		msg:    fmt.Sprintf("instance %q is not enabled", cmd.Params[0]),
+	}
+}
+
+// commandArgs returns arguments of tt to run the command in its environment.
+func (handler *DaemonHandler) commandArgs(cmd *command) []string {
+	args := []string{}
+	if env, err := resolveEnvironment(handler.environments, cmd.Environment); err == nil &&
+		env.ConfigPath != "" {
+		args = append(args, "--cfg", env.ConfigPath)
+	}
+	args = append(args, cmd.Name)
+	return append(args, cmd.Params...)
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDaemonHandler_checkCommand(t *testing.T) {
+	handler := NewDaemonHandler("echo").InstanceNames(func(env string) ([]string, error) {
+		return []string{"app", "app:master", "single"}, nil
+	})
+
+	cases := []struct {
+		cmd    command
+		status int
+		code   string
+		msg    string
+	}{
+		{command{Name: "status", Params: nil}, 0, "", ""},
+		{command{Name: "start", Params: []string{"app"}}, 0, "", ""},
+		{command{Name: "stop", Params: []string{"app:master"}}, 0, "", ""},
+		{command{Name: "restart", Params: []string{"single"}}, 0, "", ""},
+		{command{Name: "version", Params: nil}, http.StatusForbidden, errCodeCommandNotAllowed,
+			`command "version" is not allowed`},
+		{command{Name: "", Params: nil}, http.StatusForbidden, errCodeCommandNotAllowed,
+			`command "" is not allowed`},
+		{command{Name: "start", Params: []string{"app", "single"}}, http.StatusBadRequest,
+			errCodeInvalidParams, "only one instance name could be specified"},
+		{command{Name: "start", Params: []string{"-L"}}, http.StatusBadRequest,
+			errCodeInvalidParams, `flags are not allowed: "-L"`},
+		{command{Name: "start", Params: []string{"app:replica"}}, http.StatusBadRequest,
+			errCodeUnknownInstance, `instance "app:replica" is not enabled`},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprint(tc.cmd), func(t *testing.T) {
+			err := handler.checkCommand(&tc.cmd)
+			if tc.status == 0 {
+				assert.Nil(t, err)
+				return
+			}
+			if assert.NotNil(t, err) {
+				assert.Equal(t, tc.status, err.status)
+				assert.Equal(t, tc.code, err.code)
+				assert.Equal(t, tc.msg, err.Error())
+			}
+		})
+	}
+}
+
+func TestDaemonHandler_checkCommandNoInstances(t *testing.T) {
+	handler := NewDaemonHandler("echo").InstanceNames(func(env string) ([]string, error) {
+		return nil, fmt.Errorf("tt.yaml not found")
+	})
+	err := handler.checkCommand(&command{Name: "start", Params: []string{"app"}})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, http.StatusInternalServerError, err.status)
+		assert.Equal(t, errCodeInstancesUnavailable, err.code)
+		assert.Equal(t, "failed to get enabled instances: tt.yaml not found", err.Error())
+	}
+
+	// Parameters are rejected without the instances function.
+	err = NewDaemonHandler("echo").checkCommand(&command{Name: "start", Params: []string{"app"}})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, errCodeUnknownInstance, err.code)
+	}
+}
+
+func TestDaemonHandler_ServeHTTP(t *testing.T) {
+	handler := NewDaemonHandler("echo").
+		AllowedCommands([]string{"status", "logrotate"}).
+		InstanceNames(func(env string) ([]string, error) {
+			return []string{"app"}, nil
+		})
+
+	cases := []struct {
+		body     string
+		status   int
+		expected string
+	}{
+		{`{"command_name": "status", "params": ["app"]}`, http.StatusOK,
+			`{"res":"status app\n"}`},
+		{`{"command_name": "logrotate"}`, http.StatusOK, `{"res":"logrotate\n"}`},
+		{`{"command_name": "start", "params": ["app"]}`, http.StatusForbidden,
+			`{"err":"command \"start\" is not allowed","code":"command_not_allowed"}`},
+		{`{"command_name": "status", "params": ["other"]}`, http.StatusBadRequest,
+			`{"err":"instance \"other\" is not enabled","code":"unknown_instance"}`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.body, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/tarantool", strings.NewReader(tc.body))
+			res := serve(handler, req)
+			assert.Equal(t, tc.status, res.Code)
+			assert.Equal(t, tc.expected+"\n", res.Body.String())
+		})
+	}
+}
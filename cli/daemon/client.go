@@ -0,0 +1,68 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// ExecRequest describes a tt command sent to the daemon.
+type ExecRequest struct {
+	// Name is a name of the command.
+	Name string `json:"command_name"`
+	// Params are command parameters.
+	Params []string `json:"params"`
+	// Environment is a name of the environment to run the command in.
+	Environment string `json:"environment,omitempty"`
+}
+
+// execResponse describes a response of the daemon to the command.
+type execResponse struct {
+	Res  string `json:"res"`
+	Err  string `json:"err"`
+	Code string `json:"code"`
+}
+
+// Exec sends the command to the daemon listening on the unix socket and
+// returns the command output.
+func Exec(socketPath string, req ExecRequest) (string, error) {
+	if req.Params == nil {
+		req.Params = []string{}
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+
+	client := http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+	// The host is ignored, the connection is established with the socket.
+	res, err := client.Post("http://tt-daemon/tarantool", "application/json",
+		bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to send the command to the daemon: %s", err)
+	}
+	defer res.Body.Close()
+
+	var resJSON execResponse
+	if err := json.NewDecoder(res.Body).Decode(&resJSON); err != nil {
+		return "", fmt.Errorf("failed to decode the daemon response (status %d): %s",
+			res.StatusCode, err)
+	}
+	if resJSON.Err != "" {
+		if resJSON.Code != "" {
+			return resJSON.Res, fmt.Errorf("%s (%s)", resJSON.Err, resJSON.Code)
+		}
+		return resJSON.Res, fmt.Errorf("%s", resJSON.Err)
+	}
+	return resJSON.Res, nil
+}
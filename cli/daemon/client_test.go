@@ -0,0 +1,58 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveUnix serves the handler on a unix socket and returns the socket path.
+func serveUnix(t *testing.T, handler http.HandlerFunc) string {
+	socketPath := filepath.Join(t.TempDir(), "tt_daemon.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	srv := &http.Server{Handler: handler}
+	go srv.Serve(listener)
+	t.Cleanup(func() { srv.Close() })
+	return socketPath
+}
+
+func TestExec(t *testing.T) {
+	var received ExecRequest
+	socketPath := serveUnix(t, func(wr http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/tarantool", req.URL.Path)
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&received))
+		switch received.Name {
+		case "status":
+			wr.Write([]byte(`{"res":"   • app: NOT RUNNING\n"}`))
+		case "version":
+			wr.WriteHeader(http.StatusForbidden)
+			wr.Write([]byte(`{"err":"command \"version\" is not allowed",` +
+				`"code":"command_not_allowed"}`))
+		default:
+			wr.Write([]byte(`{"err":"exit status 1: failed"}`))
+		}
+	})
+
+	res, err := Exec(socketPath, ExecRequest{Name: "status", Params: []string{"app"},
+		Environment: "prod"})
+	require.NoError(t, err)
+	assert.Equal(t, "   • app: NOT RUNNING\n", res)
+	assert.Equal(t, ExecRequest{Name: "status", Params: []string{"app"},
+		Environment: "prod"}, received)
+
+	_, err = Exec(socketPath, ExecRequest{Name: "version"})
+	assert.EqualError(t, err, `command "version" is not allowed (command_not_allowed)`)
+	assert.Equal(t, []string{}, received.Params)
+
+	_, err = Exec(socketPath, ExecRequest{Name: "start"})
+	assert.EqualError(t, err, "exit status 1: failed")
+
+	_, err = Exec(filepath.Join(t.TempDir(), "missing.sock"), ExecRequest{Name: "status"})
+	assert.ErrorContains(t, err, "failed to send the command to the daemon")
+}
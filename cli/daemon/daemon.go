@@ -4,12 +4,19 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strconv"
+	"time"
 
+	"github.com/tarantool/tt/cli/cmdcontext"
 	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/daemon/api"
 	"github.com/tarantool/tt/cli/process_utils"
 	"github.com/tarantool/tt/cli/ttlog"
 )
 
+// DefaultUnixSocketMode is default permissions of the daemon unix socket.
+const DefaultUnixSocketMode os.FileMode = 0660
+
 // DaemonCtx contains information for running an daemon instance.
 type DaemonCtx struct {
 	// Port is a port number to be used for daemon http server.
@@ -34,18 +41,71 @@ type DaemonCtx struct {
 	// ListenInterface is a network interface the IP address
 	// should be found on to bind http server socket.
 	ListenInterface string
+	// AuthToken is a bearer token required in requests.
+	AuthToken string
+	// AuthHMACKey is a key to check HMAC signatures of requests.
+	AuthHMACKey string
+	// AllowedIPs is a list of IP addresses and CIDR networks allowed
+	// to send requests.
+	AllowedIPs []string
+	// TLSCertFile is a path to the server TLS certificate.
+	TLSCertFile string
+	// TLSKeyFile is a path to the server TLS private key.
+	TLSKeyFile string
+	// TLSCAFile is a path to CA certificates to verify client certificates.
+	TLSCAFile string
+	// AllowedCommands is a list of tt commands available via the HTTP API.
+	AllowedCommands []string
+	// JobsHistorySize is a number of finished jobs kept in memory.
+	JobsHistorySize int
+	// Environments maps names of the managed tt environments to paths of
+	// their configurations.
+	Environments map[string]string
+	// Supervisor enables starting and restarting of enabled instances.
+	Supervisor bool
+	// SupervisorInterval is a period of instance checks by the supervisor.
+	SupervisorInterval time.Duration
+	// UnixSocket is a path to a unix socket the daemon listens on.
+	UnixSocket string
+	// UnixSocketMode is permissions of the unix socket file.
+	UnixSocketMode os.FileMode
+	// CliOpts are tt options used to find enabled instances.
+	CliOpts *config.CliOpts
+	// CmdCtx is a tt command context used to find enabled instances.
+	CmdCtx *cmdcontext.CmdCtx
 }
 
 // NewDaemonCtx creates the DaemonCtx context.
 func NewDaemonCtx(opts *config.DaemonOpts) *DaemonCtx {
-	return &DaemonCtx{
+	daemonCtx := &DaemonCtx{
 		PIDFile:       filepath.Join(opts.RunDir, opts.PIDFile),
 		Port:          opts.Port,
 		LogPath:       filepath.Join(opts.LogDir, opts.LogFile),
 		LogMaxAge:     opts.LogMaxAge,
 		LogMaxBackups: opts.LogMaxBackups,
 		LogMaxSize:    opts.LogMaxSize,
+
+		ListenInterface: opts.ListenInterface,
+		AuthToken:       opts.AuthToken,
+		AuthHMACKey:     opts.AuthHMACKey,
+		AllowedIPs:      opts.AllowedIPs,
+		TLSCertFile:     opts.TLSCertFile,
+		TLSKeyFile:      opts.TLSKeyFile,
+		TLSCAFile:       opts.TLSCAFile,
+		AllowedCommands: opts.AllowedCommands,
+		JobsHistorySize: opts.JobsHistorySize,
+		Environments:    opts.Environments,
+
+		Supervisor:         opts.Supervisor,
+		SupervisorInterval: time.Duration(opts.SupervisorInterval) * time.Second,
+
+		UnixSocket:     opts.UnixSocket,
+		UnixSocketMode: DefaultUnixSocketMode,
 	}
+	if mode, err := strconv.ParseUint(opts.UnixSocketMode, 8, 32); err == nil {
+		daemonCtx.UnixSocketMode = os.FileMode(mode)
+	}
+	return daemonCtx
 }
 
 // RunHTTPServerOnBackground starts http daemon process.
@@ -57,9 +117,24 @@ func RunHTTPServerOnBackground(daemonCtx *DaemonCtx) error {
 		MaxAge:     daemonCtx.LogMaxAge,
 	}
 
+	manager := &instanceManager{daemonCtx: daemonCtx}
+	httpServer := NewHTTPServer(daemonCtx.ListenInterface, daemonCtx.Port).
+		Auth(api.AuthOpts{
+			Token:      daemonCtx.AuthToken,
+			HMACKey:    daemonCtx.AuthHMACKey,
+			AllowedIPs: daemonCtx.AllowedIPs,
+		}).
+		TLS(daemonCtx.TLSCertFile, daemonCtx.TLSKeyFile, daemonCtx.TLSCAFile).
+		Commands(daemonCtx.AllowedCommands, manager).
+		JobsHistorySize(daemonCtx.JobsHistorySize).
+		UnixSocket(daemonCtx.UnixSocket, daemonCtx.UnixSocketMode)
+	if daemonCtx.Supervisor {
+		httpServer.Supervise(NewSupervisor(manager).Interval(daemonCtx.SupervisorInterval))
+	}
+
 	args := []string{"daemon", "start"}
-	proc := NewProcess(NewHTTPServer(daemonCtx.ListenInterface, daemonCtx.Port),
-		daemonCtx.PIDFile, logOpts).CmdPath(os.Args[0]).CmdArgs(args)
+	proc := NewProcess(httpServer, daemonCtx.PIDFile, logOpts).
+		CmdPath(os.Args[0]).CmdArgs(args)
 
 	if err := proc.Start(); err != nil {
 		return err
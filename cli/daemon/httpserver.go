@@ -2,10 +2,16 @@ package daemon
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/tarantool/tt/cli/daemon/api"
@@ -30,6 +36,34 @@ type HTTPServer struct {
 	timeout time.Duration
 	// logger is  a log file the HTTP server will write to.
 	logger *ttlog.Logger
+	// authOpts are options of the request authentication.
+	authOpts api.AuthOpts
+	// certFile is a path to the server TLS certificate. The server
+	// uses plain HTTP if it is empty.
+	certFile string
+	// keyFile is a path to the server TLS private key.
+	keyFile string
+	// caFile is a path to the CA certificates to verify client
+	// certificates. Client certificates are not required if it is empty.
+	caFile string
+	// allowedCommands is a list of commands available via the HTTP API.
+	// The default list is used if it is empty.
+	allowedCommands []string
+	// manager provides instances of the environments.
+	manager api.InstanceManager
+	// jobsHistorySize is a number of finished jobs kept in memory.
+	jobsHistorySize int
+	// jobsHandler runs commands as background jobs.
+	jobsHandler *api.JobsHandler
+	// supervisor keeps enabled instances running if it is set.
+	supervisor *Supervisor
+	// socketPath is a path to a unix socket the server listens on besides
+	// the TCP port. The socket is not used if it is empty.
+	socketPath string
+	// socketMode is permissions of the unix socket file.
+	socketMode os.FileMode
+	// socketSrv is http.Server instance serving the unix socket.
+	socketSrv *http.Server
 }
 
 // listenIP discovers IP address on the specified interface.
@@ -97,6 +131,129 @@ func (httpServer *HTTPServer) Timeout(timeout time.Duration) *HTTPServer {
 	return httpServer
 }
 
+// Auth sets options of the request authentication.
+func (httpServer *HTTPServer) Auth(opts api.AuthOpts) *HTTPServer {
+	httpServer.authOpts = opts
+	return httpServer
+}
+
+// Commands sets commands available via the HTTP API and a manager of
+// the environments instances.
+func (httpServer *HTTPServer) Commands(allowedCommands []string,
+	manager api.InstanceManager) *HTTPServer {
+	httpServer.allowedCommands = allowedCommands
+	httpServer.manager = manager
+	return httpServer
+}
+
+// JobsHistorySize sets a number of finished jobs kept in memory.
+func (httpServer *HTTPServer) JobsHistorySize(size int) *HTTPServer {
+	httpServer.jobsHistorySize = size
+	return httpServer
+}
+
+// Supervise sets a supervisor started and stopped with the HTTP server.
+func (httpServer *HTTPServer) Supervise(supervisor *Supervisor) *HTTPServer {
+	httpServer.supervisor = supervisor
+	return httpServer
+}
+
+// UnixSocket sets a path to a unix socket the server listens on besides
+// the TCP port and permissions of the socket file. Requests to the socket
+// are not authenticated: access is controlled by the permissions.
+func (httpServer *HTTPServer) UnixSocket(path string, mode os.FileMode) *HTTPServer {
+	httpServer.socketPath = path
+	httpServer.socketMode = mode
+	return httpServer
+}
+
+// listenUnix creates the unix socket with the permissions. A socket left by
+// a killed daemon is removed.
+func listenUnix(path string, mode os.FileMode) (net.Listener, error) {
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%q exists and is not a socket", path)
+		}
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("socket %q is already in use", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, err
+	}
+	// The socket is created in a private directory, so nobody can connect
+	// to it before the permissions are set, and then moved into place.
+	tmpDir, err := ioutil.TempDir(filepath.Dir(path), ".sock")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+	tmpPath := filepath.Join(tmpDir, "s")
+	listener, err := net.Listen("unix", tmpPath)
+	if err != nil {
+		return nil, err
+	}
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	if err := os.Chmod(tmpPath, mode.Perm()); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return &unixListener{Listener: listener, path: path}, nil
+}
+
+// unixListener is a unix socket listener that removes the socket file by
+// the path on close.
+type unixListener struct {
+	net.Listener
+	path string
+}
+
+// Close closes the listener and removes the socket file.
+func (listener *unixListener) Close() error {
+	err := listener.Listener.Close()
+	os.Remove(listener.path)
+	return err
+}
+
+// TLS sets paths to the server certificate, the private key and CA
+// certificates to verify clients.
+func (httpServer *HTTPServer) TLS(certFile, keyFile, caFile string) *HTTPServer {
+	httpServer.certFile = certFile
+	httpServer.keyFile = keyFile
+	httpServer.caFile = caFile
+	return httpServer
+}
+
+// getTLSConfig returns TLS configuration requiring and verifying client
+// certificates if the CA file is set.
+func (httpServer *HTTPServer) getTLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if httpServer.caFile == "" {
+		return tlsConfig, nil
+	}
+
+	caCerts, err := ioutil.ReadFile(httpServer.caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %s", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caCerts) {
+		return nil, fmt.Errorf("no certificates found in CA file %q", httpServer.caFile)
+	}
+	tlsConfig.ClientCAs = pool
+	tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	return tlsConfig, nil
+}
+
 // SetLogger sets a log file the HTTP server will write to.
 func (httpServer *HTTPServer) SetLogger(logger *ttlog.Logger) {
 	httpServer.logger = logger
@@ -116,7 +273,68 @@ func (httpServer *HTTPServer) Start(ttPath string) {
 
 	// Prepare HTTP server.
 	daemonHandler := api.NewDaemonHandler(ttPath).Logger(httpServer.logger)
-	http.Handle("/tarantool", daemonHandler)
+	instancesHandler := api.NewInstancesHandler(httpServer.manager).
+		Logger(httpServer.logger)
+	if len(httpServer.allowedCommands) > 0 {
+		daemonHandler.AllowedCommands(httpServer.allowedCommands)
+		instancesHandler.AllowedCommands(httpServer.allowedCommands)
+	}
+	daemonHandler.InstanceNames(api.ManagerInstanceNames(httpServer.manager)).
+		Environments(httpServer.manager.Environments())
+	httpServer.jobsHandler = api.NewJobsHandler(daemonHandler).
+		HistorySize(httpServer.jobsHistorySize).
+		Logger(httpServer.logger)
+
+	metrics := api.NewMetrics(httpServer.manager)
+
+	handlers := map[string]http.Handler{
+		"/tarantool":            daemonHandler,
+		api.InstancesPath:       instancesHandler,
+		api.InstancesPath + "/": instancesHandler,
+		api.JobsPath:            httpServer.jobsHandler,
+		api.JobsPath + "/":      httpServer.jobsHandler,
+		api.EnvironmentsPath: api.NewEnvironmentsHandler(httpServer.manager).
+			Logger(httpServer.logger),
+		api.OpenAPIPath: api.OpenAPIHandler{},
+		api.MetricsPath: metrics,
+	}
+	tcpMux := http.NewServeMux()
+	socketMux := http.NewServeMux()
+	for path, handler := range handlers {
+		authHandler, err := api.NewAuthHandler(handler, httpServer.authOpts)
+		if err != nil {
+			httpServer.logger.Fatalf("Can't configure authentication: %s", err)
+		}
+		// Requests are recorded by the path they are registered with to
+		// keep the number of label values bounded.
+		name := strings.TrimSuffix(path, "/")
+		tcpMux.Handle(path, metrics.Handler(name, authHandler.Logger(httpServer.logger)))
+		socketMux.Handle(path, metrics.Handler(name, handler))
+	}
+	httpServer.srv.Handler = tcpMux
+
+	if httpServer.certFile != "" {
+		if httpServer.srv.TLSConfig, err = httpServer.getTLSConfig(); err != nil {
+			httpServer.logger.Fatalf("Can't configure TLS: %s", err)
+		}
+	}
+
+	if httpServer.socketPath != "" {
+		listener, err := listenUnix(httpServer.socketPath, httpServer.socketMode)
+		if err != nil {
+			httpServer.logger.Fatalf("Can't listen on unix socket: %s", err)
+		}
+		httpServer.socketSrv = &http.Server{Handler: socketMux}
+		go func() {
+			if err := httpServer.socketSrv.Serve(listener); err != http.ErrServerClosed {
+				httpServer.logger.Fatalf("Can't serve unix socket: %s", err)
+			}
+		}()
+	}
+
+	if httpServer.supervisor != nil {
+		go httpServer.supervisor.Logger(httpServer.logger).Start()
+	}
 
 	// Start HTTP server.
 	socket, err := net.Listen("tcp4", httpServer.srv.Addr)
@@ -124,8 +342,13 @@ func (httpServer *HTTPServer) Start(ttPath string) {
 		httpServer.logger.Fatal(err)
 	}
 
-	if err := httpServer.srv.Serve(socket); err != http.ErrServerClosed {
-		httpServer.logger.Fatalf("Can't start HTTP server")
+	if httpServer.certFile != "" {
+		err = httpServer.srv.ServeTLS(socket, httpServer.certFile, httpServer.keyFile)
+	} else {
+		err = httpServer.srv.Serve(socket)
+	}
+	if err != http.ErrServerClosed {
+		httpServer.logger.Fatalf("Can't start HTTP server: %s", err)
 	}
 }
 
@@ -137,10 +360,26 @@ func (httpServer *HTTPServer) Stop() error {
 		return fmt.Errorf("server is not started")
 	}
 
+	if httpServer.supervisor != nil {
+		httpServer.supervisor.Stop()
+	}
+
+	// Running jobs are canceled to finish their log streams.
+	if httpServer.jobsHandler != nil {
+		httpServer.jobsHandler.CancelAll()
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), httpServer.timeout)
 	if err = httpServer.srv.Shutdown(ctx); err != nil {
 		httpServer.logger.Printf(`HTTP server shutdown error: "%v"`, err)
 	}
+	// The socket file is removed on the listener close.
+	if httpServer.socketSrv != nil {
+		if socketErr := httpServer.socketSrv.Shutdown(ctx); socketErr != nil {
+			httpServer.logger.Printf(`Unix socket server shutdown error: "%v"`, socketErr)
+			err = socketErr
+		}
+	}
 	cancel()
 
 	return err
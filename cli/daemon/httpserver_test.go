@@ -0,0 +1,48 @@
+package daemon
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListenUnix(t *testing.T) {
+	dir := t.TempDir()
+	socketPath := filepath.Join(dir, "run", "tt_daemon.sock")
+
+	listener, err := listenUnix(socketPath, 0600)
+	require.NoError(t, err)
+	info, err := os.Stat(socketPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	// The temporary directory of the socket is removed.
+	entries, err := os.ReadDir(filepath.Dir(socketPath))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	_, err = listenUnix(socketPath, 0600)
+	assert.EqualError(t, err, `socket "`+socketPath+`" is already in use`)
+	require.NoError(t, listener.Close())
+
+	// A socket left by a killed daemon is replaced.
+	stale, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, stale.Close())
+	listener, err = listenUnix(socketPath, 0660)
+	require.NoError(t, err)
+	info, err = os.Stat(socketPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0660), info.Mode().Perm())
+	require.NoError(t, listener.Close())
+	assert.NoFileExists(t, socketPath)
+
+	filePath := filepath.Join(dir, "file")
+	require.NoError(t, os.WriteFile(filePath, []byte{}, 0644))
+	_, err = listenUnix(filePath, 0660)
+	assert.EqualError(t, err, `"`+filePath+`" exists and is not a socket`)
+}
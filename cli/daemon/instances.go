@@ -0,0 +1,112 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+
+	"github.com/tarantool/tt/cli/cmdcontext"
+	"github.com/tarantool/tt/cli/config"
+	"github.com/tarantool/tt/cli/configure"
+	"github.com/tarantool/tt/cli/daemon/api"
+	"github.com/tarantool/tt/cli/running"
+)
+
+// instanceManager provides instances of the tt environments managed by
+// the daemon.
+type instanceManager struct {
+	daemonCtx *DaemonCtx
+}
+
+// Environments returns the configured environments sorted by name or
+// the environment the daemon is started in if there are none.
+func (manager *instanceManager) Environments() []api.Environment {
+	if len(manager.daemonCtx.Environments) == 0 {
+		return []api.Environment{{Name: api.DefaultEnvironment}}
+	}
+
+	envs := make([]api.Environment, 0, len(manager.daemonCtx.Environments))
+	for name, configPath := range manager.daemonCtx.Environments {
+		envs = append(envs, api.Environment{Name: name, ConfigPath: configPath})
+	}
+	sort.Slice(envs, func(i, j int) bool {
+		return envs[i].Name < envs[j].Name
+	})
+	return envs
+}
+
+// environment returns tt options and the command context of the
+// environment. Configurations of the named environments are loaded on
+// each call to notice their changes without a restart of the daemon.
+func (manager *instanceManager) environment(env string) (*config.CliOpts,
+	*cmdcontext.CmdCtx, error) {
+	if len(manager.daemonCtx.Environments) == 0 {
+		if env != api.DefaultEnvironment {
+			return nil, nil, fmt.Errorf("environment %q is not found", env)
+		}
+		if manager.daemonCtx.CliOpts == nil || manager.daemonCtx.CmdCtx == nil {
+			return nil, nil, fmt.Errorf("tt environment is not configured")
+		}
+		// The context is copied to avoid side effects of the command name.
+		cmdCtx := *manager.daemonCtx.CmdCtx
+		return manager.daemonCtx.CliOpts, &cmdCtx, nil
+	}
+
+	configPath, ok := manager.daemonCtx.Environments[env]
+	if !ok {
+		return nil, nil, fmt.Errorf("environment %q is not found", env)
+	}
+	cliOpts, cmdCtx, err := configure.CliEnvironment(configPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load %q: %s", configPath, err)
+	}
+	return cliOpts, cmdCtx, nil
+}
+
+// Instances returns enabled instances of the environment.
+func (manager *instanceManager) Instances(env string) ([]running.InstanceCtx, error) {
+	cliOpts, cmdCtx, err := manager.environment(env)
+	if err != nil {
+		return nil, err
+	}
+
+	cmdCtx.CommandName = "status"
+	var runningCtx running.RunningCtx
+	if err := running.FillCtx(cliOpts, cmdCtx, &runningCtx, nil); err != nil {
+		return nil, err
+	}
+	return runningCtx.Instances, nil
+}
+
+// Start starts the instance of the environment under a watchdog in
+// background as "tt start" does.
+func (manager *instanceManager) Start(env string, inst *running.InstanceCtx) error {
+	_, cmdCtx, err := manager.environment(env)
+	if err != nil {
+		return err
+	}
+	ttBin, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	args := []string{"start", "--watchdog", running.GetAppInstanceName(*inst)}
+	if configPath := cmdCtx.Cli.ConfigPath; configPath != "" {
+		args = append([]string{"--cfg", configPath}, args...)
+	}
+	wdCmd := exec.Command(ttBin, args...)
+	// The daemon environment tag should not be inherited by the watchdog.
+	wdCmd.Env = []string{}
+	for _, env := range os.Environ() {
+		if env != EnvName+"=true" {
+			wdCmd.Env = append(wdCmd.Env, env)
+		}
+	}
+	if err := wdCmd.Start(); err != nil {
+		return err
+	}
+	// The watchdog is waited to avoid a zombie process.
+	go wdCmd.Wait()
+	return nil
+}
@@ -0,0 +1,154 @@
+package daemon
+
+import (
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/tarantool/tt/cli/daemon/api"
+	"github.com/tarantool/tt/cli/process_utils"
+	"github.com/tarantool/tt/cli/running"
+	"github.com/tarantool/tt/cli/ttlog"
+)
+
+// DefaultSupervisorInterval is a default period of instance checks.
+const DefaultSupervisorInterval = 5 * time.Second
+
+// Supervisor keeps enabled instances of the environments running. On start
+// it starts all instances that are not running, after that it periodically
+// restarts dead instances: the ones whose PID file remains while the process
+// is not alive and the ones without a PID file that are not stopped with
+// "tt stop", e.g. crashed under a watchdog without restarts. An instance
+// stopped with "tt stop" is not started again until the next start of
+// the supervisor.
+type Supervisor struct {
+	// manager provides instances of the environments.
+	manager api.InstanceManager
+	// interval is a period of instance checks.
+	interval time.Duration
+	// logger is a log file the supervisor writes to.
+	logger *ttlog.Logger
+	// stop is closed to stop the supervisor.
+	stop chan struct{}
+	// done is closed when the supervisor is stopped.
+	done chan struct{}
+	// stopOnce guards closing of the stop channel.
+	stopOnce sync.Once
+}
+
+// NewSupervisor creates Supervisor.
+func NewSupervisor(manager api.InstanceManager) *Supervisor {
+	return &Supervisor{
+		manager:  manager,
+		interval: DefaultSupervisorInterval,
+		logger:   ttlog.NewCustomLogger(io.Discard, "", 0),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Interval sets a period of instance checks. The default period is used
+// if it is not positive.
+func (supervisor *Supervisor) Interval(interval time.Duration) *Supervisor {
+	if interval > 0 {
+		supervisor.interval = interval
+	}
+	return supervisor
+}
+
+// Logger sets logger for Supervisor.
+func (supervisor *Supervisor) Logger(logger *ttlog.Logger) *Supervisor {
+	supervisor.logger = logger
+	return supervisor
+}
+
+// Start starts instances that are not running and checks them until
+// the supervisor is stopped.
+func (supervisor *Supervisor) Start() {
+	defer close(supervisor.done)
+
+	select {
+	case <-supervisor.stop:
+		return
+	default:
+	}
+	supervisor.check(true)
+
+	ticker := time.NewTicker(supervisor.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-supervisor.stop:
+			return
+		case <-ticker.C:
+			supervisor.check(false)
+		}
+	}
+}
+
+// Stop stops the supervisor and waits for the current check to be
+// finished. Instances are not stopped.
+func (supervisor *Supervisor) Stop() {
+	supervisor.stopOnce.Do(func() {
+		close(supervisor.stop)
+	})
+	<-supervisor.done
+}
+
+// Instance states found by the supervisor.
+const (
+	instanceRunning = iota
+	instanceStopped
+	instanceDead
+)
+
+// getInstanceState returns a state of the instance. The instance is dead if
+// the process of the PID file is not alive or there is no PID file while
+// the instance is not marked as stopped.
+func getInstanceState(inst *running.InstanceCtx) int {
+	pid, err := process_utils.GetPIDFromFile(inst.PIDFile)
+	if err != nil {
+		if _, err := os.Stat(inst.StopFile); err == nil {
+			return instanceStopped
+		}
+		return instanceDead
+	}
+	if alive, _ := process_utils.IsProcessAlive(pid); alive {
+		return instanceRunning
+	}
+	return instanceDead
+}
+
+// check restarts dead instances of the environments. Stopped instances
+// are started too if startStopped is true.
+func (supervisor *Supervisor) check(startStopped bool) {
+	for _, env := range supervisor.manager.Environments() {
+		instances, err := supervisor.manager.Instances(env.Name)
+		if err != nil {
+			supervisor.logger.Printf("Supervisor: can't get instances of environment %q: %s",
+				env.Name, err)
+			continue
+		}
+
+		for i := range instances {
+			inst := &instances[i]
+			state := getInstanceState(inst)
+			if state == instanceRunning || (state == instanceStopped && !startStopped) {
+				continue
+			}
+
+			msg := "starting"
+			if state == instanceDead && !startStopped {
+				msg = "restarting dead"
+			}
+			name := running.GetAppInstanceName(*inst)
+			supervisor.logger.Printf("Supervisor: %s instance %s of environment %q",
+				msg, name, env.Name)
+			if err := supervisor.manager.Start(env.Name, inst); err != nil {
+				supervisor.logger.Printf("Supervisor: failed to start instance %s "+
+					"of environment %q: %s", name, env.Name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tarantool/tt/cli/daemon/api"
+	"github.com/tarantool/tt/cli/running"
+)
+
+// testManager is an instance manager recording started instances.
+type testManager struct {
+	instances []running.InstanceCtx
+	err       error
+
+	mutex   sync.Mutex
+	started []string
+}
+
+func (manager *testManager) Environments() []api.Environment {
+	return []api.Environment{{Name: api.DefaultEnvironment}}
+}
+
+func (manager *testManager) Instances(env string) ([]running.InstanceCtx, error) {
+	return manager.instances, manager.err
+}
+
+func (manager *testManager) Start(env string, inst *running.InstanceCtx) error {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	manager.started = append(manager.started, inst.InstName)
+	return nil
+}
+
+func (manager *testManager) getStarted() []string {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	return append([]string{}, manager.started...)
+}
+
+// newSupervisorTestInstance returns an instance with the PID file of
+// the process. The PID file is not created if the command is nil, the stop
+// marker is created if stopped is true.
+func newSupervisorTestInstance(t *testing.T, name string, cmd *exec.Cmd,
+	stopped bool) running.InstanceCtx {
+	runDir := t.TempDir()
+	inst := running.InstanceCtx{
+		AppName:  "app",
+		InstName: name,
+		PIDFile:  filepath.Join(runDir, name+".pid"),
+		StopFile: filepath.Join(runDir, name+".stopped"),
+	}
+	if cmd != nil {
+		require.NoError(t, os.WriteFile(inst.PIDFile,
+			[]byte(strconv.Itoa(cmd.Process.Pid)), 0644))
+	}
+	if stopped {
+		require.NoError(t, os.WriteFile(inst.StopFile, []byte{}, 0644))
+	}
+	return inst
+}
+
+func TestSupervisor(t *testing.T) {
+	alive := exec.Command("sleep", "60")
+	require.NoError(t, alive.Start())
+	t.Cleanup(func() {
+		alive.Process.Kill()
+		alive.Wait()
+	})
+	dead := exec.Command("true")
+	require.NoError(t, dead.Run())
+
+	manager := &testManager{instances: []running.InstanceCtx{
+		newSupervisorTestInstance(t, "running", alive, false),
+		newSupervisorTestInstance(t, "stopped", nil, true),
+		newSupervisorTestInstance(t, "dead", dead, false),
+		newSupervisorTestInstance(t, "crashed", nil, false),
+	}}
+	supervisor := NewSupervisor(manager).Interval(10 * time.Millisecond)
+	go supervisor.Start()
+
+	// Stopped instances are started only on the supervisor start, dead
+	// instances and instances without a PID file and a stop marker are
+	// restarted on each check.
+	require.Eventually(t, func() bool {
+		return len(manager.getStarted()) >= 7
+	}, 10*time.Second, 10*time.Millisecond)
+	supervisor.Stop()
+
+	started := manager.getStarted()
+	assert.Equal(t, []string{"stopped", "dead", "crashed"}, started[:3])
+	for _, name := range started[3:] {
+		assert.Contains(t, []string{"dead", "crashed"}, name)
+	}
+	assert.Contains(t, started[3:], "crashed")
+
+	// The supervisor could be stopped more than once.
+	supervisor.Stop()
+}
+
+func TestSupervisor_error(t *testing.T) {
+	manager := &testManager{err: fmt.Errorf("tt.yaml not found")}
+	supervisor := NewSupervisor(manager).Interval(10 * time.Millisecond)
+	go supervisor.Start()
+	time.Sleep(50 * time.Millisecond)
+	supervisor.Stop()
+	assert.Empty(t, manager.getStarted())
+}
@@ -8,6 +8,7 @@ import (
 	"os/exec"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -72,6 +73,12 @@ type InstanceCtx struct {
 	// The name of the file with the watchdog PID under which the
 	// instance was started.
 	PIDFile string
+	// The name of the file with a number of restarts of the instance
+	// by the watchdog.
+	RestartsFile string
+	// The name of the file marking the instance as stopped by "tt stop".
+	// It is removed on the instance start.
+	StopFile string
 	// If the instance is started under the watchdog it should
 	// restart on if it crashes.
 	Restartable bool
@@ -347,6 +354,10 @@ func cleanup(run *InstanceCtx) {
 	if _, err := os.Stat(run.ConsoleSocket); err == nil {
 		os.Remove(run.ConsoleSocket)
 	}
+
+	if _, err := os.Stat(run.RestartsFile); err == nil {
+		os.Remove(run.RestartsFile)
+	}
 }
 
 // createLogger prepares a logger for the watchdog and instance.
@@ -428,6 +439,8 @@ func FillCtx(cliOpts *config.CliOpts, cmdCtx *cmdcontext.CmdCtx,
 			instance.RunDir = pathBuilder.WithPath(runDir).Make()
 			instance.ConsoleSocket = filepath.Join(instance.RunDir, instance.InstName+".control")
 			instance.PIDFile = filepath.Join(instance.RunDir, instance.InstName+".pid")
+			instance.RestartsFile = filepath.Join(instance.RunDir, instance.InstName+".restarts")
+			instance.StopFile = filepath.Join(instance.RunDir, instance.InstName+".stopped")
 			instance.LogDir = pathBuilder.WithPath(logDir).Make()
 			instance.Log = filepath.Join(instance.LogDir, instance.InstName+".log")
 			pathBuilder = pathBuilder.WithTarantoolctlLayout(false)
@@ -449,7 +462,9 @@ func FillCtx(cliOpts *config.CliOpts, cmdCtx *cmdcontext.CmdCtx,
 		}
 	}
 
-	if cmdCtx.CommandName != "connect" {
+	connectionCommands := []string{"connect", "eval", "call", "sql", "watch", "cp", "user",
+		"schema", "export", "import"}
+	if util.Find(connectionCommands, cmdCtx.CommandName) == -1 {
 		if cmdCtx.Cli.TarantoolExecutable == "" {
 			return fmt.Errorf("tarantool binary not found")
 		}
@@ -460,6 +475,12 @@ func FillCtx(cliOpts *config.CliOpts, cmdCtx *cmdcontext.CmdCtx,
 
 // Start an Instance.
 func Start(cmdCtx *cmdcontext.CmdCtx, run *InstanceCtx) error {
+	if run.StopFile != "" {
+		if err := os.Remove(run.StopFile); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove stop marker: %s", err)
+		}
+	}
+
 	logger := createLogger(run)
 	provider := providerImpl{cmdCtx: cmdCtx, instanceCtx: run}
 	preStartAction := func() error {
@@ -469,6 +490,11 @@ func Start(cmdCtx *cmdcontext.CmdCtx, run *InstanceCtx) error {
 		return nil
 	}
 	wd := NewWatchdog(run.Restartable, 5*time.Second, logger, &provider, preStartAction)
+	if run.RestartsFile != "" {
+		wd.SetRestartAction(func(restarts int) error {
+			return ioutil.WriteFile(run.RestartsFile, []byte(strconv.Itoa(restarts)), 0644)
+		})
+	}
 
 	defer func() {
 		cleanup(run)
@@ -480,6 +506,18 @@ func Start(cmdCtx *cmdcontext.CmdCtx, run *InstanceCtx) error {
 
 // Stop the Instance.
 func Stop(run *InstanceCtx) error {
+	// The instance is marked as stopped before the stop, so a supervisor
+	// does not take it for a crashed one.
+	if run.StopFile != "" {
+		err := os.MkdirAll(filepath.Dir(run.StopFile), defaultDirPerms)
+		if err == nil {
+			err = ioutil.WriteFile(run.StopFile, []byte{}, 0644)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create stop marker: %s", err)
+		}
+	}
+
 	pid, err := process_utils.StopProcess(run.PIDFile)
 	if err != nil {
 		return err
@@ -512,6 +550,22 @@ func Status(run *InstanceCtx) process_utils.ProcessState {
 	return process_utils.ProcessStatus(run.PIDFile)
 }
 
+// GetRestarts returns a number of restarts of the started instance by
+// the watchdog. It is zero if the instance has not been restarted.
+func GetRestarts(run *InstanceCtx) (int, error) {
+	data, err := ioutil.ReadFile(run.RestartsFile)
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	restarts, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("invalid restarts file %q: %s", run.RestartsFile, err)
+	}
+	return restarts, nil
+}
+
 // Logrotate rotates logs of a started tarantool instance.
 func Logrotate(run *InstanceCtx) (string, error) {
 	pid, err := process_utils.GetPIDFromFile(run.PIDFile)
@@ -48,6 +48,11 @@ type Watchdog struct {
 	shouldStop bool
 	// preStartAction is a hook that is to be run before the start of a new Instance.
 	preStartAction func() error
+	// restarts is a number of restarts of the Instance.
+	restarts int
+	// restartAction is a hook that is to be run on a restart of the Instance
+	// with the number of restarts.
+	restartAction func(restarts int) error
 }
 
 // NewWatchdog creates a new instance of Watchdog.
@@ -61,6 +66,12 @@ func NewWatchdog(restartable bool, restartTimeout time.Duration, logger *ttlog.L
 	return &wd
 }
 
+// SetRestartAction sets a hook that is to be run on a restart of the Instance
+// with the number of restarts.
+func (wd *Watchdog) SetRestartAction(restartAction func(restarts int) error) {
+	wd.restartAction = restartAction
+}
+
 // Start starts the Instance and signal handling.
 func (wd *Watchdog) Start() error {
 	var err error
@@ -139,6 +150,12 @@ func (wd *Watchdog) Start() error {
 			return err
 		}
 		wd.logger = wd.Instance.logger
+		wd.restarts++
+		if wd.restartAction != nil {
+			if err := wd.restartAction(wd.restarts); err != nil {
+				wd.logger.Printf(`Restart action error: %v`, err)
+			}
+		}
 		// Before the restart of an instance start a new signal handling loop.
 		wd.startSignalHandling()
 	}
@@ -123,6 +123,7 @@ func TestWatchdogBase(t *testing.T) {
 
 	killAndCheckRestart(t, wd, syscall.SIGINT)
 	killAndCheckRestart(t, wd, syscall.SIGKILL)
+	assert.Equal(2, wd.restarts, "Restarts are not counted.")
 
 	// Let's try to stop the watchdog by a signal.
 	syscall.Kill(syscall.Getpid(), syscall.SIGINT)
@@ -0,0 +1,19 @@
+local fiber = require('fiber')
+
+box.cfg({})
+
+box.schema.space.create('test', {
+    if_not_exists = true,
+    format = {{'id', 'unsigned'}, {'name', 'string'}},
+})
+box.space.test:create_index('pk', { if_not_exists = true })
+box.space.test:replace({1, 'one'})
+box.space.test:replace({2, 'two'})
+
+function sum(a, b)
+    return a + b
+end
+
+while true do
+    fiber.sleep(5)
+end
@@ -0,0 +1,68 @@
+import json
+import os
+import re
+import shutil
+
+import pytest
+
+from utils import run_command_and_get_output, run_path, wait_file
+
+
+@pytest.fixture
+def test_app(tt_cmd, tmpdir_with_cfg):
+    tmpdir = tmpdir_with_cfg
+    test_app_path = os.path.join(os.path.dirname(__file__), "test_app.lua")
+    shutil.copy(test_app_path, tmpdir)
+
+    # Start an instance.
+    start_cmd = [tt_cmd, "start", "test_app"]
+    rc, output = run_command_and_get_output(start_cmd, cwd=tmpdir)
+    assert rc == 0
+
+    # Check for start.
+    file = wait_file(os.path.join(tmpdir, run_path, "test_app"), 'test_app.control', [])
+    assert file != ""
+
+    yield tmpdir
+
+    # Stop the Instance.
+    run_command_and_get_output([tt_cmd, "stop", "test_app"], cwd=tmpdir)
+
+
+def test_call(tt_cmd, test_app):
+    cmd = [tt_cmd, "call", "test_app", "sum", "1", "2", "--format", "json"]
+    rc, output = run_command_and_get_output(cmd, cwd=test_app)
+    assert rc == 0
+    assert json.loads(output) == [3]
+
+    cmd = [tt_cmd, "call", "test_app", "box.space.test:get", "2"]
+    rc, output = run_command_and_get_output(cmd, cwd=test_app)
+    assert rc == 0
+    assert output == "---\n- - 2\n  - two\n...\n"
+
+    cmd = [tt_cmd, "call", "test_app", "unknown_func"]
+    rc, output = run_command_and_get_output(cmd, cwd=test_app)
+    assert rc != 0
+
+
+def test_sql(tt_cmd, test_app):
+    cmd = [tt_cmd, "sql", "test_app", 'SELECT * FROM "test" WHERE "id" > ?', "--bind", "0"]
+    rc, output = run_command_and_get_output(cmd, cwd=test_app)
+    assert rc == 0
+    assert output == "id  name\n--  ----\n1   one\n2   two\n(2 rows)\n"
+
+    cmd = [tt_cmd, "sql", "test_app", 'SELECT "name" FROM "test" WHERE "id" = 1',
+           "--format", "json"]
+    rc, output = run_command_and_get_output(cmd, cwd=test_app)
+    assert rc == 0
+    assert json.loads(output) == [{"name": "one"}]
+
+    cmd = [tt_cmd, "sql", "test_app", """UPDATE "test" SET "name" = 'new' WHERE "id" = 1"""]
+    rc, output = run_command_and_get_output(cmd, cwd=test_app)
+    assert rc == 0
+    assert output == "Rows affected: 1\n"
+
+    cmd = [tt_cmd, "sql", "test_app", "SELECT * FROM unknown"]
+    rc, output = run_command_and_get_output(cmd, cwd=test_app)
+    assert rc != 0
+    assert re.search(r"UNKNOWN", output)
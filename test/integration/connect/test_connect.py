@@ -1,11 +1,13 @@
+import json
 import os
 import re
 import shutil
 import subprocess
 
 import pytest
+import yaml
 
-from utils import run_command_and_get_output, run_path, wait_file
+from utils import config_name, run_command_and_get_output, run_path, wait_file
 
 
 def copy_data(dst, file_paths):
@@ -513,3 +515,126 @@ def test_connect_language_set_if_unsupported(tt_cmd, tmpdir_with_cfg):
 
     # Stop the Instance.
     stop_app(tt_cmd, tmpdir, test_app)
+
+
+def test_connect_meta_commands(tt_cmd, tmpdir_with_cfg):
+    tmpdir = tmpdir_with_cfg
+    # The test application file.
+    test_app_path = os.path.join(os.path.dirname(__file__), "test_single_app", "test_app.lua")
+    # Copy test data into temporary directory.
+    copy_data(tmpdir, [test_app_path])
+
+    # Start an instance.
+    start_app(tt_cmd, tmpdir, "test_app")
+
+    # Check for start.
+    file = wait_file(os.path.join(tmpdir, run_path, "test_app"), 'test_app.control', [])
+    assert file != ""
+
+    # Meta-commands are executed by the console itself.
+    commands = "\\help\n\\info\n\\x\nreturn {1, 2}\n\\q\nreturn 'unreachable'\n"
+    instance_process = subprocess.run(
+        [tt_cmd, "connect", "test_app"],
+        cwd=tmpdir,
+        input=commands,
+        stderr=subprocess.STDOUT,
+        stdout=subprocess.PIPE,
+        text=True,
+    )
+    assert instance_process.returncode == 0
+    output = instance_process.stdout
+    assert re.search(r"\\quit, \\q\s+quit the console", output)
+    assert re.search(r"Language:\s+lua", output)
+    assert re.search(r"Server version:\s+\d+\.\d+", output)
+    assert re.search(r"Expanded output is on", output)
+    assert re.search(r"- - 1\n  - 2\n", output)
+    assert re.search(r"unreachable", output) is None
+
+    # Stop the Instance.
+    stop_app(tt_cmd, tmpdir, "test_app")
+
+
+def test_connect_sql_timing_explain_read_only(tt_cmd, tmpdir_with_cfg):
+    tmpdir = tmpdir_with_cfg
+    test_app, lua_file, sql_file = prepare_test_app_languages(tt_cmd, tmpdir)
+
+    skip_if_language_unsupported(tt_cmd, tmpdir, test_app)
+
+    # Timing and query plans are printed by the console.
+    commands = "\\timing\nVALUES (1);\n\\explain SELECT * FROM \"_space\" WHERE \"id\" = 1\n"
+    instance_process = subprocess.run(
+        [tt_cmd, "connect", test_app, "-l", "sql"],
+        cwd=tmpdir,
+        input=commands,
+        stderr=subprocess.STDOUT,
+        stdout=subprocess.PIPE,
+        text=True,
+    )
+    assert instance_process.returncode == 0
+    output = instance_process.stdout
+    assert re.search(r"Timing is on", output)
+    assert re.search(r"Time: \d+\.\d{3} ms \(server: \d+\.\d{3} ms\)", output)
+    assert re.search(r"QUERY PLAN\n`--SEARCH TABLE _space USING PRIMARY KEY", output)
+
+    # Modifying statements are rejected in read-only mode.
+    commands = "CREATE TABLE t (id INT PRIMARY KEY);\nVALUES (1);\n\\set language lua\n"
+    instance_process = subprocess.run(
+        [tt_cmd, "connect", test_app, "--read-only"],
+        cwd=tmpdir,
+        input=commands,
+        stderr=subprocess.STDOUT,
+        stdout=subprocess.PIPE,
+        text=True,
+    )
+    assert instance_process.returncode == 0
+    output = instance_process.stdout
+    assert re.search(r"CREATE statements are not allowed in read-only mode", output)
+    assert re.search(r"only sql language is available in read-only mode", output)
+    assert re.search(r"metadata:", output)
+
+    ret, output = try_execute_on_instance(tt_cmd, tmpdir, test_app, sql_file,
+                                          opts={"--read-only": "--language=lua"})
+    assert not ret
+    assert re.search(r"read-only mode is supported only for the sql language", output)
+
+    # Stop the Instance.
+    stop_app(tt_cmd, tmpdir, test_app)
+
+
+def test_connect_audit_log(tt_cmd, tmpdir_with_cfg):
+    tmpdir = tmpdir_with_cfg
+    with open(os.path.join(tmpdir, config_name), "w") as f:
+        yaml.dump({"tt": {"modules": {"directory": ""}, "audit_log": "audit/tt.log"}}, f)
+
+    test_app, lua_file, sql_file = prepare_test_app_languages(tt_cmd, tmpdir)
+
+    ret, output = try_execute_on_instance(tt_cmd, tmpdir, test_app, lua_file)
+    assert ret
+
+    commands = "return 1 + 1\nerror('oops')\n"
+    instance_process = subprocess.run(
+        [tt_cmd, "connect", test_app],
+        cwd=tmpdir,
+        input=commands,
+        stderr=subprocess.STDOUT,
+        stdout=subprocess.PIPE,
+        text=True,
+    )
+    assert instance_process.returncode == 0
+
+    with open(os.path.join(tmpdir, "audit", "tt.log")) as f:
+        records = [json.loads(line) for line in f]
+    assert [record["statement"] for record in records] == [
+        open(os.path.join(tmpdir, lua_file)).read(),
+        "return 1 + 1",
+        "error('oops')",
+    ]
+    assert [record["success"] for record in records] == [True, True, False]
+    assert "oops" in records[2]["error"]
+    for record in records:
+        assert record["language"] == "lua"
+        assert record["target"].endswith("test_app.control")
+        assert record["duration_ms"] >= 0
+
+    # Stop the Instance.
+    stop_app(tt_cmd, tmpdir, test_app)
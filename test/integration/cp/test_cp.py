@@ -0,0 +1,81 @@
+import os
+import shutil
+
+import pytest
+
+from utils import run_command_and_get_output, run_path, wait_file
+
+
+@pytest.fixture
+def test_app(tt_cmd, tmpdir_with_cfg):
+    tmpdir = tmpdir_with_cfg
+    test_app_path = os.path.join(os.path.dirname(__file__), "test_app.lua")
+    shutil.copy(test_app_path, tmpdir)
+
+    # Start an instance.
+    start_cmd = [tt_cmd, "start", "test_app"]
+    rc, output = run_command_and_get_output(start_cmd, cwd=tmpdir)
+    assert rc == 0
+
+    # Check for start.
+    file = wait_file(os.path.join(tmpdir, run_path, "test_app"), 'test_app.control', [])
+    assert file != ""
+
+    yield tmpdir
+
+    # Stop the Instance.
+    run_command_and_get_output([tt_cmd, "stop", "test_app"], cwd=tmpdir)
+
+
+def read_file(path):
+    with open(path, "rb") as f:
+        return f.read()
+
+
+def test_cp(tt_cmd, test_app):
+    content = os.urandom(3 * 1024 * 1024 + 17)
+    with open(os.path.join(test_app, "data.bin"), "wb") as f:
+        f.write(content)
+
+    remote_path = os.path.join(test_app, "remote.bin")
+    cmd = [tt_cmd, "cp", "./data.bin", "test_app:" + remote_path]
+    rc, output = run_command_and_get_output(cmd, cwd=test_app)
+    assert rc == 0
+    assert read_file(remote_path) == content
+    assert not os.path.exists(remote_path + ".part")
+
+    cmd = [tt_cmd, "cp", "test_app:" + remote_path, "./copy.bin", "--chunk-size", "100000"]
+    rc, output = run_command_and_get_output(cmd, cwd=test_app)
+    assert rc == 0
+    assert read_file(os.path.join(test_app, "copy.bin")) == content
+
+
+def test_cp_resume(tt_cmd, test_app):
+    content = os.urandom(1024 * 1024)
+    remote_path = os.path.join(test_app, "remote.bin")
+    with open(remote_path, "wb") as f:
+        f.write(content)
+
+    # A partially copied file is continued.
+    local_dir = os.path.join(test_app, "local")
+    os.mkdir(local_dir)
+    with open(os.path.join(local_dir, "remote.bin.part"), "wb") as f:
+        f.write(content[:1000])
+
+    cmd = [tt_cmd, "cp", "test_app:" + remote_path, "./local"]
+    rc, output = run_command_and_get_output(cmd, cwd=test_app)
+    assert rc == 0
+    assert read_file(os.path.join(local_dir, "remote.bin")) == content
+    assert not os.path.exists(os.path.join(local_dir, "remote.bin.part"))
+
+
+def test_cp_errors(tt_cmd, test_app):
+    cmd = [tt_cmd, "cp", "test_app:" + os.path.join(test_app, "missing"), "./local"]
+    rc, output = run_command_and_get_output(cmd, cwd=test_app)
+    assert rc != 0
+    assert "does not exist" in output
+
+    cmd = [tt_cmd, "cp", "./a", "./b"]
+    rc, output = run_command_and_get_output(cmd, cwd=test_app)
+    assert rc != 0
+    assert "exactly one of the paths must be a remote" in output
@@ -2,6 +2,7 @@ import os
 import re
 import shutil
 import subprocess
+import time
 
 import psutil
 import pytest
@@ -216,6 +217,18 @@ def test_daemon_http_requests(tt_cmd, tmpdir_with_cfg):
     assert response.status_code == 200
     assert re.search(r"RUNNING. PID: \d+.", response.json()["res"])
 
+    body = {"command_name": "version", "params": []}
+    response = requests.post(default_url, json=body)
+    assert response.status_code == 403
+    assert response.json() == {"err": 'command "version" is not allowed',
+                               "code": "command_not_allowed"}
+
+    body = {"command_name": "status", "params": ["unknown_app"]}
+    response = requests.post(default_url, json=body)
+    assert response.status_code == 400
+    assert response.json() == {"err": 'instance "unknown_app" is not enabled',
+                               "code": "unknown_instance"}
+
     body = {"command_name": "stop", "params": ["test_app"]}
     response = requests.post(default_url, json=body)
     assert response.status_code == 200
@@ -303,3 +316,428 @@ def test_daemon_http_requests_with_cfg(tt_cmd, tmpdir_with_cfg):
     # Check that the process was terminated correctly.
     daemon_process_rc = daemon_process.wait(1)
     assert daemon_process_rc == 0
+
+
+def test_daemon_http_requests_auth(tt_cmd, tmpdir):
+    port = utils.find_port()
+    with open(os.path.join(tmpdir, "tt_daemon.yaml"), "w") as tnt_env_file:
+        line = '''
+        daemon:
+            port: {}
+            auth_token: "secret"
+            log_file: "daemon.log"
+        '''.format(port)
+        tnt_env_file.write(line)
+
+    # Start daemon.
+    start_cmd = [tt_cmd, "daemon", "start"]
+    daemon_process = subprocess.Popen(
+        start_cmd,
+        cwd=tmpdir,
+        stderr=subprocess.STDOUT,
+        stdout=subprocess.PIPE,
+        text=True
+    )
+    start_out = daemon_process.stdout.readline()
+    assert re.search(r"Starting tt daemon...", start_out)
+
+    file = utils.wait_file(os.path.join(tmpdir, utils.run_path), 'tt_daemon.pid', [])
+    assert file != ""
+    conn = utils.get_process_conn(os.path.join(tmpdir, utils.run_path, file), port)
+    assert conn is not None
+
+    url = "http://127.0.0.1:" + str(port) + "/tarantool"
+    body = {"command_name": "status", "params": []}
+
+    response = requests.post(url, json=body)
+    assert response.status_code == 401
+    assert response.json() == {"err": "Unauthorized", "code": "unauthorized"}
+
+    response = requests.post(url, json=body, headers={"Authorization": "Bearer wrong"})
+    assert response.status_code == 401
+
+    response = requests.post(url, json=body, headers={"Authorization": "Bearer secret"})
+    assert response.status_code == 200
+
+    with open(os.path.join(tmpdir, utils.log_path, "daemon.log")) as log_file:
+        log = log_file.read()
+        assert re.search(r"Authentication failed: Client IP: 127.0.0.1", log)
+        assert "secret" not in log
+
+    # Stop daemon.
+    stop_cmd = [tt_cmd, "daemon", "stop"]
+    stop_rc, stop_out = utils.run_command_and_get_output(stop_cmd, cwd=tmpdir)
+    assert stop_rc == 0
+    assert re.search(r"The Daemon \(PID = \d+\) has been terminated.", stop_out)
+
+    daemon_process_rc = daemon_process.wait(1)
+    assert daemon_process_rc == 0
+
+
+def test_daemon_rest_api(tt_cmd, tmpdir_with_cfg):
+    tmpdir = tmpdir_with_cfg
+    # Copy the test application to the "run" directory.
+    test_app_path = os.path.join(os.path.dirname(__file__), "test_app", "test_app.lua")
+    shutil.copy(test_app_path, tmpdir)
+
+    # Start daemon.
+    start_cmd = [tt_cmd, "daemon", "start"]
+    daemon_process = subprocess.Popen(
+        start_cmd,
+        cwd=tmpdir,
+        stderr=subprocess.STDOUT,
+        stdout=subprocess.PIPE,
+        text=True
+    )
+    start_out = daemon_process.stdout.readline()
+    assert re.search(r"Starting tt daemon...", start_out)
+
+    file = utils.wait_file(os.path.join(tmpdir, utils.run_path), 'tt_daemon.pid', [])
+    assert file != ""
+
+    base_url = default_url.replace("/tarantool", "")
+    response = requests.get(base_url + "/v1/openapi.json")
+    assert response.status_code == 200
+    assert "/v1/instances" in response.json()["paths"]
+
+    response = requests.get(base_url + "/v1/instances")
+    assert response.status_code == 200
+    instances = response.json()["instances"]
+    assert [inst["name"] for inst in instances] == ["test_app"]
+    assert instances[0]["state"] == "stopped"
+
+    inst_url = base_url + "/v1/instances/test_app/test_app"
+    response = requests.post(inst_url + "/start")
+    assert response.status_code == 202
+    assert response.json()["action"] == "start"
+
+    file = utils.wait_file(os.path.join(tmpdir, utils.run_path, "test_app"), 'test_app.pid', [])
+    assert file != ""
+
+    response = requests.get(inst_url)
+    assert response.status_code == 200
+    assert response.json()["state"] == "running"
+    assert response.json()["pid"] > 0
+
+    response = requests.get(base_url + "/metrics")
+    assert response.status_code == 200
+    labels = '{environment="default",app="test_app",instance="test_app"}'
+    assert 'tt_instance_up' + labels + ' 1' in response.text
+    assert 'tt_instance_watchdog_restarts' + labels + ' 0' in response.text
+    assert re.search(r'tt_daemon_http_requests_total\{handler="/v1/instances",method="GET",'
+                     r'code="200"\} \d+', response.text)
+
+    response = requests.post(inst_url + "/start")
+    assert response.status_code == 409
+    assert response.json() == {"err": "instance test_app is already running",
+                               "code": "already_running"}
+
+    response = requests.get(base_url + "/v1/instances/unknown_app/unknown_app")
+    assert response.status_code == 404
+    assert response.json()["code"] == "not_found"
+
+    response = requests.post(inst_url + "/stop")
+    assert response.status_code == 200
+    assert response.json()["instance"]["state"] != "running"
+
+    # Stop daemon.
+    stop_cmd = [tt_cmd, "daemon", "stop"]
+    stop_rc, stop_out = utils.run_command_and_get_output(stop_cmd, cwd=tmpdir)
+    assert stop_rc == 0
+    assert re.search(r"The Daemon \(PID = \d+\) has been terminated.", stop_out)
+
+    daemon_process_rc = daemon_process.wait(1)
+    assert daemon_process_rc == 0
+
+
+def test_daemon_jobs(tt_cmd, tmpdir_with_cfg):
+    tmpdir = tmpdir_with_cfg
+    # Copy the test application to the "run" directory.
+    test_app_path = os.path.join(os.path.dirname(__file__), "test_app", "test_app.lua")
+    shutil.copy(test_app_path, tmpdir)
+
+    # Start daemon.
+    start_cmd = [tt_cmd, "daemon", "start"]
+    daemon_process = subprocess.Popen(
+        start_cmd,
+        cwd=tmpdir,
+        stderr=subprocess.STDOUT,
+        stdout=subprocess.PIPE,
+        text=True
+    )
+    start_out = daemon_process.stdout.readline()
+    assert re.search(r"Starting tt daemon...", start_out)
+
+    file = utils.wait_file(os.path.join(tmpdir, utils.run_path), 'tt_daemon.pid', [])
+    assert file != ""
+
+    jobs_url = default_url.replace("/tarantool", "/v1/jobs")
+    body = {"command_name": "start", "params": ["test_app"], "timeout": 30}
+    response = requests.post(jobs_url, json=body)
+    assert response.status_code == 202
+    job = response.json()
+    assert job["state"] == "running"
+    assert response.headers["Location"] == "/v1/jobs/" + job["id"]
+
+    # The log stream is finished with the final job state.
+    response = requests.get(jobs_url + "/" + job["id"] + "/log", stream=True)
+    assert response.status_code == 200
+    events = response.text.strip().split("\n\n")
+    assert re.search(r"Starting an instance \[test_app\]", response.text)
+    assert events[-1].startswith("event: end\n")
+
+    response = requests.get(jobs_url + "/" + job["id"])
+    assert response.status_code == 200
+    assert response.json()["state"] == "succeeded"
+    assert response.json()["exit_code"] == 0
+    assert re.search(r"Starting an instance", response.json()["output"])
+
+    response = requests.post(jobs_url + "/" + job["id"] + "/cancel")
+    assert response.status_code == 409
+    assert response.json()["code"] == "job_finished"
+
+    response = requests.post(jobs_url, json={"command_name": "version"})
+    assert response.status_code == 403
+
+    file = utils.wait_file(os.path.join(tmpdir, utils.run_path, "test_app"), 'test_app.pid', [])
+    assert file != ""
+    body = {"command_name": "stop", "params": ["test_app"]}
+    response = requests.post(default_url, json=body)
+    assert response.status_code == 200
+
+    # Stop daemon.
+    stop_cmd = [tt_cmd, "daemon", "stop"]
+    stop_rc, stop_out = utils.run_command_and_get_output(stop_cmd, cwd=tmpdir)
+    assert stop_rc == 0
+    assert re.search(r"The Daemon \(PID = \d+\) has been terminated.", stop_out)
+
+    daemon_process_rc = daemon_process.wait(1)
+    assert daemon_process_rc == 0
+
+
+def test_daemon_environments(tt_cmd, tmpdir):
+    port = utils.find_port()
+    test_app_path = os.path.join(os.path.dirname(__file__), "test_app", "test_app.lua")
+    for env in ["prod", "dev"]:
+        env_dir = os.path.join(tmpdir, env)
+        os.mkdir(env_dir)
+        with open(os.path.join(env_dir, "tt.yaml"), "w") as tt_cfg:
+            tt_cfg.write("tt:\n  app:\n    instances_enabled: .\n")
+        shutil.copy(test_app_path, env_dir)
+
+    with open(os.path.join(tmpdir, "tt_daemon.yaml"), "w") as tnt_env_file:
+        line = '''
+        daemon:
+            port: {}
+            environments:
+                prod: prod/tt.yaml
+                dev: dev/tt.yaml
+        '''.format(port)
+        tnt_env_file.write(line)
+
+    # Start daemon.
+    start_cmd = [tt_cmd, "daemon", "start"]
+    daemon_process = subprocess.Popen(
+        start_cmd,
+        cwd=tmpdir,
+        stderr=subprocess.STDOUT,
+        stdout=subprocess.PIPE,
+        text=True
+    )
+    start_out = daemon_process.stdout.readline()
+    assert re.search(r"Starting tt daemon...", start_out)
+
+    file = utils.wait_file(os.path.join(tmpdir, utils.run_path), 'tt_daemon.pid', [])
+    assert file != ""
+    conn = utils.get_process_conn(os.path.join(tmpdir, utils.run_path, file), port)
+    assert conn is not None
+
+    base_url = "http://127.0.0.1:" + str(port)
+    response = requests.get(base_url + "/v1/environments")
+    assert response.status_code == 200
+    envs = response.json()["environments"]
+    assert [env["name"] for env in envs] == ["dev", "prod"]
+    assert [env["instances"] for env in envs] == [1, 1]
+
+    response = requests.get(base_url + "/v1/instances")
+    assert response.status_code == 200
+    assert [inst["environment"] for inst in response.json()["instances"]] == ["dev", "prod"]
+
+    inst_url = base_url + "/v1/instances/test_app/test_app"
+    response = requests.post(inst_url + "/start")
+    assert response.status_code == 400
+    assert response.json()["code"] == "environment_required"
+
+    response = requests.post(inst_url + "/start?environment=dev")
+    assert response.status_code == 202
+
+    file = utils.wait_file(os.path.join(tmpdir, "dev", utils.run_path, "test_app"),
+                           'test_app.pid', [])
+    assert file != ""
+
+    body = {"command_name": "status", "params": ["test_app"], "environment": "prod"}
+    response = requests.post(base_url + "/tarantool", json=body)
+    assert response.status_code == 200
+    assert re.search(r"test_app: NOT RUNNING", response.json()["res"])
+
+    response = requests.post(inst_url + "/stop?environment=dev")
+    assert response.status_code == 200
+
+    # Stop daemon.
+    stop_cmd = [tt_cmd, "daemon", "stop"]
+    stop_rc, stop_out = utils.run_command_and_get_output(stop_cmd, cwd=tmpdir)
+    assert stop_rc == 0
+    assert re.search(r"The Daemon \(PID = \d+\) has been terminated.", stop_out)
+
+    daemon_process_rc = daemon_process.wait(1)
+    assert daemon_process_rc == 0
+
+
+def test_daemon_supervisor(tt_cmd, tmpdir):
+    port = utils.find_port()
+    test_app_path = os.path.join(os.path.dirname(__file__), "test_app", "test_app.lua")
+    shutil.copy(test_app_path, tmpdir)
+    with open(os.path.join(tmpdir, "tt.yaml"), "w") as tt_cfg:
+        tt_cfg.write("tt:\n  app:\n    instances_enabled: .\n")
+    with open(os.path.join(tmpdir, "tt_daemon.yaml"), "w") as tnt_env_file:
+        line = '''
+        daemon:
+            port: {}
+            supervisor: true
+            supervisor_interval: 1
+        '''.format(port)
+        tnt_env_file.write(line)
+
+    # Start daemon, it starts the enabled instance.
+    start_cmd = [tt_cmd, "daemon", "start"]
+    daemon_process = subprocess.Popen(
+        start_cmd,
+        cwd=tmpdir,
+        stderr=subprocess.STDOUT,
+        stdout=subprocess.PIPE,
+        text=True
+    )
+    start_out = daemon_process.stdout.readline()
+    assert re.search(r"Starting tt daemon...", start_out)
+
+    inst_run_dir = os.path.join(tmpdir, utils.run_path, "test_app")
+    file = utils.wait_file(inst_run_dir, 'test_app.pid', [])
+    assert file != ""
+    pid_file = os.path.join(inst_run_dir, file)
+    with open(pid_file) as f:
+        watchdog_pid = int(f.read())
+
+    # Kill the watchdog, so the PID file remains and the instance is dead.
+    watchdog = psutil.Process(watchdog_pid)
+    children = watchdog.children()
+    watchdog.kill()
+    watchdog.wait(5)
+    utils.kill_procs(children)
+
+    new_pid = watchdog_pid
+    for _ in range(100):
+        try:
+            with open(pid_file) as f:
+                new_pid = int(f.read())
+        except (OSError, ValueError):
+            pass
+        if new_pid != watchdog_pid and psutil.pid_exists(new_pid):
+            break
+        time.sleep(0.1)
+    assert new_pid != watchdog_pid
+
+    with open(os.path.join(tmpdir, utils.log_path, "tt_daemon.log")) as log_file:
+        assert "Supervisor: restarting dead instance test_app" in log_file.read()
+
+    # Kill the tarantool process. The watchdog without restarts exits and
+    # removes the PID file, the instance is restarted by the supervisor.
+    watchdog_pid = new_pid
+    watchdog = psutil.Process(watchdog_pid)
+    for _ in range(100):
+        if watchdog.children():
+            break
+        time.sleep(0.1)
+    tarantool = watchdog.children()[0]
+    tarantool.kill()
+    tarantool.wait(5)
+
+    for _ in range(100):
+        try:
+            with open(pid_file) as f:
+                new_pid = int(f.read())
+        except (OSError, ValueError):
+            pass
+        if new_pid != watchdog_pid and psutil.pid_exists(new_pid):
+            break
+        time.sleep(0.1)
+    assert new_pid != watchdog_pid
+
+    # An intentionally stopped instance is not restarted.
+    stop_rc, _ = utils.run_command_and_get_output([tt_cmd, "stop", "test_app"], cwd=tmpdir)
+    assert stop_rc == 0
+    time.sleep(2)
+    assert not os.path.exists(pid_file)
+    assert os.path.exists(os.path.join(inst_run_dir, "test_app.stopped"))
+
+    # Stop daemon.
+    stop_cmd = [tt_cmd, "daemon", "stop"]
+    stop_rc, stop_out = utils.run_command_and_get_output(stop_cmd, cwd=tmpdir)
+    assert stop_rc == 0
+    assert re.search(r"The Daemon \(PID = \d+\) has been terminated.", stop_out)
+
+    daemon_process_rc = daemon_process.wait(1)
+    assert daemon_process_rc == 0
+
+
+def test_daemon_exec(tt_cmd, tmpdir_with_cfg):
+    tmpdir = tmpdir_with_cfg
+    port = utils.find_port()
+    test_app_path = os.path.join(os.path.dirname(__file__), "test_app", "test_app.lua")
+    shutil.copy(test_app_path, tmpdir)
+    with open(os.path.join(tmpdir, "tt_daemon.yaml"), "w") as tnt_env_file:
+        line = '''
+        daemon:
+            port: {}
+            auth_token: "secret"
+            unix_socket: tt_daemon.sock
+            unix_socket_mode: "0600"
+        '''.format(port)
+        tnt_env_file.write(line)
+
+    # Start daemon.
+    start_cmd = [tt_cmd, "daemon", "start"]
+    daemon_process = subprocess.Popen(
+        start_cmd,
+        cwd=tmpdir,
+        stderr=subprocess.STDOUT,
+        stdout=subprocess.PIPE,
+        text=True
+    )
+    start_out = daemon_process.stdout.readline()
+    assert re.search(r"Starting tt daemon...", start_out)
+
+    socket_path = os.path.join(tmpdir, "tt_daemon.sock")
+    file = utils.wait_file(tmpdir, "tt_daemon.sock", [])
+    assert file != ""
+    assert os.stat(socket_path).st_mode & 0o777 == 0o600
+
+    # Requests to the socket are not authenticated.
+    exec_cmd = [tt_cmd, "daemon", "exec", "status", "test_app"]
+    exec_rc, exec_out = utils.run_command_and_get_output(exec_cmd, cwd=tmpdir)
+    assert exec_rc == 0
+    assert re.search(r"test_app: NOT RUNNING", exec_out)
+
+    exec_cmd = [tt_cmd, "daemon", "exec", "version"]
+    exec_rc, exec_out = utils.run_command_and_get_output(exec_cmd, cwd=tmpdir)
+    assert exec_rc == 1
+    assert re.search(r'command "version" is not allowed \(command_not_allowed\)', exec_out)
+
+    # Stop daemon.
+    stop_cmd = [tt_cmd, "daemon", "stop"]
+    stop_rc, stop_out = utils.run_command_and_get_output(stop_cmd, cwd=tmpdir)
+    assert stop_rc == 0
+    assert re.search(r"The Daemon \(PID = \d+\) has been terminated.", stop_out)
+    assert not os.path.exists(socket_path)
+
+    daemon_process_rc = daemon_process.wait(1)
+    assert daemon_process_rc == 0
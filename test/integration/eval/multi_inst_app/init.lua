@@ -0,0 +1,5 @@
+local fiber = require('fiber')
+
+while true do
+    fiber.sleep(1)
+end
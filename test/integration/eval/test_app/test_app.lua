@@ -0,0 +1,11 @@
+local fiber = require('fiber')
+
+box.cfg({})
+
+box.schema.space.create('test', { if_not_exists = true })
+box.space.test:create_index('pk', { if_not_exists = true })
+box.space.test:replace({1, 'one'})
+
+while true do
+    fiber.sleep(5)
+end
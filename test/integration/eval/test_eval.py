@@ -0,0 +1,144 @@
+import json
+import os
+import re
+import shutil
+import subprocess
+import tempfile
+
+import pytest
+
+from utils import run_command_and_get_output, run_path, wait_file
+
+
+@pytest.fixture
+def test_app(tt_cmd, tmpdir_with_cfg):
+    tmpdir = tmpdir_with_cfg
+    test_app_path = os.path.join(os.path.dirname(__file__), "test_app", "test_app.lua")
+    shutil.copy(test_app_path, tmpdir)
+
+    # Start an instance.
+    start_cmd = [tt_cmd, "start", "test_app"]
+    rc, output = run_command_and_get_output(start_cmd, cwd=tmpdir)
+    assert rc == 0
+
+    # Check for start.
+    file = wait_file(os.path.join(tmpdir, run_path, "test_app"), 'test_app.control', [])
+    assert file != ""
+
+    yield tmpdir
+
+    # Stop the Instance.
+    run_command_and_get_output([tt_cmd, "stop", "test_app"], cwd=tmpdir)
+
+
+def run_eval(tt_cmd, tmpdir, args, stdin=None):
+    process = subprocess.run(
+        [tt_cmd, "eval", "test_app"] + args,
+        cwd=tmpdir,
+        input=stdin,
+        stderr=subprocess.PIPE,
+        stdout=subprocess.PIPE,
+        text=True,
+    )
+    return process.returncode, process.stdout, process.stderr
+
+
+def test_eval_formats(tt_cmd, test_app):
+    rc, out, _ = run_eval(tt_cmd, test_app, ["-e", "box.space.test:get(1)", "--format", "json"])
+    assert rc == 0
+    assert json.loads(out) == [[1, "one"]]
+
+    rc, out, _ = run_eval(tt_cmd, test_app, ["-e", "return 1, nil, 'x'"])
+    assert rc == 0
+    assert out == "---\n- 1\n- null\n- x\n...\n"
+
+    rc, out, _ = run_eval(tt_cmd, test_app, ["-e", "return 1", "--format", "msgpack-hex"])
+    assert rc == 0
+    assert out == "9101\n"
+
+    rc, out, _ = run_eval(tt_cmd, test_app, ["-e", "return 1", "--format", "xml"])
+    assert rc != 0
+
+
+def test_eval_args(tt_cmd, test_app):
+    args = ["-e", "local args = ... return args.a + args.b, args.s",
+            "--arg", "a=1", "--arg", "b=2", "--arg", "s=str", "--format", "json"]
+    rc, out, _ = run_eval(tt_cmd, test_app, args)
+    assert rc == 0
+    assert json.loads(out) == [3, "str"]
+
+    rc, out, _ = run_eval(tt_cmd, test_app, ["--format", "json"], stdin="return 2 + 2")
+    assert rc == 0
+    assert json.loads(out) == [4]
+
+
+def test_eval_error(tt_cmd, test_app):
+    expr = "local function f() error('custom error') end f()"
+    rc, out, err = run_eval(tt_cmd, test_app, ["-e", expr])
+    assert rc != 0
+    assert out == ""
+    assert re.search(r"custom error", err)
+    assert re.search(r"stack traceback:", err)
+
+    rc, out, err = run_eval(tt_cmd, test_app, ["-e", "return ("])
+    assert rc != 0
+    assert re.search(r"unexpected symbol", err)
+
+
+@pytest.fixture
+def multi_inst_app(tt_cmd):
+    test_app_path_src = os.path.join(os.path.dirname(__file__), "multi_inst_app")
+
+    # Default temporary directory may have very long path. This can cause socket path buffer
+    # overflow. Create our own temporary directory.
+    with tempfile.TemporaryDirectory() as tmpdir:
+        test_app_path = os.path.join(tmpdir, "app")
+        shutil.copytree(test_app_path_src, test_app_path)
+
+        rc, _ = run_command_and_get_output([tt_cmd, "start", "app"], cwd=test_app_path)
+        assert rc == 0
+        for inst_name in ["router", "storage1", "storage2"]:
+            file = wait_file(os.path.join(test_app_path, run_path, "app", inst_name),
+                             inst_name + ".control", [])
+            assert file != ""
+
+        yield test_app_path
+
+        run_command_and_get_output([tt_cmd, "stop", "app"], cwd=test_app_path)
+
+
+def test_eval_all(tt_cmd, multi_inst_app):
+    expr = "return os.getenv('TARANTOOL_INSTANCE_NAME')"
+    cmd = [tt_cmd, "eval", "app", "--all", "-e", expr, "--format", "json"]
+    rc, output = run_command_and_get_output(cmd, cwd=multi_inst_app)
+    assert rc == 0
+    assert json.loads(output) == {
+        "app:router": {"results": ["router"]},
+        "app:storage1": {"results": ["storage1"]},
+        "app:storage2": {"results": ["storage2"]},
+    }
+
+    cmd = [tt_cmd, "eval", "app:storage*", "-e", expr]
+    rc, output = run_command_and_get_output(cmd, cwd=multi_inst_app)
+    assert rc == 0
+    assert output == "# app:storage1\n---\n- storage1\n...\n" \
+                     "# app:storage2\n---\n- storage2\n...\n"
+
+    cmd = [tt_cmd, "eval", "app:unknown*", "-e", expr]
+    rc, output = run_command_and_get_output(cmd, cwd=multi_inst_app)
+    assert rc != 0
+    assert re.search(r'no instances matching "app:unknown\*" found', output)
+
+
+def test_eval_all_errors(tt_cmd, multi_inst_app):
+    expr = "if os.getenv('TARANTOOL_INSTANCE_NAME') == 'storage2' then error('failed') end" \
+           " return true"
+    cmd = [tt_cmd, "eval", "app", "--all", "-e", expr, "--format", "json"]
+    process = subprocess.run(cmd, cwd=multi_inst_app, stderr=subprocess.PIPE,
+                             stdout=subprocess.PIPE, text=True)
+    assert process.returncode != 0
+    output = json.loads(process.stdout)
+    assert output["app:router"] == {"results": [True]}
+    assert output["app:storage1"] == {"results": [True]}
+    assert re.search(r"failed", output["app:storage2"]["error"])
+    assert re.search(r"the evaluation failed on 1 of 3 instances", process.stderr)
@@ -0,0 +1,23 @@
+local fiber = require('fiber')
+
+box.cfg({})
+
+for _, name in ipairs({'source', 'target'}) do
+    box.schema.space.create(name, {
+        if_not_exists = true,
+        format = {{'id', 'unsigned'}, {'name', 'string', is_nullable = true}},
+    })
+    box.space[name]:create_index('pk', {if_not_exists = true})
+end
+box.space.source:create_index('name', {
+    if_not_exists = true,
+    unique = false,
+    parts = {{'name', 'string', is_nullable = true}},
+})
+box.space.source:replace({1, 'one'})
+box.space.source:replace({2, 'two, "quoted"'})
+box.space.source:replace({3, box.NULL})
+
+while true do
+    fiber.sleep(5)
+end
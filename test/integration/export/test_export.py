@@ -0,0 +1,95 @@
+import json
+import os
+import shutil
+import subprocess
+
+import pytest
+
+from utils import run_command_and_get_output, run_path, wait_file
+
+
+@pytest.fixture
+def test_app(tt_cmd, tmpdir_with_cfg):
+    tmpdir = tmpdir_with_cfg
+    test_app_path = os.path.join(os.path.dirname(__file__), "test_app.lua")
+    shutil.copy(test_app_path, tmpdir)
+
+    # Start an instance.
+    start_cmd = [tt_cmd, "start", "test_app"]
+    rc, output = run_command_and_get_output(start_cmd, cwd=tmpdir)
+    assert rc == 0
+
+    # Check for start.
+    file = wait_file(os.path.join(tmpdir, run_path, "test_app"), 'test_app.control', [])
+    assert file != ""
+
+    yield tmpdir
+
+    # Stop the Instance.
+    run_command_and_get_output([tt_cmd, "stop", "test_app"], cwd=tmpdir)
+
+
+def export_space(tt_cmd, cwd, space, *flags):
+    cmd = [tt_cmd, "export", "test_app", space, *flags]
+    process = subprocess.run(cmd, cwd=cwd, stdout=subprocess.PIPE, stderr=subprocess.PIPE)
+    return process.returncode, process.stdout, process.stderr.decode("utf-8")
+
+
+def test_export_csv(tt_cmd, test_app):
+    rc, output, _ = export_space(tt_cmd, test_app, "source")
+    assert rc == 0
+    assert output.decode("utf-8") == 'id,name\n1,one\n2,"two, ""quoted"""\n3,\n'
+
+    rc, output, _ = export_space(tt_cmd, test_app, "source", "--where", "name:one")
+    assert rc == 0
+    assert output.decode("utf-8") == "id,name\n1,one\n"
+
+    rc, _, stderr = export_space(tt_cmd, test_app, "source", "--where", "unknown:1")
+    assert rc != 0
+    assert "index unknown does not exist in space source" in stderr
+
+
+def test_export_jsonl(tt_cmd, test_app):
+    rc, output, _ = export_space(tt_cmd, test_app, "source", "--format", "jsonl")
+    assert rc == 0
+    assert [json.loads(line) for line in output.decode("utf-8").splitlines()] == [
+        {"id": 1, "name": "one"},
+        {"id": 2, "name": 'two, "quoted"'},
+        {"id": 3, "name": None},
+    ]
+
+
+@pytest.mark.parametrize("data_format", ["csv", "jsonl", "msgpack"])
+def test_export_import(tt_cmd, test_app, data_format):
+    rc, output, _ = export_space(tt_cmd, test_app, "source", "--format", data_format)
+    assert rc == 0
+    data_path = os.path.join(test_app, "source." + data_format)
+    with open(data_path, "wb") as f:
+        f.write(output)
+
+    cmd = [tt_cmd, "import", "test_app", "target", data_path, "--format", data_format]
+    rc, output = run_command_and_get_output(cmd, cwd=test_app)
+    assert rc == 0
+    assert "Imported 3 tuples, skipped 0 tuples" in output
+
+    rc, output, _ = export_space(tt_cmd, test_app, "target", "--format", "jsonl")
+    assert rc == 0
+    assert [json.loads(line) for line in output.decode("utf-8").splitlines()] == [
+        {"id": 1, "name": "one"},
+        {"id": 2, "name": 'two, "quoted"'},
+        {"id": 3, "name": None},
+    ]
+
+    # Conflicts with the imported tuples.
+    cmd = [tt_cmd, "import", "test_app", "target", data_path, "--format", data_format]
+    rc, output = run_command_and_get_output(cmd, cwd=test_app)
+    assert rc != 0
+    assert "failed to import records 1-3" in output
+
+    rc, output = run_command_and_get_output(cmd + ["--on-conflict", "skip"], cwd=test_app)
+    assert rc == 0
+    assert "Imported 0 tuples, skipped 3 tuples" in output
+
+    rc, output = run_command_and_get_output(cmd + ["--on-conflict", "replace"], cwd=test_app)
+    assert rc == 0
+    assert "Imported 3 tuples, skipped 0 tuples" in output
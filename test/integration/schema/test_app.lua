@@ -0,0 +1,22 @@
+local fiber = require('fiber')
+
+box.cfg({})
+
+box.schema.space.create('test', {
+    if_not_exists = true,
+    format = {{'id', 'unsigned'}, {'name', 'string', is_nullable = true}},
+})
+box.space.test:create_index('pk', {if_not_exists = true})
+box.space.test:create_index('name', {
+    if_not_exists = true,
+    unique = false,
+    parts = {{'name', 'string', is_nullable = true}},
+})
+box.space.test:replace({1, 'one'})
+box.space.test:replace({2, 'two'})
+
+box.schema.space.create('empty', {if_not_exists = true})
+
+while true do
+    fiber.sleep(5)
+end
@@ -0,0 +1,78 @@
+import json
+import os
+import shutil
+
+import pytest
+import yaml
+
+from utils import run_command_and_get_output, run_path, wait_file
+
+
+@pytest.fixture
+def test_app(tt_cmd, tmpdir_with_cfg):
+    tmpdir = tmpdir_with_cfg
+    test_app_path = os.path.join(os.path.dirname(__file__), "test_app.lua")
+    shutil.copy(test_app_path, tmpdir)
+
+    # Start an instance.
+    start_cmd = [tt_cmd, "start", "test_app"]
+    rc, output = run_command_and_get_output(start_cmd, cwd=tmpdir)
+    assert rc == 0
+
+    # Check for start.
+    file = wait_file(os.path.join(tmpdir, run_path, "test_app"), 'test_app.control', [])
+    assert file != ""
+
+    yield tmpdir
+
+    # Stop the Instance.
+    run_command_and_get_output([tt_cmd, "stop", "test_app"], cwd=tmpdir)
+
+
+def test_schema_spaces(tt_cmd, test_app):
+    cmd = [tt_cmd, "schema", "spaces", "test_app", "--format", "json"]
+    rc, output = run_command_and_get_output(cmd, cwd=test_app)
+    assert rc == 0
+    spaces = {space["name"]: space for space in json.loads(output)}
+    assert sorted(spaces.keys()) == ["empty", "test"]
+    assert spaces["test"]["engine"] == "memtx"
+    assert spaces["test"]["len"] == 2
+    assert spaces["test"]["bsize"] > 0
+
+    cmd = [tt_cmd, "schema", "spaces", "test_app", "--show-system"]
+    rc, output = run_command_and_get_output(cmd, cwd=test_app)
+    assert rc == 0
+    assert output.startswith("NAME")
+    assert "_space" in output
+
+
+def test_schema_indexes_and_format(tt_cmd, test_app):
+    cmd = [tt_cmd, "schema", "indexes", "test_app", "test"]
+    rc, output = run_command_and_get_output(cmd, cwd=test_app)
+    assert rc == 0
+    lines = output.splitlines()
+    assert lines[2].split() == ["pk", "0", "TREE", "true", "id", "unsigned"]
+    assert lines[3].split() == ["name", "1", "TREE", "false", "name", "string", "nullable"]
+
+    cmd = [tt_cmd, "schema", "format", "test_app", "test", "--format", "json"]
+    rc, output = run_command_and_get_output(cmd, cwd=test_app)
+    assert rc == 0
+    assert json.loads(output) == [
+        {"name": "id", "type": "unsigned", "is_nullable": False},
+        {"name": "name", "type": "string", "is_nullable": True},
+    ]
+
+    cmd = [tt_cmd, "schema", "format", "test_app", "unknown"]
+    rc, output = run_command_and_get_output(cmd, cwd=test_app)
+    assert rc != 0
+    assert "space unknown does not exist" in output
+
+
+def test_schema_dump(tt_cmd, test_app):
+    cmd = [tt_cmd, "schema", "dump", "test_app"]
+    rc, output = run_command_and_get_output(cmd, cwd=test_app)
+    assert rc == 0
+    dump = yaml.safe_load(output)
+    assert list(dump["spaces"].keys()) == ["empty", "test"]
+    assert "id" not in dump["spaces"]["test"]
+    assert [index["name"] for index in dump["spaces"]["test"]["indexes"]] == ["pk", "name"]
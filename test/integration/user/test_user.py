@@ -0,0 +1,87 @@
+import json
+import os
+import shutil
+import subprocess
+
+import pytest
+
+from utils import run_command_and_get_output, run_path, wait_file
+
+
+@pytest.fixture
+def test_app(tt_cmd, tmpdir_with_cfg):
+    tmpdir = tmpdir_with_cfg
+    test_app_path = os.path.join(os.path.dirname(__file__), "test_app.lua")
+    shutil.copy(test_app_path, tmpdir)
+
+    # Start an instance.
+    start_cmd = [tt_cmd, "start", "test_app"]
+    rc, output = run_command_and_get_output(start_cmd, cwd=tmpdir)
+    assert rc == 0
+
+    # Check for start.
+    file = wait_file(os.path.join(tmpdir, run_path, "test_app"), 'test_app.control', [])
+    assert file != ""
+
+    yield tmpdir
+
+    # Stop the Instance.
+    run_command_and_get_output([tt_cmd, "stop", "test_app"], cwd=tmpdir)
+
+
+def list_users(tt_cmd, cwd):
+    cmd = [tt_cmd, "user", "list", "test_app", "--format", "json"]
+    rc, output = run_command_and_get_output(cmd, cwd=cwd)
+    assert rc == 0
+    return {user["name"]: user for user in json.loads(output)}
+
+
+def test_user(tt_cmd, test_app):
+    # The password is read from stdin.
+    process = subprocess.run([tt_cmd, "user", "create", "test_app", "alice"],
+                             cwd=test_app, input=b"secret\n",
+                             stdout=subprocess.PIPE, stderr=subprocess.STDOUT)
+    assert process.returncode == 0
+    assert "User \"alice\" is created" in process.stdout.decode("utf-8")
+
+    users = list_users(tt_cmd, test_app)
+    assert users["alice"]["type"] == "user"
+    assert users["admin"]["type"] == "user"
+    assert users["super"]["type"] == "role"
+
+    cmd = [tt_cmd, "user", "grant", "test_app", "alice", "read,write", "space", "test"]
+    rc, output = run_command_and_get_output(cmd, cwd=test_app)
+    assert rc == 0
+    privileges = list_users(tt_cmd, test_app)["alice"]["privileges"]
+    assert {"privileges": "read,write", "object_type": "space",
+            "object_name": "test"} in privileges
+
+    cmd = [tt_cmd, "user", "list", "test_app"]
+    rc, output = run_command_and_get_output(cmd, cwd=test_app)
+    assert rc == 0
+    assert output.startswith("NAME")
+    assert "alice  user  read,write" in output
+
+    cmd = [tt_cmd, "user", "revoke", "test_app", "alice", "write", "space", "test"]
+    rc, output = run_command_and_get_output(cmd, cwd=test_app)
+    assert rc == 0
+    privileges = list_users(tt_cmd, test_app)["alice"]["privileges"]
+    assert {"privileges": "read", "object_type": "space",
+            "object_name": "test"} in privileges
+
+    process = subprocess.run([tt_cmd, "user", "passwd", "test_app", "alice"],
+                             cwd=test_app, input=b"new_secret\n")
+    assert process.returncode == 0
+
+    cmd = [tt_cmd, "user", "drop", "test_app", "alice"]
+    rc, output = run_command_and_get_output(cmd, cwd=test_app)
+    assert rc == 0
+    assert "alice" not in list_users(tt_cmd, test_app)
+
+    cmd = [tt_cmd, "user", "drop", "test_app", "alice"]
+    rc, output = run_command_and_get_output(cmd, cwd=test_app)
+    assert rc != 0
+
+    cmd = [tt_cmd, "user", "drop", "test_app", "alice", "--if-exists"]
+    rc, output = run_command_and_get_output(cmd, cwd=test_app)
+    assert rc == 0
@@ -0,0 +1,9 @@
+local fiber = require('fiber')
+
+box.cfg({})
+
+box.broadcast('test_key', {value = 0})
+
+while true do
+    fiber.sleep(5)
+end
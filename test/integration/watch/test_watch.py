@@ -0,0 +1,60 @@
+import json
+import os
+import shutil
+import subprocess
+import time
+
+import pytest
+
+from utils import run_command_and_get_output, run_path, wait_file
+
+
+def tarantool_supports_watch():
+    output = subprocess.run(["tarantool", "--version"], stdout=subprocess.PIPE, text=True)
+    version = output.stdout.split()[1].split("-")[0]
+    major, minor = [int(part) for part in version.split(".")[:2]]
+    return (major, minor) >= (2, 10)
+
+
+@pytest.fixture
+def test_app(tt_cmd, tmpdir_with_cfg):
+    tmpdir = tmpdir_with_cfg
+    test_app_path = os.path.join(os.path.dirname(__file__), "test_app.lua")
+    shutil.copy(test_app_path, tmpdir)
+
+    # Start an instance.
+    start_cmd = [tt_cmd, "start", "test_app"]
+    rc, output = run_command_and_get_output(start_cmd, cwd=tmpdir)
+    assert rc == 0
+
+    # Check for start.
+    file = wait_file(os.path.join(tmpdir, run_path, "test_app"), 'test_app.control', [])
+    assert file != ""
+
+    yield tmpdir
+
+    # Stop the Instance.
+    run_command_and_get_output([tt_cmd, "stop", "test_app"], cwd=tmpdir)
+
+
+@pytest.mark.skipif(not tarantool_supports_watch(), reason="Tarantool 2.10+ is required")
+def test_watch(tt_cmd, test_app):
+    watch_cmd = [tt_cmd, "watch", "test_app", "test_key", "--format", "json"]
+    process = subprocess.Popen(watch_cmd, cwd=test_app, stdout=subprocess.PIPE, text=True)
+
+    first = json.loads(process.stdout.readline())
+    assert first["key"] == "test_key"
+    assert first["value"] == {"value": 0}
+    assert first["timestamp"] != ""
+
+    # Wait until the watcher is acknowledged.
+    time.sleep(0.2)
+    eval_cmd = [tt_cmd, "eval", "test_app", "-e", "box.broadcast('test_key', {value = 1})"]
+    rc, _ = run_command_and_get_output(eval_cmd, cwd=test_app)
+    assert rc == 0
+
+    second = json.loads(process.stdout.readline())
+    assert second["value"] == {"value": 1}
+
+    process.terminate()
+    assert process.wait(timeout=5) == 0